@@ -4,40 +4,286 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/calindra/rollups-server/src/admin"
+	"github.com/calindra/rollups-server/src/claimer"
 	"github.com/calindra/rollups-server/src/container"
+	"github.com/calindra/rollups-server/src/decoder"
 	"github.com/calindra/rollups-server/src/devnet"
+	"github.com/calindra/rollups-server/src/epoch"
+	"github.com/calindra/rollups-server/src/inspect"
+	"github.com/calindra/rollups-server/src/migrations"
 	"github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/proof"
+	"github.com/calindra/rollups-server/src/query"
+	"github.com/calindra/rollups-server/src/reader"
 	"github.com/calindra/rollups-server/src/rollup"
 	"github.com/calindra/rollups-server/src/sequencer"
 	"github.com/calindra/rollups-server/src/sequencer/inputter"
+	"github.com/calindra/rollups-server/src/simulator"
 	"github.com/calindra/rollups-server/src/supervisor"
+	"github.com/calindra/rollups-server/src/util"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+const DefaultHttpAddress = "127.0.0.1"
 const DefaultRollupsPort = 5004
 const HttpTimeout = 10 * time.Second
+const DefaultSQLiteDsn = "sqlite3"
+
+// Prefix of the environment variables that set the flags.
+// The variable of the flag --http-port is ROLLUPS_SERVER_HTTP_PORT, for instance.
+const EnvPrefix = "ROLLUPS_SERVER_"
 
 var startupMessage = `
-Http Rollups for development started at http://localhost:5004
+Http Rollups for development started at http://%s:%d
 Press Ctrl+C to stop the server
 `
 
-func main() {
+// Options of the rollups server.
+type Opts struct {
+	HttpAddress           string
+	HttpPort              int
+	AnvilAddress          string
+	AnvilPort             int
+	AnvilVerbose          bool
+	DisableAnvil          bool
+	DisableClaimer        bool
+	RpcUrl                string
+	RpcPolling            bool
+	RpcPollInterval       time.Duration
+	InputBoxAddress       string
+	InputBoxBlock         uint64
+	InputterBlockRange    uint64
+	InputterConfirmations uint64
+	InputterRewindOnReorg bool
+	ApplicationAddress    string
+	DbDriver              string
+	DbDsn                 string
+	MigrateDryRun         bool
+	AbiDir                string
+	FinishTimeout         time.Duration
+	InspectTimeout        time.Duration
+	InspectRetention      time.Duration
+	EpochBlocks           uint64
+	EpochDuration         time.Duration
+	LogLevel              string
+}
+
+// Create the options with the default values.
+func NewOpts() Opts {
+	return Opts{
+		HttpAddress:        DefaultHttpAddress,
+		HttpPort:           DefaultRollupsPort,
+		AnvilAddress:       devnet.AnvilDefaultAddress,
+		AnvilPort:          devnet.AnvilDefaultPort,
+		AnvilVerbose:       false,
+		DisableAnvil:       false,
+		DisableClaimer:     false,
+		RpcUrl:             "",
+		RpcPolling:         false,
+		RpcPollInterval:    inputter.DefaultPollInterval,
+		InputBoxAddress:    devnet.InputBoxAddress,
+		InputBoxBlock:      0,
+		InputterBlockRange: inputter.DefaultBlockRange,
+		ApplicationAddress: devnet.ApplicationAddress,
+		DbDriver:           migrations.DriverSQLite,
+		DbDsn:              DefaultSQLiteDsn,
+		MigrateDryRun:      false,
+		AbiDir:             "",
+		FinishTimeout:      rollup.DefaultFinishTimeout,
+		InspectTimeout:     inspect.DefaultInspectTimeout,
+		InspectRetention:   model.DefaultInspectRetention,
+		EpochBlocks:        0,
+		EpochDuration:      model.DefaultEpochDuration,
+		LogLevel:           "info",
+	}
+}
+
+var opts = NewOpts()
+
+var cmd = &cobra.Command{
+	Use:   "rollups-server",
+	Short: "Run the http rollups server for development",
+	Long: "Run the http rollups server for development.\n" +
+		"Every flag can also be set with an environment variable; for instance, " +
+		"--http-port can be set with " + envName("http-port") + ".",
+	SilenceUsage: true,
+	// Run for the subcommands as well, so their flags and the log level are configured too.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := bindEnv(cmd.Flags())
+		if err != nil {
+			return err
+		}
+		var level slog.Level
+		err = level.UnmarshalText([]byte(opts.LogLevel))
+		if err != nil {
+			return fmt.Errorf("invalid log level %s", opts.LogLevel)
+		}
+		util.ConfigureLog(level)
+		return nil
+	},
+	RunE: run,
+}
+
+// Options of the execute-voucher command.
+type ExecuteVoucherOpts struct {
+	Url     string
+	Account int
+}
+
+var executeVoucherOpts = ExecuteVoucherOpts{
+	Url:     fmt.Sprintf("http://%s:%d", DefaultHttpAddress, DefaultRollupsPort),
+	Account: 0,
+}
+
+var executeVoucherCmd = &cobra.Command{
+	Use:   "execute-voucher <input index> <output index>",
+	Short: "Execute a voucher in the devnet",
+	Long: "Execute a voucher in the devnet with one of the test accounts.\n" +
+		"The command calls the admin API of a running rollups server, " +
+		"which loads the voucher proof and calls executeOutput in the application contract.",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runExecuteVoucher,
+}
+
+func init() {
+	cmd.AddCommand(executeVoucherCmd)
+	executeVoucherFlags := executeVoucherCmd.Flags()
+	executeVoucherFlags.StringVar(&executeVoucherOpts.Url, "url", executeVoucherOpts.Url,
+		"URL of the rollups server")
+	executeVoucherFlags.IntVar(&executeVoucherOpts.Account, "account", executeVoucherOpts.Account,
+		fmt.Sprintf("index of the test account that sends the transaction, from 0 to %d",
+			len(devnet.TestPrivateKeys)-1))
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.HttpAddress, "http-address", opts.HttpAddress, "HTTP address of the rollups server")
+	flags.IntVar(&opts.HttpPort, "http-port", opts.HttpPort, "HTTP port of the rollups server")
+	flags.StringVar(&opts.AnvilAddress, "anvil-address", opts.AnvilAddress, "address of the embedded anvil")
+	flags.IntVar(&opts.AnvilPort, "anvil-port", opts.AnvilPort, "port of the embedded anvil")
+	flags.BoolVar(&opts.AnvilVerbose, "anvil-verbose", opts.AnvilVerbose, "show the anvil logs")
+	flags.BoolVar(&opts.DisableAnvil, "disable-anvil", opts.DisableAnvil,
+		"do not start the embedded anvil; use --rpc-url to connect to an external node")
+	flags.BoolVar(&opts.DisableClaimer, "disable-claimer", opts.DisableClaimer,
+		"do not submit the claims of the proved epochs to the embedded anvil")
+	flags.StringVar(&opts.RpcUrl, "rpc-url", opts.RpcUrl,
+		"websocket or HTTP RPC URL of the node that the inputter reads; defaults to the embedded anvil")
+	flags.BoolVar(&opts.RpcPolling, "rpc-polling", opts.RpcPolling,
+		"poll the node for new blocks instead of subscribing to them; always set for HTTP URLs")
+	flags.DurationVar(&opts.RpcPollInterval, "rpc-poll-interval", opts.RpcPollInterval,
+		"interval between checks for new blocks when polling the node")
+	flags.StringVar(&opts.InputBoxAddress, "contracts-input-box-address", opts.InputBoxAddress,
+		"address of the InputBox contract")
+	flags.Uint64Var(&opts.InputBoxBlock, "contracts-input-box-block", opts.InputBoxBlock,
+		"block in which the InputBox contract was deployed")
+	flags.Uint64Var(&opts.InputterBlockRange, "inputter-block-range", opts.InputterBlockRange,
+		"maximum number of blocks that the inputter and the execution watcher read in each eth_getLogs request")
+	flags.Uint64Var(&opts.InputterConfirmations, "inputter-confirmations", opts.InputterConfirmations,
+		"number of blocks on top of a block before the inputter reads its inputs")
+	flags.BoolVar(&opts.InputterRewindOnReorg, "inputter-rewind-on-reorg", opts.InputterRewindOnReorg,
+		"rewind the inputs to the fork block when the inputter detects a chain reorganization")
+	flags.StringVar(&opts.ApplicationAddress, "contracts-application-address", opts.ApplicationAddress,
+		"address of the application contract")
+	flags.StringVar(&opts.DbDriver, "db-driver", opts.DbDriver,
+		fmt.Sprintf("database driver, either %s or %s", migrations.DriverSQLite, migrations.DriverPostgres))
+	flags.StringVar(&opts.DbDsn, "db-dsn", opts.DbDsn, "database data source name")
+	flags.BoolVar(&opts.MigrateDryRun, "migrate-dry-run", opts.MigrateDryRun,
+		"list the pending database migrations and exit")
+	flags.StringVar(&opts.AbiDir, "abi-dir", opts.AbiDir,
+		"directory of the ABIs that decode the outputs; the registered ABIs are only kept in memory if not set")
+	flags.DurationVar(&opts.FinishTimeout, "finish-timeout", opts.FinishTimeout,
+		"maximum time that /finish waits for a new input")
+	flags.DurationVar(&opts.InspectTimeout, "inspect-timeout", opts.InspectTimeout,
+		"maximum time that /inspect waits for the DApp to finish the inspect")
+	flags.DurationVar(&opts.InspectRetention, "inspect-retention", opts.InspectRetention,
+		"time that finished inspect inputs are kept in the database")
+	flags.Uint64Var(&opts.EpochBlocks, "epoch-blocks", opts.EpochBlocks,
+		"close the epoch when an input arrives this many blocks after its first input; 0 disables it")
+	flags.DurationVar(&opts.EpochDuration, "epoch-duration", opts.EpochDuration,
+		"close the epoch this long after it was opened; 0 disables it")
+	cmd.PersistentFlags().StringVar(&opts.LogLevel, "log-level", opts.LogLevel,
+		"log level: debug, info, warn or error")
+}
+
+// Get the name of the environment variable of the flag.
+func envName(flag string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// Set the flags that were not given in the command line from the environment variables.
+func bindEnv(flags *pflag.FlagSet) error {
+	var err error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed {
+			return
+		}
+		value, ok := os.LookupEnv(envName(flag.Name))
+		if !ok {
+			return
+		}
+		if setErr := flags.Set(flag.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q for %s: %w", value, envName(flag.Name), setErr)
+		}
+	})
+	return err
+}
+
+func run(cmd *cobra.Command, args []string) error {
 	startTime := time.Now()
+	for name, address := range map[string]string{
+		"contracts-input-box-address":   opts.InputBoxAddress,
+		"contracts-application-address": opts.ApplicationAddress,
+	} {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid address %q for --%s", address, name)
+		}
+	}
+	if opts.DisableAnvil && opts.RpcUrl == "" {
+		return fmt.Errorf("--rpc-url is required when the anvil is disabled")
+	}
+
 	var w supervisor.SupervisorWorker
-	db := sqlx.MustConnect("sqlite3", "sqlite3")
+	db, err := sqlx.Connect(opts.DbDriver, opts.DbDsn)
+	if err != nil {
+		return fmt.Errorf("database connection: %w", err)
+	}
 	container := container.NewContainer(*db)
-	decoder := container.GetOutputDecoder()
+	pending, err := container.Migrate(cmd.Context(), opts.MigrateDryRun)
+	if err != nil {
+		return fmt.Errorf("database migration: %w", err)
+	}
+	if opts.MigrateDryRun {
+		for _, migration := range pending {
+			fmt.Printf("%d %s\n", migration.Version, migration.Name)
+		}
+		return nil
+	}
+	outputDecoder := container.GetOutputDecoder()
+	if opts.AbiDir != "" {
+		err = outputDecoder.Registry().Load(opts.AbiDir)
+		if err != nil {
+			return fmt.Errorf("load abis: %w", err)
+		}
+	}
 
-	modelInstance := model.NewAppModel(decoder, db)
+	modelInstance := model.NewAppModel(outputDecoder, db)
+	modelInstance.InspectRetention = opts.InspectRetention
+	modelInstance.EpochBlockCount = opts.EpochBlocks
+	modelInstance.EpochDuration = opts.EpochDuration
 
 	e := echo.New()
 	e.Use(middleware.CORS())
@@ -46,45 +292,148 @@ func main() {
 		Format: `${time_rfc3339} ${remote_ip} ${method} ${uri} ${status} ${latency_human}` + "\n",
 	}))
 	e.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
+		// /finish and /inspect are long-polls with their own timeouts
+		Skipper: func(c echo.Context) bool {
+			switch c.Path() {
+			case "/finish", "/inspect", "/inspect/:payload":
+				return true
+			}
+			return false
+		},
 		ErrorMessage: "Request timed out",
 		Timeout:      HttpTimeout,
 	}))
 
 	inputBoxSequencer := sequencer.NewInputBoxSequencer(modelInstance)
 
-	w.Workers = append(w.Workers, devnet.AnvilWorker{
-		Address: devnet.AnvilDefaultAddress,
-		Port:    devnet.AnvilDefaultPort,
-		Verbose: false,
+	rpcUrl := opts.RpcUrl
+	if !opts.DisableAnvil {
+		w.Workers = append(w.Workers, devnet.AnvilWorker{
+			Address: opts.AnvilAddress,
+			Port:    opts.AnvilPort,
+			Verbose: opts.AnvilVerbose,
+		})
+		if rpcUrl == "" {
+			rpcUrl = fmt.Sprintf("ws://%s:%v", opts.AnvilAddress, opts.AnvilPort)
+		}
+	}
+
+	w.Workers = append(w.Workers, decoder.PortalWorker{
+		Decoder:            outputDecoder,
+		Provider:           rpcUrl,
+		ApplicationAddress: common.HexToAddress(opts.ApplicationAddress),
 	})
 
 	w.Workers = append(w.Workers, inputter.InputterWorker{
 		Model:              modelInstance,
-		Provider:           fmt.Sprintf("ws://%s:%v", devnet.AnvilDefaultAddress, devnet.AnvilDefaultPort),
-		InputBoxAddress:    common.HexToAddress(devnet.InputBoxAddress),
-		InputBoxBlock:      0,
-		ApplicationAddress: common.HexToAddress(devnet.ApplicationAddress),
+		Provider:           rpcUrl,
+		InputBoxAddress:    common.HexToAddress(opts.InputBoxAddress),
+		InputBoxBlock:      opts.InputBoxBlock,
+		ApplicationAddress: common.HexToAddress(opts.ApplicationAddress),
+		BlockRange:         opts.InputterBlockRange,
+		Confirmations:      opts.InputterConfirmations,
+		RewindOnReorg:      opts.InputterRewindOnReorg,
+		Polling:            opts.RpcPolling,
+		PollInterval:       opts.RpcPollInterval,
 	})
 
-	rollup.Register(e, modelInstance, inputBoxSequencer)
+	w.Workers = append(w.Workers, inputter.ExecutionWorker{
+		Service:            container.GetConvenienceService(),
+		Model:              modelInstance,
+		Provider:           rpcUrl,
+		ApplicationAddress: common.HexToAddress(opts.ApplicationAddress),
+		FromBlock:          opts.InputBoxBlock,
+		BlockRange:         opts.InputterBlockRange,
+		Polling:            opts.RpcPolling,
+		PollInterval:       opts.RpcPollInterval,
+	})
+
+	w.Workers = append(w.Workers, simulator.SimulatorWorker{
+		Service:            container.GetConvenienceService(),
+		Provider:           rpcUrl,
+		ApplicationAddress: common.HexToAddress(opts.ApplicationAddress),
+	})
+
+	if !opts.DisableAnvil && !opts.DisableClaimer {
+		w.Workers = append(w.Workers, claimer.ClaimerWorker{
+			Model:              modelInstance,
+			Provider:           rpcUrl,
+			ApplicationAddress: common.HexToAddress(opts.ApplicationAddress),
+			PrivateKey:         devnet.SenderPrivateKey,
+		})
+	}
+
+	rollup.Register(e, modelInstance, inputBoxSequencer, opts.FinishTimeout)
+	inspect.Register(e, modelInstance, opts.InspectTimeout)
+	proof.Register(e, modelInstance)
+	epoch.Register(e, modelInstance)
+	reader.Register(e, modelInstance, container.GetConvenienceService())
+	query.Register(e, modelInstance, container.GetConvenienceService())
+	admin.Register(e, modelInstance, outputDecoder, rpcUrl, common.HexToAddress(opts.ApplicationAddress))
+
+	w.Workers = append(w.Workers, epoch.EpochWorker{
+		Model: modelInstance,
+	})
 
 	w.Workers = append(w.Workers, supervisor.HttpWorker{
-		Address: "127.0.0.1:5004",
+		Address: fmt.Sprintf("%s:%d", opts.HttpAddress, opts.HttpPort),
 		Handler: e,
 	})
 
-	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
 	ready := make(chan struct{}, 1)
 	go func() {
 		select {
 		case <-ready:
-			fmt.Println(startupMessage)
+			fmt.Printf(startupMessage, opts.HttpAddress, opts.HttpPort)
 			slog.Info("http server: ready", "after", time.Since(startTime))
 		case <-ctx.Done():
 		}
 	}()
-	w.Start(ctx, ready)
+	return w.Start(ctx, ready)
+}
 
+func runExecuteVoucher(cmd *cobra.Command, args []string) error {
+	inputIndex, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid input index %q", args[0])
+	}
+	outputIndex, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid output index %q", args[1])
+	}
+	client, err := admin.NewClientWithResponses(executeVoucherOpts.Url)
+	if err != nil {
+		return err
+	}
+	request := admin.ExecuteVoucherRequest{
+		Account: &executeVoucherOpts.Account,
+	}
+	resp, err := client.ExecuteVoucherWithResponse(cmd.Context(), inputIndex, outputIndex, request)
+	if err != nil {
+		return err
+	}
+	if resp.JSON200 == nil {
+		return fmt.Errorf("execute voucher: %s", strings.TrimSpace(string(resp.Body)))
+	}
+	result := resp.JSON200
+	fmt.Printf("status: %s\n", result.Status)
+	if result.TransactionHash != nil {
+		fmt.Printf("transaction: %s\n", *result.TransactionHash)
+		fmt.Printf("block: %d\n", *result.BlockNumber)
+		fmt.Printf("gas used: %d\n", *result.GasUsed)
+	}
+	if result.RevertReason != nil {
+		fmt.Printf("revert reason: %s\n", *result.RevertReason)
+	}
+	return nil
+}
+
+func main() {
+	err := cmd.ExecuteContext(context.Background())
+	if err != nil {
+		os.Exit(1)
+	}
 }
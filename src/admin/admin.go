@@ -0,0 +1,166 @@
+// This package contains the bindings for the admin OpenAPI spec.
+package admin
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen -config=oapi.yaml ../../api/admin.yaml
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/calindra/rollups-server/src/decoder"
+	"github.com/calindra/rollups-server/src/devnet"
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+)
+
+// Register the admin API to echo.
+// The vouchers are executed in the application deployed in the node of rpcUrl.
+func Register(
+	e *echo.Echo,
+	model *mdl.AppModel,
+	outputDecoder *decoder.OutputDecoder,
+	rpcUrl string,
+	applicationAddress common.Address,
+) {
+	var adminAPI ServerInterface = &AdminAPI{model, outputDecoder, rpcUrl, applicationAddress}
+	RegisterHandlers(e, adminAPI)
+}
+
+// Shared struct for request handlers.
+type AdminAPI struct {
+	model              *mdl.AppModel
+	outputDecoder      *decoder.OutputDecoder
+	rpcUrl             string
+	applicationAddress common.Address
+}
+
+// Handle POST requests to /admin/epochs/close.
+func (a *AdminAPI) CloseEpoch(c echo.Context) error {
+	epoch, err := a.model.CloseEpoch()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	if epoch == nil {
+		return c.String(http.StatusNotFound, "no open epoch with inputs")
+	}
+	resp := IndexResponse{
+		Index: epoch.Index,
+	}
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Handle POST requests to /admin/inputs/rewind.
+func (a *AdminAPI) RewindInputs(c echo.Context) error {
+	var request RewindRequest
+	if err := c.Bind(&request); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	removed, err := a.model.RewindInputs(request.BlockNumber)
+	if errors.Is(err, mdl.ErrClaimedEpoch) {
+		return c.String(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	resp := RewindResult{
+		RemovedInputs: removed,
+	}
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Handle POST requests to /admin/vouchers/{inputIndex}/{outputIndex}/execute.
+func (a *AdminAPI) ExecuteVoucher(c echo.Context, inputIndex uint64, outputIndex uint64) error {
+	var request ExecuteVoucherRequest
+	if err := c.Bind(&request); err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	account := 0
+	if request.Account != nil {
+		account = *request.Account
+	}
+	if account < 0 || account >= len(devnet.TestPrivateKeys) {
+		return c.String(http.StatusBadRequest, "invalid test account")
+	}
+
+	proof, err := a.model.GetProof(inputIndex, outputIndex)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	if proof == nil {
+		return c.String(http.StatusNotFound, "proof not found")
+	}
+	if !mdl.IsVoucherOutput(proof.Output) {
+		return c.String(http.StatusBadRequest, "output is not a voucher")
+	}
+
+	result, err := devnet.ExecuteOutput(c.Request().Context(), a.rpcUrl, a.applicationAddress,
+		account, proof.Output, proof.ValidityProof())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	resp := convertExecutionResult(*result)
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Handle PUT requests to /admin/abis/contracts/{address}.
+func (a *AdminAPI) RegisterContractAbi(c echo.Context, address string) error {
+	if !common.IsHexAddress(address) {
+		return c.String(http.StatusBadRequest, "invalid contract address")
+	}
+	data, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	decoded, err := a.outputDecoder.RegisterContractAbi(
+		c.Request().Context(), common.HexToAddress(address), data)
+	if errors.Is(err, decoder.ErrInvalidAbi) {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	resp := RegisterAbiResult{
+		DecodedOutputs: decoded,
+	}
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Handle PUT requests to /admin/abis/notices/{name}.
+func (a *AdminAPI) RegisterNoticeSchema(c echo.Context, name string) error {
+	data, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	decoded, err := a.outputDecoder.RegisterNoticeSchema(c.Request().Context(), name, data)
+	if errors.Is(err, decoder.ErrInvalidAbi) {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	resp := RegisterAbiResult{
+		DecodedOutputs: decoded,
+	}
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Convert the devnet execution result to API type.
+func convertExecutionResult(result devnet.ExecutionResult) ExecuteVoucherResult {
+	resp := ExecuteVoucherResult{
+		Status: Success,
+	}
+	if result.RevertReason != "" {
+		resp.Status = Reverted
+		resp.RevertReason = &result.RevertReason
+	}
+	if receipt := result.Receipt; receipt != nil {
+		transactionHash := receipt.TxHash.Hex()
+		blockNumber := receipt.BlockNumber.Uint64()
+		resp.TransactionHash = &transactionHash
+		resp.BlockNumber = &blockNumber
+		resp.GasUsed = &receipt.GasUsed
+	}
+	return resp
+}
@@ -0,0 +1,200 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calindra/rollups-server/src/container"
+	"github.com/calindra/rollups-server/src/decoder"
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+const testTimeout = 5 * time.Second
+
+type AdminSuite struct {
+	suite.Suite
+	model   *mdl.AppModel
+	decoder *decoder.OutputDecoder
+	server  *httptest.Server
+	client  *ClientWithResponses
+	tempDir string
+}
+
+func (s *AdminSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	tempDir, err := os.MkdirTemp("", "")
+	s.NoError(err)
+	s.tempDir = tempDir
+	db := sqlx.MustConnect("sqlite3", path.Join(tempDir, "admin.sqlite3"))
+	c := container.NewContainer(*db)
+	_, err = c.Migrate(context.Background(), false)
+	s.Require().NoError(err)
+	s.decoder = c.GetOutputDecoder()
+	s.model = mdl.NewAppModel(s.decoder, db)
+	e := echo.New()
+	Register(e, s.model, s.decoder, "http://127.0.0.1:0", common.Address{})
+	s.server = httptest.NewServer(e)
+	client, err := NewClientWithResponses(s.server.URL)
+	s.NoError(err)
+	s.client = client
+}
+
+func (s *AdminSuite) TearDownTest() {
+	s.server.Close()
+	os.RemoveAll(s.tempDir)
+}
+
+func TestAdminSuite(t *testing.T) {
+	suite.Run(t, new(AdminSuite))
+}
+
+func (s *AdminSuite) TestCloseEpoch() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	resp, err := s.client.CloseEpochWithResponse(ctx)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+
+	err = s.model.AddAdvanceInput(common.Address{}, []byte{0xde, 0xad}, 1, time.Now(), 0)
+	s.Require().NoError(err)
+	resp, err = s.client.CloseEpochWithResponse(ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode())
+	s.Equal(uint64(0), resp.JSON200.Index)
+	epoch, err := s.model.GetEpoch(0)
+	s.Require().NoError(err)
+	s.Equal(mdl.EpochStatusClosed, epoch.Status)
+}
+
+func (s *AdminSuite) TestRewindInputs() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	for i, blockNumber := range []uint64{1, 5, 8} {
+		err := s.model.AddAdvanceInput(common.Address{}, []byte{0xde, 0xad}, blockNumber, time.Now(), i)
+		s.Require().NoError(err)
+	}
+	resp, err := s.client.RewindInputsWithResponse(ctx, RewindRequest{BlockNumber: 4})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode())
+	s.Equal(2, resp.JSON200.RemovedInputs)
+	count, err := s.model.InputRepository.Count(context.Background(), nil)
+	s.Require().NoError(err)
+	s.Equal(1, int(count))
+}
+
+func (s *AdminSuite) TestRewindClaimedInputs() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	s.addProvedOutputs()
+	s.Require().NoError(s.model.SetEpochClaimed(0, common.HexToHash("0xabcd")))
+	resp, err := s.client.RewindInputsWithResponse(ctx, RewindRequest{BlockNumber: 0})
+	s.Require().NoError(err)
+	s.Equal(http.StatusConflict, resp.StatusCode())
+}
+
+// Add an input with a voucher and a notice and prove its epoch.
+func (s *AdminSuite) addProvedOutputs() {
+	err := s.model.AddAdvanceInput(common.Address{}, []byte{0xde, 0xad}, 1, time.Now(), 0)
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.AddVoucher(common.HexToAddress("0xfafa"), nil, []byte{0xbe, 0xef})
+	s.Require().NoError(err)
+	_, err = s.model.AddNotice([]byte{0xbe, 0xef})
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.CloseEpoch()
+	s.Require().NoError(err)
+}
+
+func (s *AdminSuite) TestExecuteVoucherNotFound() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	resp, err := s.client.ExecuteVoucherWithResponse(ctx, 0, 0, ExecuteVoucherRequest{})
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+}
+
+func (s *AdminSuite) TestExecuteNotice() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	s.addProvedOutputs()
+	resp, err := s.client.ExecuteVoucherWithResponse(ctx, 0, 1, ExecuteVoucherRequest{})
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode())
+	s.Equal("output is not a voucher", string(resp.Body))
+}
+
+func (s *AdminSuite) TestExecuteVoucherInvalidAccount() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	s.addProvedOutputs()
+	account := 10
+	resp, err := s.client.ExecuteVoucherWithResponse(ctx, 0, 0, ExecuteVoucherRequest{Account: &account})
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode())
+}
+
+func (s *AdminSuite) TestRegisterContractAbi() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	s.addProvedOutputs()
+	contractAbi := `[{"type":"function","name":"burn","inputs":[]}]`
+	resp, err := s.client.RegisterContractAbiWithBodyWithResponse(ctx, "0xfafa",
+		"application/json", strings.NewReader(contractAbi))
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode())
+
+	address := common.HexToAddress("0xfafa").Hex()
+	resp, err = s.client.RegisterContractAbiWithBodyWithResponse(ctx, address,
+		"application/json", strings.NewReader(`{"abi":[{"type":"foo"}]}`))
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode())
+
+	// 0xbeef is not a call of the registered ABI, so the stored voucher is not decoded
+	resp, err = s.client.RegisterContractAbiWithBodyWithResponse(ctx, address,
+		"application/json", strings.NewReader(contractAbi))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode())
+	s.Equal(0, resp.JSON200.DecodedOutputs)
+	s.NotNil(s.decoder.Registry().Contract(common.HexToAddress("0xfafa")))
+}
+
+func (s *AdminSuite) TestRegisterNoticeSchema() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	schema := `[{"name":"amount","type":"uint256"}]`
+	resp, err := s.client.RegisterNoticeSchemaWithBodyWithResponse(ctx, "bad.name",
+		"application/json", strings.NewReader(schema))
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode())
+
+	err = s.model.AddAdvanceInput(common.Address{}, []byte{0xde, 0xad}, 1, time.Now(), 0)
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.AddNotice(common.LeftPadBytes([]byte{0x07}, 32))
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+
+	resp, err = s.client.RegisterNoticeSchemaWithBodyWithResponse(ctx, "amount",
+		"application/json", strings.NewReader(schema))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode())
+	s.Equal(1, resp.JSON200.DecodedOutputs)
+}
@@ -0,0 +1,195 @@
+// This package contains the worker that submits the epoch claims to the consensus.
+package claimer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/calindra/rollups-server/src/contracts"
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Default interval between checks for proved epochs.
+const DefaultPollInterval = time.Second
+
+// Maximum delay between the retries of a claim that failed.
+const maxRetryDelay = time.Minute
+
+// Subset of the IConsensus ABI used to submit the claims.
+const consensusAbi = `[{
+	"type": "function",
+	"name": "submitClaim",
+	"inputs": [
+		{"name": "appContract", "type": "address"},
+		{"name": "inputRange", "type": "tuple", "components": [
+			{"name": "firstIndex", "type": "uint64"},
+			{"name": "lastIndex", "type": "uint64"}
+		]},
+		{"name": "epochHash", "type": "bytes32"}
+	],
+	"outputs": [],
+	"stateMutability": "nonpayable"
+}]`
+
+// Backend used to send the claims.
+type backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// This worker submits the claim of each proved epoch to the consensus of the application.
+// The claims are signed with the private key, which must belong to the owner of the
+// authority so the claim is accepted right away.
+type ClaimerWorker struct {
+	Model              *mdl.AppModel
+	Provider           string
+	ApplicationAddress common.Address
+	PrivateKey         string
+	PollInterval       time.Duration
+}
+
+func (w ClaimerWorker) String() string {
+	return "claimer"
+}
+
+func (w ClaimerWorker) Start(ctx context.Context, ready chan<- struct{}) error {
+	client, err := ethclient.DialContext(ctx, w.Provider)
+	if err != nil {
+		return fmt.Errorf("claimer: dial: %w", err)
+	}
+	defer client.Close()
+	application, err := contracts.NewApplication(w.ApplicationAddress, client)
+	if err != nil {
+		return fmt.Errorf("claimer: bind application: %w", err)
+	}
+	consensusAddress, err := application.GetConsensus(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return fmt.Errorf("claimer: get consensus: %w", err)
+	}
+	parsed, err := abi.JSON(strings.NewReader(consensusAbi))
+	if err != nil {
+		return fmt.Errorf("claimer: parse abi: %w", err)
+	}
+	consensus := bind.NewBoundContract(consensusAddress, parsed, client, client, client)
+	txOpts, err := w.newTransactOpts(ctx, client)
+	if err != nil {
+		return err
+	}
+	slog.Info("claimer: submitting claims", "consensus", consensusAddress)
+
+	interval := w.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ready <- struct{}{}
+	// the delay doubles after each failed claim, up to maxRetryDelay, and is reset by the next
+	// successful claim
+	delay := interval
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		failed, err := w.claimEpochs(ctx, client, consensus, txOpts)
+		if err != nil {
+			return err
+		}
+		if failed {
+			delay = min(2*delay, max(maxRetryDelay, interval))
+			slog.Warn("claimer: retrying the claims later", "delay", delay)
+		} else {
+			delay = interval
+		}
+	}
+}
+
+// Submit the claims of the proved epochs in order.
+// When the transaction fails, the epoch is kept proved and the claim is retried later;
+// return whether a claim failed.
+func (w ClaimerWorker) claimEpochs(
+	ctx context.Context,
+	client backend,
+	consensus *bind.BoundContract,
+	txOpts *bind.TransactOpts,
+) (bool, error) {
+	epochs, err := w.Model.GetEpochsToClaim()
+	if err != nil {
+		return false, fmt.Errorf("claimer: %w", err)
+	}
+	for _, epoch := range epochs {
+		txHash, err := w.submitClaim(ctx, client, consensus, txOpts, epoch)
+		if err != nil {
+			slog.Warn("claimer: failed to submit claim", "epoch", epoch.Index, "error", err)
+			return true, nil
+		}
+		err = w.Model.SetEpochClaimed(epoch.Index, txHash)
+		if err != nil {
+			return false, fmt.Errorf("claimer: %w", err)
+		}
+		slog.Info("claimer: submitted claim", "epoch", epoch.Index,
+			"epochHash", epoch.EpochHash(), "tx", txHash)
+	}
+	return false, nil
+}
+
+// Submit the claim of the epoch and wait for the transaction to be mined.
+func (w ClaimerWorker) submitClaim(
+	ctx context.Context,
+	client backend,
+	consensus *bind.BoundContract,
+	txOpts *bind.TransactOpts,
+	epoch mdl.Epoch,
+) (common.Hash, error) {
+	inputRange := contracts.InputRange{
+		FirstIndex: epoch.FirstInputIndex,
+		LastIndex:  epoch.LastInputIndex,
+	}
+	// The server does not run the application in a Cartesi machine, so there is no machine
+	// state to commit to: the epoch hash is computed with a zero MachineStateHash, which the
+	// proofs of the epoch carry as well, so the contract accepts them.
+	tx, err := consensus.Transact(txOpts, "submitClaim", w.ApplicationAddress, inputRange, epoch.EpochHash())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("submit claim: %w", err)
+	}
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("wait for claim: %w", err)
+	}
+	if receipt.Status == 0 {
+		return common.Hash{}, fmt.Errorf("claim transaction %v was reverted", tx.Hash())
+	}
+	return tx.Hash(), nil
+}
+
+// Create the options to sign the transactions with the private key.
+// The nonce and the gas are filled when each transaction is sent.
+func (w ClaimerWorker) newTransactOpts(
+	ctx context.Context,
+	client backend,
+) (*bind.TransactOpts, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(w.PrivateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("claimer: private key: %w", err)
+	}
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("claimer: get chain id: %w", err)
+	}
+	txOpts, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
+	if err != nil {
+		return nil, fmt.Errorf("claimer: create transactor: %w", err)
+	}
+	txOpts.Context = ctx
+	return txOpts, nil
+}
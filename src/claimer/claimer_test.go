@@ -0,0 +1,130 @@
+package claimer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/calindra/rollups-server/src/contracts"
+	"github.com/calindra/rollups-server/src/devnet"
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+const testTimeout = 10 * time.Second
+
+var testApplication = common.HexToAddress(devnet.ApplicationAddress)
+
+type ClaimerSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+	rpcUrl string
+	model  *mdl.AppModel
+}
+
+func (s *ClaimerSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), testTimeout)
+
+	port := devnet.AnvilDefaultPort + 102
+	anvil := devnet.AnvilWorker{Address: devnet.AnvilDefaultAddress, Port: port}
+	ready := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		result <- anvil.Start(s.ctx, ready)
+	}()
+	select {
+	case <-ready:
+	case err := <-result:
+		s.Require().NoError(err)
+	case <-s.ctx.Done():
+		s.Require().NoError(s.ctx.Err())
+	}
+	s.rpcUrl = fmt.Sprintf("http://%s:%v", devnet.AnvilDefaultAddress, port)
+
+	db := sqlx.MustConnect("sqlite3", path.Join(s.T().TempDir(), "claimer.sqlite3"))
+	s.model = mdl.NewAppModel(nil, db)
+}
+
+func (s *ClaimerSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestClaimerSuite(t *testing.T) {
+	suite.Run(t, new(ClaimerSuite))
+}
+
+// Add an input with a voucher and an input with a notice and prove their epoch.
+func (s *ClaimerSuite) addProvedEpoch() {
+	sender := common.HexToAddress(devnet.SenderAddress)
+	for i := 0; i < 2; i++ {
+		err := s.model.AddAdvanceInput(sender, []byte{byte(i)}, uint64(i+1), time.Now(), i)
+		s.Require().NoError(err)
+	}
+	_, err := s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.AddVoucher(sender, big.NewInt(0), []byte{0xbe, 0xef})
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.AddNotice([]byte{0xaa})
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	epoch, err := s.model.CloseEpoch()
+	s.Require().NoError(err)
+	s.Require().NotNil(epoch)
+	s.Require().Equal(mdl.EpochStatusProved, epoch.Status)
+}
+
+func (s *ClaimerSuite) TestClaimedProofsAreAccepted() {
+	s.addProvedEpoch()
+	worker := ClaimerWorker{
+		Model:              s.model,
+		Provider:           s.rpcUrl,
+		ApplicationAddress: testApplication,
+		PrivateKey:         devnet.SenderPrivateKey,
+		PollInterval:       10 * time.Millisecond,
+	}
+	ready := make(chan struct{}, 1)
+	go func() {
+		_ = worker.Start(s.ctx, ready)
+	}()
+	s.Eventually(func() bool {
+		epoch, err := s.model.GetEpoch(0)
+		return err == nil && epoch != nil && epoch.Status == mdl.EpochStatusClaimed
+	}, testTimeout, 10*time.Millisecond)
+
+	client, err := ethclient.DialContext(s.ctx, s.rpcUrl)
+	s.Require().NoError(err)
+	defer client.Close()
+	application, err := contracts.NewApplication(testApplication, client)
+	s.Require().NoError(err)
+	notice, err := s.model.GetProof(1, 0)
+	s.Require().NoError(err)
+	s.Require().NotNil(notice)
+	err = application.ValidateOutput(&bind.CallOpts{Context: s.ctx}, notice.Output, notice.ValidityProof())
+	s.NoError(err)
+
+	voucher, err := s.model.GetProof(0, 0)
+	s.Require().NoError(err)
+	s.Require().NotNil(voucher)
+	result, err := devnet.ExecuteOutput(
+		s.ctx, s.rpcUrl, testApplication, 1, voucher.Output, voucher.ValidityProof())
+	s.Require().NoError(err)
+	s.Empty(result.RevertReason)
+	s.Require().NotNil(result.Receipt)
+	s.Equal(types.ReceiptStatusSuccessful, result.Receipt.Status)
+}
@@ -1,7 +1,10 @@
 package container
 
 import (
+	"context"
+
 	"github.com/calindra/rollups-server/src/decoder"
+	"github.com/calindra/rollups-server/src/migrations"
 	"github.com/calindra/rollups-server/src/model"
 	"github.com/calindra/rollups-server/src/services"
 	"github.com/jmoiron/sqlx"
@@ -13,6 +16,7 @@ type Container struct {
 	convenienceService *services.ConvenienceService
 	repository         *model.VoucherRepository
 	noticeRepository   *model.NoticeRepository
+	depositRepository  *model.DepositRepository
 }
 
 func NewContainer(db sqlx.DB) *Container {
@@ -21,6 +25,13 @@ func NewContainer(db sqlx.DB) *Container {
 	}
 }
 
+// Apply the pending schema migrations to the database.
+// The repositories expect the schema to be up to date, so call it before them.
+// When dryRun is set, only report the pending migrations.
+func (c *Container) Migrate(ctx context.Context, dryRun bool) ([]migrations.Migration, error) {
+	return migrations.Migrate(ctx, c.db, dryRun)
+}
+
 func (c *Container) GetOutputDecoder() *decoder.OutputDecoder {
 	if c.outputDecoder != nil {
 		return c.outputDecoder
@@ -36,6 +47,7 @@ func (c *Container) GetConvenienceService() *services.ConvenienceService {
 	c.convenienceService = services.NewConvenienceService(
 		c.GetRepository(),
 		c.GetNoticeRepository(),
+		c.GetDepositRepository(),
 	)
 	return c.convenienceService
 }
@@ -47,10 +59,6 @@ func (c *Container) GetRepository() *model.VoucherRepository {
 	c.repository = &model.VoucherRepository{
 		Db: *c.db,
 	}
-	err := c.repository.CreateTables()
-	if err != nil {
-		panic(err)
-	}
 	return c.repository
 }
 
@@ -61,9 +69,15 @@ func (c *Container) GetNoticeRepository() *model.NoticeRepository {
 	c.noticeRepository = &model.NoticeRepository{
 		Db: *c.db,
 	}
-	err := c.noticeRepository.CreateTables()
-	if err != nil {
-		panic(err)
-	}
 	return c.noticeRepository
 }
+
+func (c *Container) GetDepositRepository() *model.DepositRepository {
+	if c.depositRepository != nil {
+		return c.depositRepository
+	}
+	c.depositRepository = &model.DepositRepository{
+		Db: *c.db,
+	}
+	return c.depositRepository
+}
@@ -1,6 +1,7 @@
 package dataavailability
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -51,7 +52,7 @@ func computeEpoch(blockNumber *big.Int) (*big.Int, error) {
 func (e *EspressoFetcher) fetchCurrentInput() (*model.AdvanceInput, error) {
 	// retrieve total number of inputs
 	input := e.inputRepository
-	currentInput, err := input.FindByStatusNeDesc(model.CompletionStatusUnprocessed)
+	currentInput, err := input.FindByStatusNeDesc(context.Background(), model.CompletionStatusUnprocessed)
 	if err != nil {
 		return nil, err
 	}
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
+	"math/big"
 	"net/http"
+	"sync"
 
 	"github.com/calindra/rollups-server/src/model"
 	"github.com/calindra/rollups-server/src/services"
@@ -15,19 +18,42 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// Number of outputs read in each query when decoding the stored outputs.
+const decodePageSize = 100
+
 type OutputDecoder struct {
 	convenienceService services.ConvenienceService
+
+	// Kinds of the application portals, set by the PortalWorker.
+	portalsMutex sync.Mutex
+	portals      map[common.Address]model.DepositKind
+
+	registry *AbiRegistry
+
+	// URL of the Etherscan API that provides the ABIs missing from the registry.
+	etherscanUrl string
 }
 
+// URL of the Etherscan API used by default.
+const etherscanApiUrl = "https://api.etherscan.io/api"
+
 func NewOutputDecoder(convenienceService services.ConvenienceService) *OutputDecoder {
 	return &OutputDecoder{
 		convenienceService: convenienceService,
+		registry:           NewAbiRegistry(),
+		etherscanUrl:       etherscanApiUrl,
 	}
 }
 
+// Get the registry of the ABIs used to decode the outputs.
+func (o *OutputDecoder) Registry() *AbiRegistry {
+	return o.registry
+}
+
 func (o *OutputDecoder) HandleOutput(
 	ctx context.Context,
 	destination common.Address,
+	value *big.Int,
 	payload string,
 	inputIndex uint64,
 	outputIndex uint64,
@@ -37,63 +63,235 @@ func (o *OutputDecoder) HandleOutput(
 	// 0xc258d6e5 for Notice
 	// 0xef615e2f for Vouchers
 	if payload[2:10] == model.VOUCHER_SELECTOR {
-		_, err := o.convenienceService.CreateVoucher(ctx, &model.ConvenienceVoucher{
+		voucher := &model.ConvenienceVoucher{
 			Destination: destination,
+			Value:       value,
 			Payload:     util.RemoveSelector(payload),
 			Executed:    false,
 			InputIndex:  inputIndex,
 			OutputIndex: outputIndex,
-		})
+		}
+		decodeWithdrawal(voucher)
+		o.decodeCall(voucher)
+		_, err := o.convenienceService.CreateVoucher(ctx, voucher)
 		return err
 	} else {
-		_, err := o.convenienceService.CreateNotice(ctx, &model.ConvenienceNotice{
+		notice := &model.ConvenienceNotice{
 			Payload:     util.RemoveSelector(payload),
 			InputIndex:  inputIndex,
 			OutputIndex: outputIndex,
-		})
+		}
+		o.decodeNotice(notice)
+		_, err := o.convenienceService.CreateNotice(ctx, notice)
 		return err
 	}
 }
 
+// Decode the call of the voucher with the ABI of its destination in the registry.
+// Return false if there is no ABI for the call.
+func (o *OutputDecoder) decodeCall(voucher *model.ConvenienceVoucher) bool {
+	if o.registry == nil {
+		return false
+	}
+	function, args, ok := o.registry.DecodeCall(voucher.Destination, common.FromHex(voucher.Payload))
+	if ok {
+		voucher.DecodedFunction = function
+		voucher.DecodedArgs = args
+	}
+	return ok
+}
+
+// Decode the notice with the notice schemas in the registry.
+// Return false if no schema matches the notice.
+func (o *OutputDecoder) decodeNotice(notice *model.ConvenienceNotice) bool {
+	if o.registry == nil {
+		return false
+	}
+	schema, fields, ok := o.registry.DecodeNotice(common.FromHex(notice.Payload))
+	if ok {
+		notice.DecodedSchema = schema
+		notice.DecodedFields = fields
+	}
+	return ok
+}
+
+// Register the ABI of the contract and decode the stored vouchers sent to it.
+// Return the number of decoded vouchers.
+func (o *OutputDecoder) RegisterContractAbi(
+	ctx context.Context,
+	address common.Address,
+	data []byte,
+) (int, error) {
+	err := o.registry.RegisterContract(address, data)
+	if err != nil {
+		return 0, err
+	}
+	field := model.DESTINATION
+	value := address.Hex()
+	filter := []*model.ConvenienceFilter{{Field: &field, Eq: &value}}
+	decoded := 0
+	var after *string
+	for {
+		first := decodePageSize
+		page, err := o.convenienceService.FindAllVouchers(ctx, &first, nil, after, nil, filter)
+		if err != nil {
+			return decoded, fmt.Errorf("find vouchers: %w", err)
+		}
+		for _, voucher := range page.Rows {
+			if !o.decodeCall(&voucher) {
+				continue
+			}
+			err = o.convenienceService.SetVoucherDecodedCall(ctx, voucher.InputIndex,
+				voucher.OutputIndex, voucher.DecodedFunction, voucher.DecodedArgs)
+			if err != nil {
+				return decoded, fmt.Errorf("set decoded call: %w", err)
+			}
+			decoded++
+		}
+		if !page.HasNextPage {
+			return decoded, nil
+		}
+		after = page.EndCursor
+	}
+}
+
+// Register the notice schema and decode the stored notices that were not decoded yet.
+// Return the number of decoded notices.
+func (o *OutputDecoder) RegisterNoticeSchema(
+	ctx context.Context,
+	name string,
+	data []byte,
+) (int, error) {
+	err := o.registry.RegisterNoticeSchema(name, data)
+	if err != nil {
+		return 0, err
+	}
+	decoded := 0
+	var after *string
+	for {
+		first := decodePageSize
+		page, err := o.convenienceService.FindAllNotices(ctx, &first, nil, after, nil, nil)
+		if err != nil {
+			return decoded, fmt.Errorf("find notices: %w", err)
+		}
+		for _, notice := range page.Rows {
+			if notice.DecodedSchema != "" || !o.decodeNotice(&notice) {
+				continue
+			}
+			err = o.convenienceService.SetNoticeDecodedFields(ctx, notice.InputIndex,
+				notice.OutputIndex, notice.DecodedSchema, notice.DecodedFields)
+			if err != nil {
+				return decoded, fmt.Errorf("set decoded fields: %w", err)
+			}
+			decoded++
+		}
+		if !page.HasNextPage {
+			return decoded, nil
+		}
+		after = page.EndCursor
+	}
+}
+
+// Store the deposit of the input when it comes from one of the application portals.
+// A deposit that cannot be decoded is logged and skipped, so the input is still added.
+func (o *OutputDecoder) HandleInput(ctx context.Context, input model.AdvanceInput) error {
+	o.portalsMutex.Lock()
+	loaded := o.portals != nil
+	kind, ok := o.portals[input.MsgSender]
+	o.portalsMutex.Unlock()
+	if !loaded {
+		slog.Warn("decoder: the portals are not known yet; skipped the deposit decoding",
+			"input", input.Index, "sender", input.MsgSender)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	deposit, err := DecodeDeposit(kind, input.MsgSender, uint64(input.Index), input.Payload)
+	if err != nil {
+		slog.Warn("decoder: failed to decode deposit", "input", input.Index,
+			"portal", input.MsgSender, "error", err)
+		return nil
+	}
+	_, err = o.convenienceService.CreateDeposit(ctx, deposit)
+	if err != nil {
+		return fmt.Errorf("create deposit: %w", err)
+	}
+	slog.Info("decoder: decoded deposit", "input", input.Index, "kind", kind,
+		"token", deposit.Token, "depositor", deposit.Depositor)
+	return nil
+}
+
+// Set the portals of the application; the deposits are decoded from the inputs they send.
+// The portals of unknown kind are ignored.
+func (o *OutputDecoder) SetPortals(portals []common.Address) {
+	kinds := make(map[common.Address]model.DepositKind)
+	for _, portal := range portals {
+		kind, ok := portalKinds[portal]
+		if !ok {
+			slog.Warn("decoder: unknown portal; its inputs are not decoded", "portal", portal)
+			continue
+		}
+		kinds[portal] = kind
+	}
+	o.portalsMutex.Lock()
+	defer o.portalsMutex.Unlock()
+	o.portals = kinds
+}
+
+// Remove the outputs and deposits of the inputs from the given index on.
+func (o *OutputDecoder) RewindOutputs(ctx context.Context, inputIndex uint64) error {
+	err := o.convenienceService.DeleteOutputsFrom(ctx, inputIndex)
+	if err != nil {
+		return err
+	}
+	return o.convenienceService.DeleteDepositsFrom(ctx, inputIndex)
+}
+
+// Get the ABI of the contract from the registry or, when it is not registered, from
+// Etherscan.
 func (o *OutputDecoder) GetAbi(address common.Address) (*abi.ABI, error) {
-	baseURL := "https://api.etherscan.io/api"
-	contextPath := "?module=contract&action=getsourcecode&address="
-	url := fmt.Sprintf("%s/%s%s", baseURL, contextPath, address.String())
+	if o.registry != nil {
+		if contractAbi := o.registry.Contract(address); contractAbi != nil {
+			return contractAbi, nil
+		}
+	}
+	url := fmt.Sprintf("%s?module=contract&action=getsourcecode&address=%s",
+		o.etherscanUrl, address.String())
 
+	// the result is a message instead of a list when the status is not 1
 	var apiResponse struct {
-		Status  string `json:"status"`
-		Message string `json:"message"`
-		Result  []struct {
-			ABI string `json:"ABI"`
-		} `json:"result"`
+		Status  string          `json:"status"`
+		Message string          `json:"message"`
+		Result  json.RawMessage `json:"result"`
 	}
 
 	resp, err := http.Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected error")
+		return nil, fmt.Errorf("get abi from etherscan: %w", err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get abi from etherscan: status %s", resp.Status)
+	}
 	apiResult, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected error io")
+		return nil, fmt.Errorf("read etherscan response: %w", err)
 	}
 	if err := json.Unmarshal(apiResult, &apiResponse); err != nil {
-		return nil, fmt.Errorf("unexpected error")
+		return nil, fmt.Errorf("decode etherscan response: %w", err)
 	}
-	abiJSON := apiResponse.Result[0].ABI
-	var abiData abi.ABI
-	err2 := json.Unmarshal([]byte(abiJSON), &abiData)
-	if err2 != nil {
-		return nil, fmt.Errorf("unexpected error json %s", err2.Error())
+	if apiResponse.Status != "1" {
+		return nil, fmt.Errorf("get abi from etherscan: %s: %s", apiResponse.Message, apiResponse.Result)
 	}
-	return &abiData, nil
-}
-
-func jsonToAbi(abiJSON string) (*abi.ABI, error) {
-	var abiData abi.ABI
-	err2 := json.Unmarshal([]byte(abiJSON), &abiData)
-	if err2 != nil {
-		return nil, fmt.Errorf("unexpected error json %s", err2.Error())
+	var sources []struct {
+		ABI string `json:"ABI"`
+	}
+	if err := json.Unmarshal(apiResponse.Result, &sources); err != nil {
+		return nil, fmt.Errorf("decode etherscan result: %w", err)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("get abi from etherscan: no source code for %s", address)
 	}
-	return &abiData, nil
+	return parseAbi([]byte(sources[0].ABI))
 }
@@ -3,8 +3,13 @@ package decoder
 import (
 	"context"
 	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/calindra/rollups-server/src/devnet"
 	"github.com/calindra/rollups-server/src/model"
 	"github.com/calindra/rollups-server/src/services"
 	"github.com/ethereum/go-ethereum/common"
@@ -19,6 +24,7 @@ type OutputDecoderSuite struct {
 	decoder           *OutputDecoder
 	voucherRepository *model.VoucherRepository
 	noticeRepository  *model.NoticeRepository
+	depositRepository *model.DepositRepository
 }
 
 func (s *OutputDecoderSuite) SetupTest() {
@@ -37,11 +43,16 @@ func (s *OutputDecoderSuite) SetupTest() {
 	if err != nil {
 		panic(err)
 	}
+	s.depositRepository = &model.DepositRepository{
+		Db: *db,
+	}
 	s.decoder = &OutputDecoder{
 		convenienceService: *services.NewConvenienceService(
 			s.voucherRepository,
 			s.noticeRepository,
+			s.depositRepository,
 		),
+		registry: NewAbiRegistry(),
 	}
 }
 
@@ -51,7 +62,7 @@ func TestDecoderSuite(t *testing.T) {
 
 func (s *OutputDecoderSuite) TestHandleOutput() {
 	ctx := context.Background()
-	err := s.decoder.HandleOutput(ctx, Token, "0xef615e2f11", 1, 2)
+	err := s.decoder.HandleOutput(ctx, Token, nil, "0xef615e2f11", 1, 2)
 	if err != nil {
 		panic(err)
 	}
@@ -63,6 +74,130 @@ func (s *OutputDecoderSuite) TestHandleOutput() {
 	s.Equal("0x11", voucher.Payload)
 }
 
+func (s *OutputDecoderSuite) TestHandleWithdrawalVoucher() {
+	ctx := context.Background()
+	beneficiary := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	payload := "0xef615e2fa9059cbb" +
+		common.Bytes2Hex(common.LeftPadBytes(beneficiary.Bytes(), 32)) +
+		common.Bytes2Hex(common.LeftPadBytes([]byte{0x07}, 32))
+	err := s.decoder.HandleOutput(ctx, Token, nil, payload, 1, 2)
+	s.Require().NoError(err)
+	voucher, err := s.voucherRepository.FindVoucherByInputAndOutputIndex(ctx, 1, 2)
+	s.Require().NoError(err)
+	s.Equal(Token, voucher.Contract)
+	s.Equal(beneficiary, voucher.Beneficiary)
+	s.Equal(big.NewInt(7), voucher.Amount)
+	s.Equal(model.ERCX20, voucher.ERCX)
+	s.Equal("transfer(address,uint256)", voucher.MethodSignature)
+}
+
+func (s *OutputDecoderSuite) TestHandleRegisteredOutputs() {
+	ctx := context.Background()
+	s.Require().NoError(s.decoder.Registry().RegisterContract(Token, []byte(testContractJson)))
+	s.Require().NoError(s.decoder.Registry().RegisterNoticeSchema("message", []byte(testMessageSchema)))
+	call := common.Bytes2Hex(s.mintPayload())
+	s.Require().NoError(s.decoder.HandleOutput(ctx, Token, nil, "0xef615e2f"+call, 1, 0))
+	notice := common.Bytes2Hex(s.messagePayload("hello"))
+	s.Require().NoError(s.decoder.HandleOutput(ctx, Token, nil, "0xc258d6e5"+notice, 1, 1))
+
+	voucher, err := s.voucherRepository.FindVoucherByInputAndOutputIndex(ctx, 1, 0)
+	s.Require().NoError(err)
+	s.Equal("mint", voucher.DecodedFunction)
+	s.Contains(string(voucher.DecodedArgs), `"amount":"1000"`)
+	stored, err := s.noticeRepository.FindByInputAndOutputIndex(ctx, 1, 1)
+	s.Require().NoError(err)
+	s.Equal("message", stored.DecodedSchema)
+	s.JSONEq(`{"message":"hello"}`, string(stored.DecodedFields))
+}
+
+func (s *OutputDecoderSuite) TestRegisterDecodesStoredOutputs() {
+	ctx := context.Background()
+	call := common.Bytes2Hex(s.mintPayload())
+	s.Require().NoError(s.decoder.HandleOutput(ctx, Token, nil, "0xef615e2f"+call, 1, 0))
+	s.Require().NoError(s.decoder.HandleOutput(ctx, testDepositor, nil, "0xef615e2f"+call, 1, 1))
+	notice := common.Bytes2Hex(s.messagePayload("hello"))
+	s.Require().NoError(s.decoder.HandleOutput(ctx, Token, nil, "0xc258d6e5"+notice, 2, 0))
+	s.Require().NoError(s.decoder.HandleOutput(ctx, Token, nil, "0xc258d6e511", 2, 1))
+
+	decoded, err := s.decoder.RegisterContractAbi(ctx, Token, []byte(testContractJson))
+	s.Require().NoError(err)
+	s.Equal(1, decoded)
+	voucher, err := s.voucherRepository.FindVoucherByInputAndOutputIndex(ctx, 1, 0)
+	s.Require().NoError(err)
+	s.Equal("mint", voucher.DecodedFunction)
+	voucher, err = s.voucherRepository.FindVoucherByInputAndOutputIndex(ctx, 1, 1)
+	s.Require().NoError(err)
+	s.Empty(voucher.DecodedFunction)
+
+	decoded, err = s.decoder.RegisterNoticeSchema(ctx, "message", []byte(testMessageSchema))
+	s.Require().NoError(err)
+	s.Equal(1, decoded)
+	stored, err := s.noticeRepository.FindByInputAndOutputIndex(ctx, 2, 0)
+	s.Require().NoError(err)
+	s.Equal("message", stored.DecodedSchema)
+
+	_, err = s.decoder.RegisterNoticeSchema(ctx, "invalid", []byte("{"))
+	s.Error(err)
+}
+
+func (s *OutputDecoderSuite) mintPayload() []byte {
+	contractAbi := mustParseAbi(testContractJson)
+	payload, err := contractAbi.Pack("mint", testBeneficiary, big.NewInt(1000),
+		[]string{"a"}, []byte{})
+	s.Require().NoError(err)
+	return payload
+}
+
+func (s *OutputDecoderSuite) messagePayload(message string) []byte {
+	payload, err := mustParseArguments(testMessageSchema).Pack(message)
+	s.Require().NoError(err)
+	return payload
+}
+
+func (s *OutputDecoderSuite) TestHandleDepositInput() {
+	ctx := context.Background()
+	etherPortal := common.HexToAddress(devnet.EtherPortalAddress)
+	depositor := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	payload := append(depositor.Bytes(), common.LeftPadBytes([]byte{0x64}, 32)...)
+	input := model.AdvanceInput{Index: 1, MsgSender: etherPortal, Payload: payload}
+
+	// the inputs of the portals are only decoded after the portals are set
+	s.NoError(s.decoder.HandleInput(ctx, input))
+	deposit, err := s.depositRepository.FindByInputIndex(ctx, 1)
+	s.NoError(err)
+	s.Nil(deposit)
+
+	s.decoder.SetPortals([]common.Address{etherPortal, Token})
+	s.NoError(s.decoder.HandleInput(ctx, input))
+	s.NoError(s.decoder.HandleInput(ctx, model.AdvanceInput{Index: 2, MsgSender: depositor}))
+	deposit, err = s.depositRepository.FindByInputIndex(ctx, 1)
+	s.NoError(err)
+	s.Require().NotNil(deposit)
+	s.Equal(model.DepositKindEther, deposit.Kind)
+	s.Equal(depositor, deposit.Depositor)
+	s.Equal(big.NewInt(100), deposit.Amount)
+	count, err := s.depositRepository.Count(ctx, nil)
+	s.NoError(err)
+	s.Equal(1, int(count))
+
+	s.NoError(s.decoder.RewindOutputs(ctx, 1))
+	count, err = s.depositRepository.Count(ctx, nil)
+	s.NoError(err)
+	s.Equal(0, int(count))
+}
+
+func (s *OutputDecoderSuite) TestSkipInvalidDeposit() {
+	ctx := context.Background()
+	etherPortal := common.HexToAddress(devnet.EtherPortalAddress)
+	s.decoder.SetPortals([]common.Address{etherPortal})
+	err := s.decoder.HandleInput(ctx, model.AdvanceInput{Index: 1, MsgSender: etherPortal,
+		Payload: []byte{0xde, 0xad}})
+	s.NoError(err)
+	count, err := s.depositRepository.Count(ctx, nil)
+	s.NoError(err)
+	s.Equal(0, int(count))
+}
+
 func (s *OutputDecoderSuite) TestGetAbiFromEtherscan() {
 	s.T().Skip()
 	address := common.HexToAddress("0x26A61aF89053c847B4bd5084E2caFe7211874a29")
@@ -75,9 +210,39 @@ func (s *OutputDecoderSuite) TestGetAbiFromEtherscan() {
 	s.Equal("transfer", abiMethod.RawName)
 }
 
+// Serve the Etherscan response body in the decoder.
+func (s *OutputDecoderSuite) serveEtherscan(body string) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+	s.T().Cleanup(server.Close)
+	s.decoder.etherscanUrl = server.URL
+}
+
+func (s *OutputDecoderSuite) TestGetAbiFromEtherscanResult() {
+	s.serveEtherscan(`{"status":"1","message":"OK","result":[{"ABI":` +
+		`"[{\"type\":\"function\",\"name\":\"transfer\",\"inputs\":[` +
+		`{\"name\":\"to\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"}]}]"}]}`)
+	abi, err := s.decoder.GetAbi(Token)
+	s.Require().NoError(err)
+	s.Contains(abi.Methods, "transfer")
+}
+
+func (s *OutputDecoderSuite) TestGetAbiFromEtherscanError() {
+	s.serveEtherscan(`{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+	_, err := s.decoder.GetAbi(Token)
+	s.ErrorContains(err, "Invalid API Key")
+}
+
+func (s *OutputDecoderSuite) TestGetAbiFromEtherscanEmptyResult() {
+	s.serveEtherscan(`{"status":"1","message":"OK","result":[]}`)
+	_, err := s.decoder.GetAbi(Token)
+	s.ErrorContains(err, "no source code")
+}
+
 func (s *OutputDecoderSuite) TestCreateVoucherIdempotency() {
 	ctx := context.Background()
-	err := s.decoder.HandleOutput(ctx, Token, "0xef615e2f1122", 3, 4)
+	err := s.decoder.HandleOutput(ctx, Token, nil, "0xef615e2f1122", 3, 4)
 	if err != nil {
 		panic(err)
 	}
@@ -89,7 +254,7 @@ func (s *OutputDecoderSuite) TestCreateVoucherIdempotency() {
 
 	s.Equal(1, int(voucherCount))
 
-	err = s.decoder.HandleOutput(ctx, Token, "0xef615e2f1122", 3, 4)
+	err = s.decoder.HandleOutput(ctx, Token, nil, "0xef615e2f1122", 3, 4)
 
 	if err != nil {
 		panic(err)
@@ -128,7 +293,7 @@ func (s *OutputDecoderSuite) TestDecode() {
 		"stateMutability": "nonpayable",
 		"type": "function"
 	}]`
-	abi, err := jsonToAbi(json)
+	abi, err := parseAbi([]byte(json))
 	s.NoError(err)
 	selectorBytes, err := hex.DecodeString("a9059cbb")
 	s.NoError(err)
@@ -0,0 +1,150 @@
+package decoder
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/calindra/rollups-server/src/devnet"
+	"github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Kinds of the portals deployed by the rollups contracts.
+// The portals have the same address in every chain, including the devnet.
+var portalKinds = map[common.Address]model.DepositKind{
+	common.HexToAddress(devnet.EtherPortalAddress):         model.DepositKindEther,
+	common.HexToAddress(devnet.ERC20PortalAddress):         model.DepositKindERC20,
+	common.HexToAddress(devnet.ERC721PortalAddress):        model.DepositKindERC721,
+	common.HexToAddress(devnet.ERC1155SinglePortalAddress): model.DepositKindERC1155Single,
+	common.HexToAddress(devnet.ERC1155BatchPortalAddress):  model.DepositKindERC1155Batch,
+}
+
+// Size of the fields packed at the start of the deposits.
+const (
+	addressSize = common.AddressLength
+	uint256Size = 32
+)
+
+var (
+	uint256ArrayType, _ = abi.NewType("uint256[]", "", nil)
+	bytesType, _        = abi.NewType("bytes", "", nil)
+
+	// Data of the ERC-721 and single ERC-1155 deposits, encoded after the packed fields.
+	layerDataArgs = abi.Arguments{
+		{Name: "baseLayerData", Type: bytesType},
+		{Name: "execLayerData", Type: bytesType},
+	}
+
+	// Fields of the batch ERC-1155 deposits, encoded after the token and the depositor.
+	batchArgs = abi.Arguments{
+		{Name: "tokenIds", Type: uint256ArrayType},
+		{Name: "values", Type: uint256ArrayType},
+		{Name: "baseLayerData", Type: bytesType},
+		{Name: "execLayerData", Type: bytesType},
+	}
+)
+
+// Decode the payload of an input sent by a portal of the given kind.
+// The payload follows the InputEncoding library of the rollups contracts.
+func DecodeDeposit(
+	kind model.DepositKind,
+	portal common.Address,
+	inputIndex uint64,
+	payload []byte,
+) (*model.ConvenienceDeposit, error) {
+	deposit := &model.ConvenienceDeposit{
+		InputIndex: inputIndex,
+		Kind:       kind,
+		Portal:     portal,
+	}
+	reader := packedReader{data: payload}
+	if kind != model.DepositKindEther {
+		deposit.Token = reader.address()
+	}
+	deposit.Depositor = reader.address()
+	switch kind {
+	case model.DepositKindEther, model.DepositKindERC20:
+		deposit.Amount = reader.uint256()
+		deposit.ExecLayerData = reader.rest()
+	case model.DepositKindERC721:
+		deposit.TokenIds = []*big.Int{reader.uint256()}
+		err := reader.unpackLayerData(deposit)
+		if err != nil {
+			return nil, err
+		}
+	case model.DepositKindERC1155Single:
+		deposit.TokenIds = []*big.Int{reader.uint256()}
+		deposit.Amounts = []*big.Int{reader.uint256()}
+		err := reader.unpackLayerData(deposit)
+		if err != nil {
+			return nil, err
+		}
+	case model.DepositKindERC1155Batch:
+		if reader.err != nil {
+			return nil, reader.err
+		}
+		values, err := batchArgs.Unpack(reader.rest())
+		if err != nil {
+			return nil, fmt.Errorf("decode %v deposit: %w", kind, err)
+		}
+		deposit.TokenIds = values[0].([]*big.Int)
+		deposit.Amounts = values[1].([]*big.Int)
+		deposit.BaseLayerData = values[2].([]byte)
+		deposit.ExecLayerData = values[3].([]byte)
+	default:
+		return nil, fmt.Errorf("unknown deposit kind %v", kind)
+	}
+	if reader.err != nil {
+		return nil, fmt.Errorf("decode %v deposit: %w", kind, reader.err)
+	}
+	return deposit, nil
+}
+
+// Read the fields packed with abi.encodePacked.
+// After the first error, the reader returns zero values and keeps the error.
+type packedReader struct {
+	data []byte
+	err  error
+}
+
+func (r *packedReader) next(size int) []byte {
+	if r.err != nil {
+		return make([]byte, size)
+	}
+	if len(r.data) < size {
+		r.err = fmt.Errorf("payload too short")
+		return make([]byte, size)
+	}
+	field := r.data[:size]
+	r.data = r.data[size:]
+	return field
+}
+
+func (r *packedReader) address() common.Address {
+	return common.BytesToAddress(r.next(addressSize))
+}
+
+func (r *packedReader) uint256() *big.Int {
+	return new(big.Int).SetBytes(r.next(uint256Size))
+}
+
+func (r *packedReader) rest() []byte {
+	rest := r.data
+	r.data = nil
+	return rest
+}
+
+// Unpack the base layer and exec layer data into the deposit.
+func (r *packedReader) unpackLayerData(deposit *model.ConvenienceDeposit) error {
+	if r.err != nil {
+		return fmt.Errorf("decode %v deposit: %w", deposit.Kind, r.err)
+	}
+	values, err := layerDataArgs.Unpack(r.rest())
+	if err != nil {
+		return fmt.Errorf("decode %v deposit: %w", deposit.Kind, err)
+	}
+	deposit.BaseLayerData = values[0].([]byte)
+	deposit.ExecLayerData = values[1].([]byte)
+	return nil
+}
@@ -0,0 +1,117 @@
+package decoder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/calindra/rollups-server/src/devnet"
+	"github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	testDepositor = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	testBaseData  = []byte{0xba, 0x5e}
+	testExecData  = []byte{0xe0, 0xec}
+)
+
+type DepositSuite struct {
+	suite.Suite
+}
+
+func TestDepositSuite(t *testing.T) {
+	suite.Run(t, new(DepositSuite))
+}
+
+// Encode the values with abi.encodePacked.
+func encodePacked(values ...any) []byte {
+	var packed []byte
+	for _, value := range values {
+		switch v := value.(type) {
+		case common.Address:
+			packed = append(packed, v.Bytes()...)
+		case int64:
+			packed = append(packed, common.BigToHash(big.NewInt(v)).Bytes()...)
+		case []byte:
+			packed = append(packed, v...)
+		default:
+			panic("unexpected type")
+		}
+	}
+	return packed
+}
+
+func (s *DepositSuite) decode(kind model.DepositKind, payload []byte) *model.ConvenienceDeposit {
+	deposit, err := DecodeDeposit(kind, common.HexToAddress(devnet.EtherPortalAddress), 3, payload)
+	s.Require().NoError(err)
+	s.Equal(uint64(3), deposit.InputIndex)
+	s.Equal(kind, deposit.Kind)
+	s.Equal(testDepositor, deposit.Depositor)
+	return deposit
+}
+
+func (s *DepositSuite) TestEther() {
+	deposit := s.decode(model.DepositKindEther, encodePacked(testDepositor, int64(100), testExecData))
+	s.Equal(common.Address{}, deposit.Token)
+	s.Equal(big.NewInt(100), deposit.Amount)
+	s.Equal(testExecData, deposit.ExecLayerData)
+}
+
+func (s *DepositSuite) TestERC20() {
+	deposit := s.decode(model.DepositKindERC20, encodePacked(Token, testDepositor, int64(7)))
+	s.Equal(Token, deposit.Token)
+	s.Equal(big.NewInt(7), deposit.Amount)
+	s.Empty(deposit.ExecLayerData)
+}
+
+func (s *DepositSuite) TestERC721() {
+	data, err := layerDataArgs.Pack(testBaseData, testExecData)
+	s.Require().NoError(err)
+	deposit := s.decode(model.DepositKindERC721, encodePacked(Token, testDepositor, int64(42), data))
+	s.Equal(Token, deposit.Token)
+	s.Nil(deposit.Amount)
+	s.Equal([]*big.Int{big.NewInt(42)}, deposit.TokenIds)
+	s.Equal(testBaseData, deposit.BaseLayerData)
+	s.Equal(testExecData, deposit.ExecLayerData)
+}
+
+func (s *DepositSuite) TestERC1155Single() {
+	data, err := layerDataArgs.Pack(testBaseData, testExecData)
+	s.Require().NoError(err)
+	payload := encodePacked(Token, testDepositor, int64(42), int64(5), data)
+	deposit := s.decode(model.DepositKindERC1155Single, payload)
+	s.Equal([]*big.Int{big.NewInt(42)}, deposit.TokenIds)
+	s.Equal([]*big.Int{big.NewInt(5)}, deposit.Amounts)
+	s.Equal(testBaseData, deposit.BaseLayerData)
+	s.Equal(testExecData, deposit.ExecLayerData)
+}
+
+func (s *DepositSuite) TestERC1155Batch() {
+	ids := []*big.Int{big.NewInt(1), big.NewInt(2)}
+	amounts := []*big.Int{big.NewInt(10), big.NewInt(20)}
+	data, err := batchArgs.Pack(ids, amounts, testBaseData, testExecData)
+	s.Require().NoError(err)
+	deposit := s.decode(model.DepositKindERC1155Batch, encodePacked(Token, testDepositor, data))
+	s.Equal(Token, deposit.Token)
+	s.Equal(ids, deposit.TokenIds)
+	s.Equal(amounts, deposit.Amounts)
+	s.Equal(testBaseData, deposit.BaseLayerData)
+	s.Equal(testExecData, deposit.ExecLayerData)
+}
+
+func (s *DepositSuite) TestShortPayload() {
+	for _, kind := range []model.DepositKind{
+		model.DepositKindEther,
+		model.DepositKindERC20,
+		model.DepositKindERC721,
+		model.DepositKindERC1155Single,
+		model.DepositKindERC1155Batch,
+	} {
+		_, err := DecodeDeposit(kind, common.Address{}, 0, testDepositor.Bytes()[:10])
+		s.Error(err, kind)
+	}
+	_, err := DecodeDeposit(model.DepositKindERC721, common.Address{}, 0,
+		encodePacked(Token, testDepositor, int64(42), []byte{0x01}))
+	s.Error(err)
+}
@@ -0,0 +1,79 @@
+package decoder
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/calindra/rollups-server/src/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Delays between the attempts to read the portals, which double up to the maximum.
+const (
+	portalRetryDelay    = 100 * time.Millisecond
+	portalMaxRetryDelay = 2 * time.Second
+)
+
+// This worker reads the portals of the application when it starts and sets them in the
+// decoder; so, start it before the inputter.
+// It signals that it is ready right away and retries in the background until the portals
+// are read, so an unavailable node does not stop the server; the deposits of the inputs
+// read before that are not decoded.
+type PortalWorker struct {
+	Decoder            *OutputDecoder
+	Provider           string
+	ApplicationAddress common.Address
+}
+
+func (w PortalWorker) String() string {
+	return "portal"
+}
+
+func (w PortalWorker) Start(ctx context.Context, ready chan<- struct{}) error {
+	ready <- struct{}{}
+	delay := portalRetryDelay
+	for {
+		err := w.dialAndLoadPortals(ctx)
+		if err == nil {
+			break
+		}
+		slog.Warn("portal: failed to get the application portals; retrying",
+			"error", err, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, portalMaxRetryDelay)
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (w PortalWorker) dialAndLoadPortals(ctx context.Context) error {
+	client, err := ethclient.DialContext(ctx, w.Provider)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer client.Close()
+	return w.loadPortals(ctx, client)
+}
+
+// Get the portals from the application and set them in the decoder.
+func (w PortalWorker) loadPortals(ctx context.Context, client bind.ContractCaller) error {
+	application, err := contracts.NewApplicationCaller(w.ApplicationAddress, client)
+	if err != nil {
+		return fmt.Errorf("bind application: %w", err)
+	}
+	portals, err := application.GetPortals(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return fmt.Errorf("get portals: %w", err)
+	}
+	slog.Info("portal: read the application portals", "portals", portals)
+	w.Decoder.SetPortals(portals)
+	return nil
+}
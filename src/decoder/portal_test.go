@@ -0,0 +1,34 @@
+package decoder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/suite"
+)
+
+type PortalSuite struct {
+	suite.Suite
+}
+
+func TestPortalSuite(t *testing.T) {
+	suite.Run(t, new(PortalSuite))
+}
+
+func (s *PortalSuite) TestReadyBeforePortalsLoad() {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	decoder := &OutputDecoder{}
+	worker := PortalWorker{
+		Decoder:            decoder,
+		Provider:           "http://127.0.0.1:1",
+		ApplicationAddress: common.HexToAddress("0xfafa"),
+	}
+	ready := make(chan struct{}, 1)
+	err := worker.Start(ctx, ready)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Len(ready, 1, "the worker must be ready without the portals")
+	s.Nil(decoder.portals)
+}
@@ -0,0 +1,287 @@
+package decoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Directory of the notice schemas within the registry directory.
+const noticesDir = "notices"
+
+// Error returned when a registered ABI or its name is invalid.
+var ErrInvalidAbi = errors.New("invalid abi")
+
+var schemaNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Registry of the ABIs used to decode the outputs.
+// The contract ABIs decode the calls of the vouchers sent to the contract address; the
+// notice schemas are lists of ABI arguments that decode the ABI-encoded notices.
+// When the registry has a directory, the contract ABIs are stored in <address>.json and
+// the notice schemas in notices/<name>.json.
+type AbiRegistry struct {
+	mutex     sync.Mutex
+	dir       string
+	contracts map[common.Address]*abi.ABI
+	notices   map[string]abi.Arguments
+}
+
+// Create an empty registry that is kept in memory.
+func NewAbiRegistry() *AbiRegistry {
+	return &AbiRegistry{
+		contracts: make(map[common.Address]*abi.ABI),
+		notices:   make(map[string]abi.Arguments),
+	}
+}
+
+// Load the ABIs from the directory and store the ones registered later there.
+// The directory is created if it does not exist.
+func (r *AbiRegistry) Load(dir string) error {
+	err := os.MkdirAll(filepath.Join(dir, noticesDir), 0755)
+	if err != nil {
+		return fmt.Errorf("create abi directory: %w", err)
+	}
+	contractFiles, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range contractFiles {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		if !common.IsHexAddress(name) {
+			return fmt.Errorf("abi file %v is not named after a contract address", file)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := r.setContract(common.HexToAddress(name), data); err != nil {
+			return fmt.Errorf("load %v: %w", file, err)
+		}
+	}
+	noticeFiles, err := filepath.Glob(filepath.Join(dir, noticesDir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range noticeFiles {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := r.setNoticeSchema(name, data); err != nil {
+			return fmt.Errorf("load %v: %w", file, err)
+		}
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.dir = dir
+	return nil
+}
+
+// Register the ABI of the contract.
+// The data is a JSON ABI or a build artifact with the ABI in the abi field.
+func (r *AbiRegistry) RegisterContract(address common.Address, data []byte) error {
+	if err := r.setContract(address, data); err != nil {
+		return err
+	}
+	return r.save(address.Hex()+".json", data)
+}
+
+// Register the schema of the notices with the given name.
+// The data is a JSON list of ABI arguments, such as [{"name":"amount","type":"uint256"}].
+func (r *AbiRegistry) RegisterNoticeSchema(name string, data []byte) error {
+	if err := r.setNoticeSchema(name, data); err != nil {
+		return err
+	}
+	return r.save(filepath.Join(noticesDir, name+".json"), data)
+}
+
+func (r *AbiRegistry) setContract(address common.Address, data []byte) error {
+	var artifact struct {
+		Abi json.RawMessage `json:"abi"`
+	}
+	if err := json.Unmarshal(data, &artifact); err == nil && artifact.Abi != nil {
+		data = artifact.Abi
+	}
+	contractAbi, err := parseAbi(data)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAbi, err)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.contracts[address] = contractAbi
+	return nil
+}
+
+// Parse the JSON ABI of a contract.
+func parseAbi(data []byte) (*abi.ABI, error) {
+	var contractAbi abi.ABI
+	if err := json.Unmarshal(data, &contractAbi); err != nil {
+		return nil, fmt.Errorf("parse abi: %w", err)
+	}
+	return &contractAbi, nil
+}
+
+func (r *AbiRegistry) setNoticeSchema(name string, data []byte) error {
+	if !schemaNameRegexp.MatchString(name) {
+		return fmt.Errorf("%w: invalid notice schema name %q", ErrInvalidAbi, name)
+	}
+	var schema abi.Arguments
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidAbi, err)
+	}
+	if len(schema) == 0 {
+		return fmt.Errorf("%w: empty notice schema", ErrInvalidAbi)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.notices[name] = schema
+	return nil
+}
+
+// Write the file in the registry directory, if there is one.
+func (r *AbiRegistry) save(name string, data []byte) error {
+	r.mutex.Lock()
+	dir := r.dir
+	r.mutex.Unlock()
+	if dir == "" {
+		return nil
+	}
+	err := os.WriteFile(filepath.Join(dir, name), data, 0644)
+	if err != nil {
+		return fmt.Errorf("save abi: %w", err)
+	}
+	return nil
+}
+
+// Get the ABI of the contract; return nil if it is not registered.
+func (r *AbiRegistry) Contract(address common.Address) *abi.ABI {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.contracts[address]
+}
+
+// Decode the call sent to the contract with its ABI.
+// Return the method name and the JSON object with the arguments by name, or false when
+// the contract is not registered or the call does not match its ABI.
+func (r *AbiRegistry) DecodeCall(address common.Address, payload []byte) (string, []byte, bool) {
+	contractAbi := r.Contract(address)
+	if contractAbi == nil || len(payload) < 4 {
+		return "", nil, false
+	}
+	method, err := contractAbi.MethodById(payload[:4])
+	if err != nil {
+		return "", nil, false
+	}
+	fields, ok := decodeArguments(method.Inputs, payload[4:])
+	if !ok {
+		return "", nil, false
+	}
+	return method.Name, fields, true
+}
+
+// Decode the notice with the first schema, in name order, that encodes the same payload.
+// Return the schema name and the JSON object with the fields by name, or false when no
+// schema matches.
+func (r *AbiRegistry) DecodeNotice(payload []byte) (string, []byte, bool) {
+	r.mutex.Lock()
+	names := make([]string, 0, len(r.notices))
+	schemas := make(map[string]abi.Arguments, len(r.notices))
+	for name, schema := range r.notices {
+		names = append(names, name)
+		schemas[name] = schema
+	}
+	r.mutex.Unlock()
+	slices.Sort(names)
+	for _, name := range names {
+		if fields, ok := decodeArguments(schemas[name], payload); ok {
+			return name, fields, true
+		}
+	}
+	return "", nil, false
+}
+
+// Decode the ABI-encoded arguments into a JSON object.
+// The arguments only match when they encode back to the same data.
+func decodeArguments(args abi.Arguments, data []byte) ([]byte, bool) {
+	values, err := args.UnpackValues(data)
+	if err != nil {
+		return nil, false
+	}
+	encoded, err := args.Pack(values...)
+	if err != nil || !bytes.Equal(encoded, data) {
+		return nil, false
+	}
+	fields := make(map[string]any, len(args))
+	for i, arg := range args {
+		name := arg.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%v", i)
+		}
+		fields[name] = jsonValue(reflect.ValueOf(values[i]))
+	}
+	result, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false
+	}
+	return result, true
+}
+
+// Convert a decoded ABI value to a JSON value.
+// The integers become decimal strings, so they do not lose precision in JavaScript, and
+// the bytes become hex strings.
+func jsonValue(value reflect.Value) any {
+	switch v := value.Interface().(type) {
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	}
+	switch value.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprint(value.Int())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(value.Uint())
+	case reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(data), value)
+			return hexutil.Encode(data)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]any, value.Len())
+		for i := range items {
+			items[i] = jsonValue(value.Index(i))
+		}
+		return items
+	case reflect.Struct:
+		fields := make(map[string]any, value.NumField())
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			name := field.Tag.Get("json")
+			if name == "" {
+				name = field.Name
+			}
+			fields[name] = jsonValue(value.Field(i))
+		}
+		return fields
+	default:
+		return value.Interface()
+	}
+}
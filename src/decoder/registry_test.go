@@ -0,0 +1,148 @@
+package decoder
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testContractJson = `[
+		{"type":"function","name":"mint","inputs":[
+			{"name":"to","type":"address"},{"name":"amount","type":"uint256"},
+			{"name":"tags","type":"string[]"},{"name":"data","type":"bytes"}]}
+	]`
+	testBalanceSchema = `[{"name":"owner","type":"address"},{"name":"balance","type":"uint256"}]`
+	testMessageSchema = `[{"name":"message","type":"string"}]`
+)
+
+func mustParseArguments(data string) abi.Arguments {
+	var args abi.Arguments
+	if err := json.Unmarshal([]byte(data), &args); err != nil {
+		panic(err)
+	}
+	return args
+}
+
+type AbiRegistrySuite struct {
+	suite.Suite
+	registry *AbiRegistry
+}
+
+func TestAbiRegistrySuite(t *testing.T) {
+	suite.Run(t, new(AbiRegistrySuite))
+}
+
+func (s *AbiRegistrySuite) SetupTest() {
+	s.registry = NewAbiRegistry()
+}
+
+func (s *AbiRegistrySuite) mintPayload() []byte {
+	contractAbi := mustParseAbi(testContractJson)
+	payload, err := contractAbi.Pack("mint", testBeneficiary, big.NewInt(1000),
+		[]string{"a", "b"}, []byte{0xca, 0xfe})
+	s.Require().NoError(err)
+	return payload
+}
+
+func (s *AbiRegistrySuite) TestDecodeCall() {
+	s.Require().NoError(s.registry.RegisterContract(Token, []byte(testContractJson)))
+	name, fields, ok := s.registry.DecodeCall(Token, s.mintPayload())
+	s.Require().True(ok)
+	s.Equal("mint", name)
+	s.JSONEq(`{
+		"to": "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		"amount": "1000",
+		"tags": ["a", "b"],
+		"data": "0xcafe"
+	}`, string(fields))
+
+	_, _, ok = s.registry.DecodeCall(testDepositor, s.mintPayload())
+	s.False(ok, "unknown contract")
+	_, _, ok = s.registry.DecodeCall(Token, []byte{0xde, 0xad, 0xbe, 0xef})
+	s.False(ok, "unknown method")
+	_, _, ok = s.registry.DecodeCall(Token, s.mintPayload()[:40])
+	s.False(ok, "truncated arguments")
+}
+
+func (s *AbiRegistrySuite) TestArtifact() {
+	artifact := `{"contractName":"Token","abi":` + testContractJson + `}`
+	s.Require().NoError(s.registry.RegisterContract(Token, []byte(artifact)))
+	name, _, ok := s.registry.DecodeCall(Token, s.mintPayload())
+	s.True(ok)
+	s.Equal("mint", name)
+}
+
+func (s *AbiRegistrySuite) TestInvalidAbi() {
+	s.Error(s.registry.RegisterContract(Token, []byte(`{"abi":`)))
+	s.Error(s.registry.RegisterContract(Token, []byte(`[{"type":"function","inputs":[{"type":"foo"}]}]`)))
+	s.ErrorIs(s.registry.RegisterContract(Token, []byte(`[1]`)), ErrInvalidAbi)
+	s.Nil(s.registry.Contract(Token))
+	s.Error(s.registry.RegisterNoticeSchema("bad name", []byte(testMessageSchema)))
+	s.Error(s.registry.RegisterNoticeSchema("empty", []byte(`[]`)))
+	s.Error(s.registry.RegisterNoticeSchema("invalid", []byte(`[{"type":"foo"}]`)))
+}
+
+func (s *AbiRegistrySuite) TestDecodeNotice() {
+	s.Require().NoError(s.registry.RegisterNoticeSchema("balance", []byte(testBalanceSchema)))
+	s.Require().NoError(s.registry.RegisterNoticeSchema("message", []byte(testMessageSchema)))
+
+	balanceArgs := mustParseArguments(testBalanceSchema)
+	messageArgs := mustParseArguments(testMessageSchema)
+
+	payload, err := messageArgs.Pack("hello")
+	s.Require().NoError(err)
+	schema, fields, ok := s.registry.DecodeNotice(payload)
+	s.Require().True(ok)
+	s.Equal("message", schema)
+	s.JSONEq(`{"message":"hello"}`, string(fields))
+
+	payload, err = balanceArgs.Pack(testBeneficiary, big.NewInt(5))
+	s.Require().NoError(err)
+	schema, fields, ok = s.registry.DecodeNotice(payload)
+	s.Require().True(ok)
+	s.Equal("balance", schema)
+	s.JSONEq(`{"owner":"0x70997970C51812dc3A010C7d01b50e0d17dc79C8","balance":"5"}`, string(fields))
+
+	// the balance schema would unpack the first 64 bytes of this payload
+	_, _, ok = s.registry.DecodeNotice(append(payload, 0x01))
+	s.False(ok)
+	_, _, ok = s.registry.DecodeNotice([]byte("not abi encoded"))
+	s.False(ok)
+}
+
+func (s *AbiRegistrySuite) TestLoad() {
+	dir := filepath.Join(s.T().TempDir(), "abis")
+	s.Require().NoError(s.registry.Load(dir))
+	s.Require().NoError(s.registry.RegisterContract(Token, []byte(testContractJson)))
+	s.Require().NoError(s.registry.RegisterNoticeSchema("message", []byte(testMessageSchema)))
+	s.FileExists(filepath.Join(dir, Token.Hex()+".json"))
+	s.FileExists(filepath.Join(dir, "notices", "message.json"))
+
+	registry := NewAbiRegistry()
+	s.Require().NoError(registry.Load(dir))
+	s.NotNil(registry.Contract(Token))
+	payload, err := mustParseArguments(testMessageSchema).Pack("hello")
+	s.Require().NoError(err)
+	schema, _, ok := registry.DecodeNotice(payload)
+	s.True(ok)
+	s.Equal("message", schema)
+}
+
+func (s *AbiRegistrySuite) TestLoadInvalidFile() {
+	dir := s.T().TempDir()
+	err := os.WriteFile(filepath.Join(dir, "token.json"), []byte(testContractJson), 0644)
+	s.Require().NoError(err)
+	s.Error(s.registry.Load(dir))
+
+	dir = s.T().TempDir()
+	err = os.WriteFile(filepath.Join(dir, common.Address{}.Hex()+".json"), []byte("{"), 0644)
+	s.Require().NoError(err)
+	s.Error(s.registry.Load(dir))
+}
@@ -0,0 +1,101 @@
+package decoder
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Withdrawal methods of the token standards.
+// Each standard has its own ABI because ERC-721 and ERC-1155 share the method names.
+const (
+	erc20Json = `[
+		{"type":"function","name":"transfer","inputs":[
+			{"name":"to","type":"address"},{"name":"value","type":"uint256"}]}
+	]`
+	erc721Json = `[
+		{"type":"function","name":"safeTransferFrom","inputs":[
+			{"name":"from","type":"address"},{"name":"to","type":"address"},
+			{"name":"tokenId","type":"uint256"}]},
+		{"type":"function","name":"safeTransferFrom","inputs":[
+			{"name":"from","type":"address"},{"name":"to","type":"address"},
+			{"name":"tokenId","type":"uint256"},{"name":"data","type":"bytes"}]}
+	]`
+	erc1155Json = `[
+		{"type":"function","name":"safeTransferFrom","inputs":[
+			{"name":"from","type":"address"},{"name":"to","type":"address"},
+			{"name":"id","type":"uint256"},{"name":"value","type":"uint256"},
+			{"name":"data","type":"bytes"}]},
+		{"type":"function","name":"safeBatchTransferFrom","inputs":[
+			{"name":"from","type":"address"},{"name":"to","type":"address"},
+			{"name":"ids","type":"uint256[]"},{"name":"values","type":"uint256[]"},
+			{"name":"data","type":"bytes"}]}
+	]`
+)
+
+var withdrawalAbis = map[string]abi.ABI{
+	model.ERCX20:   mustParseAbi(erc20Json),
+	model.ERCX721:  mustParseAbi(erc721Json),
+	model.ERCX1155: mustParseAbi(erc1155Json),
+}
+
+func mustParseAbi(json string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(json))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// Fill the withdrawal fields of the voucher when its payload is a standard withdrawal.
+// A voucher without payload withdraws its value in Ether to the destination.
+// The other vouchers are left unchanged.
+func decodeWithdrawal(voucher *model.ConvenienceVoucher) {
+	payload := common.FromHex(voucher.Payload)
+	if len(payload) == 0 {
+		if voucher.Value != nil && voucher.Value.Sign() > 0 {
+			voucher.Beneficiary = voucher.Destination
+			voucher.Amount = voucher.Value
+			voucher.ERCX = model.ERCXEther
+			voucher.Label = "Ether withdrawal"
+		}
+		return
+	}
+	if len(payload) < 4 {
+		return
+	}
+	for ercx, withdrawalAbi := range withdrawalAbis {
+		method, err := withdrawalAbi.MethodById(payload[:4])
+		if err != nil {
+			continue
+		}
+		args := make(map[string]any)
+		if err := method.Inputs.UnpackIntoMap(args, payload[4:]); err != nil {
+			return
+		}
+		voucher.Contract = voucher.Destination
+		voucher.MethodSignature = method.Sig
+		voucher.ERCX = ercx
+		voucher.Beneficiary, _ = args["to"].(common.Address)
+		switch ercx {
+		case model.ERCX20:
+			voucher.Amount, _ = args["value"].(*big.Int)
+			voucher.Label = "ERC-20 transfer"
+		case model.ERCX721:
+			voucher.TokenId, _ = args["tokenId"].(*big.Int)
+			voucher.Label = "ERC-721 transfer"
+		case model.ERCX1155:
+			// the batch transfers have many ids, so only the single transfers fill them
+			voucher.TokenId, _ = args["id"].(*big.Int)
+			voucher.Amount, _ = args["value"].(*big.Int)
+			voucher.Label = "ERC-1155 transfer"
+			if method.Name == "safeBatchTransferFrom" {
+				voucher.Label = "ERC-1155 batch transfer"
+			}
+		}
+		return
+	}
+}
@@ -0,0 +1,104 @@
+package decoder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/suite"
+)
+
+var testBeneficiary = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+
+type WithdrawalSuite struct {
+	suite.Suite
+}
+
+func TestWithdrawalSuite(t *testing.T) {
+	suite.Run(t, new(WithdrawalSuite))
+}
+
+func (s *WithdrawalSuite) decode(
+	ercx string, method string, value *big.Int, args ...any,
+) *model.ConvenienceVoucher {
+	withdrawalAbi := withdrawalAbis[ercx]
+	payload, err := withdrawalAbi.Pack(method, args...)
+	s.Require().NoError(err)
+	voucher := &model.ConvenienceVoucher{
+		Destination: Token,
+		Value:       value,
+		Payload:     hexutil.Encode(payload),
+	}
+	decodeWithdrawal(voucher)
+	s.Equal(Token, voucher.Contract)
+	s.Equal(testBeneficiary, voucher.Beneficiary)
+	s.Equal(ercx, voucher.ERCX)
+	return voucher
+}
+
+func (s *WithdrawalSuite) TestEther() {
+	voucher := &model.ConvenienceVoucher{
+		Destination: testBeneficiary,
+		Value:       big.NewInt(100),
+		Payload:     "0x",
+	}
+	decodeWithdrawal(voucher)
+	s.Equal(common.Address{}, voucher.Contract)
+	s.Equal(testBeneficiary, voucher.Beneficiary)
+	s.Equal(big.NewInt(100), voucher.Amount)
+	s.Equal(model.ERCXEther, voucher.ERCX)
+	s.Equal("Ether withdrawal", voucher.Label)
+	s.Empty(voucher.MethodSignature)
+}
+
+func (s *WithdrawalSuite) TestERC20() {
+	voucher := s.decode(model.ERCX20, "transfer", nil, testBeneficiary, big.NewInt(7))
+	s.Equal(big.NewInt(7), voucher.Amount)
+	s.Nil(voucher.TokenId)
+	s.Equal("transfer(address,uint256)", voucher.MethodSignature)
+	s.Equal("ERC-20 transfer", voucher.Label)
+}
+
+func (s *WithdrawalSuite) TestERC721() {
+	voucher := s.decode(model.ERCX721, "safeTransferFrom", nil,
+		testDepositor, testBeneficiary, big.NewInt(42))
+	s.Nil(voucher.Amount)
+	s.Equal(big.NewInt(42), voucher.TokenId)
+	s.Equal("safeTransferFrom(address,address,uint256)", voucher.MethodSignature)
+	s.Equal("ERC-721 transfer", voucher.Label)
+
+	voucher = s.decode(model.ERCX721, "safeTransferFrom0", nil,
+		testDepositor, testBeneficiary, big.NewInt(43), []byte{0x01})
+	s.Equal(big.NewInt(43), voucher.TokenId)
+	s.Equal("safeTransferFrom(address,address,uint256,bytes)", voucher.MethodSignature)
+}
+
+func (s *WithdrawalSuite) TestERC1155() {
+	voucher := s.decode(model.ERCX1155, "safeTransferFrom", nil,
+		testDepositor, testBeneficiary, big.NewInt(42), big.NewInt(5), []byte{})
+	s.Equal(big.NewInt(42), voucher.TokenId)
+	s.Equal(big.NewInt(5), voucher.Amount)
+	s.Equal("safeTransferFrom(address,address,uint256,uint256,bytes)", voucher.MethodSignature)
+	s.Equal("ERC-1155 transfer", voucher.Label)
+
+	voucher = s.decode(model.ERCX1155, "safeBatchTransferFrom", nil, testDepositor, testBeneficiary,
+		[]*big.Int{big.NewInt(1)}, []*big.Int{big.NewInt(2)}, []byte{})
+	s.Nil(voucher.TokenId)
+	s.Nil(voucher.Amount)
+	s.Equal("safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)",
+		voucher.MethodSignature)
+	s.Equal("ERC-1155 batch transfer", voucher.Label)
+}
+
+func (s *WithdrawalSuite) TestUnknownCall() {
+	for _, payload := range []string{"0x", "0x11", "0xdeadbeef00", "0xa9059cbb00"} {
+		voucher := &model.ConvenienceVoucher{Destination: Token, Payload: payload}
+		decodeWithdrawal(voucher)
+		s.Equal(common.Address{}, voucher.Contract, payload)
+		s.Equal(common.Address{}, voucher.Beneficiary, payload)
+		s.Empty(voucher.ERCX, payload)
+		s.Empty(voucher.MethodSignature, payload)
+	}
+}
@@ -62,12 +62,17 @@ func ShowAddresses() {
 	for name := range contracts.Contracts {
 		names = append(names, name)
 	}
-	names = append(names, ApplicationContractName)
+	names = append(names, ApplicationContractName, AuthorityContractName)
 	contracts.Contracts[ApplicationContractName] = struct {
 		Address string "json:\"address\""
 	}{
 		Address: ApplicationAddress,
 	}
+	contracts.Contracts[AuthorityContractName] = struct {
+		Address string "json:\"address\""
+	}{
+		Address: AuthorityAddress,
+	}
 	sort.Strings(names)
 	space := 28
 	addressSpace := 42
@@ -98,7 +103,29 @@ func (w AnvilWorker) Start(ctx context.Context, ready chan<- struct{}) error {
 	if !w.Verbose {
 		server.Args = append(server.Args, "--silent")
 	}
-	return server.Start(ctx, ready)
+
+	// Deploy the application once anvil is up and only then report that the worker is ready
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	serverReady := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		result <- server.Start(ctx, serverReady)
+	}()
+	select {
+	case <-serverReady:
+	case err := <-result:
+		return err
+	}
+	rpcUrl := fmt.Sprintf("http://%s:%v", w.Address, w.Port)
+	err = DeployApplication(ctx, rpcUrl)
+	if err != nil {
+		cancel()
+		<-result
+		return fmt.Errorf("anvil: %w", err)
+	}
+	ready <- struct{}{}
+	return <-result
 }
 
 // Create a temporary directory with the state file in it.
@@ -0,0 +1,186 @@
+// Copyright (c) Gabriel de Quadros Ligneul
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package devnet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Subset of the AuthorityFactory ABI used to deploy the devnet authority.
+const authorityFactoryAbi = `[{
+	"type": "function",
+	"name": "newAuthority",
+	"inputs": [
+		{"name": "authorityOwner", "type": "address"},
+		{"name": "salt", "type": "bytes32"}
+	],
+	"outputs": [{"name": "", "type": "address"}],
+	"stateMutability": "nonpayable"
+}]`
+
+// Subset of the ApplicationFactory ABI used to deploy the devnet application.
+const applicationFactoryAbi = `[{
+	"type": "function",
+	"name": "newApplication",
+	"inputs": [
+		{"name": "consensus", "type": "address"},
+		{"name": "inputBox", "type": "address"},
+		{"name": "portals", "type": "address[]"},
+		{"name": "appOwner", "type": "address"},
+		{"name": "templateHash", "type": "bytes32"},
+		{"name": "salt", "type": "bytes32"}
+	],
+	"outputs": [{"name": "", "type": "address"}],
+	"stateMutability": "nonpayable"
+}]`
+
+// Backend used to deploy the contracts and send transactions.
+type backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// DeployApplication deploys the devnet authority and application contracts.
+// The embedded anvil state only has the factories, so the contracts are created with
+// CREATE2 to end up in AuthorityAddress and ApplicationAddress.
+// It does nothing when the application is already deployed.
+func DeployApplication(ctx context.Context, rpcUrl string) error {
+	client, err := ethclient.DialContext(ctx, rpcUrl)
+	if err != nil {
+		return fmt.Errorf("dial to %v: %w", rpcUrl, err)
+	}
+	defer client.Close()
+	return deployApplication(ctx, client)
+}
+
+func deployApplication(ctx context.Context, client backend) error {
+	deployed, err := hasCode(ctx, client, ApplicationAddress)
+	if err != nil || deployed {
+		return err
+	}
+	deployed, err = hasCode(ctx, client, AuthorityAddress)
+	if err != nil {
+		return err
+	}
+	owner := common.HexToAddress(SenderAddress)
+	var salt [32]byte
+	if !deployed {
+		slog.Debug("devnet: deploying authority", "address", AuthorityAddress)
+		err = transact(ctx, client, AuthorityFactoryAddress, authorityFactoryAbi,
+			"newAuthority", owner, salt)
+		if err != nil {
+			return fmt.Errorf("deploy authority: %w", err)
+		}
+	}
+	slog.Debug("devnet: deploying application", "address", ApplicationAddress)
+	portals := []common.Address{
+		common.HexToAddress(EtherPortalAddress),
+		common.HexToAddress(ERC20PortalAddress),
+		common.HexToAddress(ERC721PortalAddress),
+		common.HexToAddress(ERC1155SinglePortalAddress),
+		common.HexToAddress(ERC1155BatchPortalAddress),
+	}
+	var templateHash [32]byte
+	err = transact(ctx, client, ApplicationFactoryAddress, applicationFactoryAbi,
+		"newApplication", common.HexToAddress(AuthorityAddress),
+		common.HexToAddress(InputBoxAddress), portals, owner, templateHash, salt)
+	if err != nil {
+		return fmt.Errorf("deploy application: %w", err)
+	}
+	deployed, err = hasCode(ctx, client, ApplicationAddress)
+	if err != nil {
+		return err
+	}
+	if !deployed {
+		return fmt.Errorf("application was not deployed to %v", ApplicationAddress)
+	}
+	return nil
+}
+
+// Check whether there is a contract in the address.
+func hasCode(ctx context.Context, client backend, address string) (bool, error) {
+	code, err := client.CodeAt(ctx, common.HexToAddress(address), nil)
+	if err != nil {
+		return false, fmt.Errorf("get code at %v: %w", address, err)
+	}
+	return len(code) > 0, nil
+}
+
+// Call the contract method with a transaction from the devnet sender and wait for it.
+func transact(
+	ctx context.Context,
+	client backend,
+	address string,
+	contractAbi string,
+	method string,
+	params ...interface{},
+) error {
+	parsed, err := abi.JSON(strings.NewReader(contractAbi))
+	if err != nil {
+		return fmt.Errorf("parse abi: %w", err)
+	}
+	contract := bind.NewBoundContract(common.HexToAddress(address), parsed, client, client, client)
+	txOpts, err := newTransactOpts(ctx, client, SenderPrivateKey)
+	if err != nil {
+		return err
+	}
+	tx, err := contract.Transact(txOpts, method, params...)
+	if err != nil {
+		return fmt.Errorf("%v: %w", method, err)
+	}
+	receipt, err := waitMined(ctx, client, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status == 0 {
+		return fmt.Errorf("%v: transaction was not accepted", method)
+	}
+	return nil
+}
+
+// Create the options to send a transaction from the account of the private key.
+func newTransactOpts(
+	ctx context.Context,
+	client backend,
+	privateKeyHex string,
+) (*bind.TransactOpts, error) {
+	privateKey, err := crypto.ToECDSA(common.Hex2Bytes(privateKeyHex[2:]))
+	if err != nil {
+		return nil, fmt.Errorf("create private key: %w", err)
+	}
+
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get chain id: %w", err)
+	}
+
+	txOpts, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
+	if err != nil {
+		return nil, fmt.Errorf("create transactor: %w", err)
+	}
+	nonce, err := client.PendingNonceAt(ctx, txOpts.From)
+	if err != nil {
+		return nil, fmt.Errorf("get nonce: %w", err)
+	}
+	txOpts.Nonce = big.NewInt(int64(nonce))
+	txOpts.Value = big.NewInt(0)
+	txOpts.GasLimit = GasLimit
+	txOpts.GasPrice, err = client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get gas price: %w", err)
+	}
+	txOpts.Context = ctx
+	return txOpts, nil
+}
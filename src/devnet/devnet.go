@@ -8,13 +8,32 @@ package devnet
 const InputBoxAddress = "0x58Df21fE097d4bE5dCf61e01d9ea3f6B81c2E1dB"
 
 const ApplicationContractName = "CartesiDApp"
+const AuthorityContractName = "Authority"
 
 // Application address in devnet.
 // sunodo v0.10.4
 // const ApplicationAddress = "0x70ac08179605AF2D9e75782b8DEcDD3c22aA4D0C"
 
 // sunodo v0.11.2
-const ApplicationAddress = "0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e"
+// const ApplicationAddress = "0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e"
+
+// Deployed by DeployApplication with the ApplicationFactory.
+const ApplicationAddress = "0x45290f5A64Ea31887C7bc25fB3269173bCd01093"
+
+// Authority of the application, owned by the sender.
+// Deployed by DeployApplication with the AuthorityFactory.
+const AuthorityAddress = "0x0b03f7D2e3E80b27e532bcbb512B0b4590107967"
+
+// Factories in devnet.
+const AuthorityFactoryAddress = "0xFc6c1Fc6546898eb2f9cb7De360B3eA52E601D46"
+const ApplicationFactoryAddress = "0x159876e08d642c2Fe9a52804756189E1DbF497F0"
+
+// Portals in devnet.
+const EtherPortalAddress = "0x1733b13aAbcEcf3464157Bd7954Bd7e4Cf91Ce22"
+const ERC20PortalAddress = "0xCF3A2BA57D28e7A4B9E2c5E5bcFff65A7aef6E98"
+const ERC721PortalAddress = "0x2e2f6166170A9C7f8b95cC5400A39b62C46e401f"
+const ERC1155SinglePortalAddress = "0x60ab2e7b160714A0B26Dd4362389e6942F52510F"
+const ERC1155BatchPortalAddress = "0x12AD737DeAFD7AD2005d6f9c93F6eE2777846405"
 
 // Foundry test mnemonic.
 const TestMnemonic = "test test test test test test test test test test test junk"
@@ -25,5 +44,20 @@ const SenderAddress = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
 // Private key of the sender.
 const SenderPrivateKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
 
+// Private keys of the test accounts derived from the test mnemonic.
+// The first one is the sender.
+var TestPrivateKeys = []string{
+	SenderPrivateKey,
+	"0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d",
+	"0x5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a",
+	"0x7c852118294e51e653712a81e05800f419141751be58f605c371e15141b007a6",
+	"0x47e179ec197488593b187f80a00eb0da91f1b9d0b13f8733639f19c30a34926a",
+	"0x8b3a350cf5c34c9194ca85829a2df0ec3153be0318b5e2d3348e872092edffba",
+	"0x92db14e403b83dfe3df233f83dfa3a0d7096f21ca9b0d6d6b8d88b2b4ec1564e",
+	"0x4bbbf85ce3377467afe5d46f804f221813b2bb87f24d81f60f1fcdbf7cbf4356",
+	"0xdbda1821b80551c9d65939329250298aa3472ba22feea921c0cf5d620ea67b97",
+	"0x2a871d0798f97d79848a013d4936a73bf4cc922c825d33c1cf7073dff6d409c6",
+}
+
 // Gas limit when sending transactions.
 const GasLimit = 30_000_000
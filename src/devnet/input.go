@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"math/big"
 	"time"
 
 	"github.com/calindra/rollups-server/src/contracts"
@@ -16,7 +15,6 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
@@ -32,30 +30,9 @@ func AddInput(ctx context.Context, rpcUrl string, payload []byte) error {
 		return fmt.Errorf("dial to %v: %w", rpcUrl, err)
 	}
 
-	privateKey, err := crypto.ToECDSA(common.Hex2Bytes(SenderPrivateKey[2:]))
+	txOpts, err := newTransactOpts(ctx, client, SenderPrivateKey)
 	if err != nil {
-		return fmt.Errorf("create private key: %w", err)
-	}
-
-	chainId, err := client.ChainID(ctx)
-	if err != nil {
-		return fmt.Errorf("get chain id: %w", err)
-	}
-
-	txOpts, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
-	if err != nil {
-		return fmt.Errorf("create transactor: %w", err)
-	}
-	nonce, err := client.PendingNonceAt(ctx, common.HexToAddress(SenderAddress))
-	if err != nil {
-		return fmt.Errorf("get nonce: %w", err)
-	}
-	txOpts.Nonce = big.NewInt(int64(nonce))
-	txOpts.Value = big.NewInt(0)
-	txOpts.GasLimit = GasLimit
-	txOpts.GasPrice, err = client.SuggestGasPrice(ctx)
-	if err != nil {
-		return fmt.Errorf("get gas price: %w", err)
+		return err
 	}
 
 	inputBox, err := contracts.NewInputBox(common.HexToAddress(InputBoxAddress), client)
@@ -104,7 +81,7 @@ func GetInputAdded(ctx context.Context, rpcUrl string) ([]*contracts.InputBoxInp
 // It stops waiting when the context is canceled.
 func waitMined(
 	ctx context.Context,
-	client *ethclient.Client,
+	client bind.DeployBackend,
 	tx *types.Transaction,
 ) (*types.Receipt, error) {
 	const pollFrequency = 33 * time.Millisecond
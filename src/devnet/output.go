@@ -0,0 +1,159 @@
+// Copyright (c) Gabriel de Quadros Ligneul
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package devnet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/calindra/rollups-server/src/contracts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Result of executing an output in the application contract.
+type ExecutionResult struct {
+	// Receipt of the transaction; nil when the execution reverts before it is sent.
+	Receipt *types.Receipt
+	// Decoded revert reason; empty when the execution succeeds.
+	RevertReason string
+}
+
+// ExecuteOutput calls executeOutput in the application with the given test account.
+// The execution is simulated first, so an output that cannot be executed returns the
+// revert reason without sending a transaction.
+// This function should be used in the devnet environment.
+func ExecuteOutput(
+	ctx context.Context,
+	rpcUrl string,
+	applicationAddress common.Address,
+	account int,
+	output []byte,
+	proof contracts.OutputValidityProof,
+) (*ExecutionResult, error) {
+	if account < 0 || account >= len(TestPrivateKeys) {
+		return nil, fmt.Errorf("invalid test account %v", account)
+	}
+
+	client, err := ethclient.DialContext(ctx, rpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("dial to %v: %w", rpcUrl, err)
+	}
+	defer client.Close()
+	return executeOutput(ctx, client, applicationAddress, account, output, proof)
+}
+
+func executeOutput(
+	ctx context.Context,
+	client backend,
+	applicationAddress common.Address,
+	account int,
+	output []byte,
+	proof contracts.OutputValidityProof,
+) (*ExecutionResult, error) {
+	txOpts, err := newTransactOpts(ctx, client, TestPrivateKeys[account])
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := contracts.ApplicationMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("parse abi: %w", err)
+	}
+	data, err := parsed.Pack("executeOutput", output, proof)
+	if err != nil {
+		return nil, fmt.Errorf("pack execute output: %w", err)
+	}
+	call := ethereum.CallMsg{
+		From: txOpts.From,
+		To:   &applicationAddress,
+		Data: data,
+	}
+	_, err = client.CallContract(ctx, call, nil)
+	if reason, ok := revertReason(parsed, err); ok {
+		return &ExecutionResult{RevertReason: reason}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("simulate execute output: %w", err)
+	}
+
+	application, err := contracts.NewApplication(applicationAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("bind application: %w", err)
+	}
+	tx, err := application.ExecuteOutput(txOpts, output, proof)
+	if err != nil {
+		return nil, fmt.Errorf("execute output: %w", err)
+	}
+	receipt, err := waitMined(ctx, client, tx)
+	if err != nil {
+		return nil, err
+	}
+	result := &ExecutionResult{Receipt: receipt}
+	if receipt.Status == types.ReceiptStatusFailed {
+		// Replay the call on top of the previous block to find out why it reverted
+		previous := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+		_, err = client.CallContract(ctx, call, previous)
+		result.RevertReason, _ = revertReason(parsed, err)
+		if result.RevertReason == "" {
+			result.RevertReason = "transaction reverted"
+		}
+	}
+	return result, nil
+}
+
+// Get the revert reason from the error of a contract call.
+// Return false if the error is not a revert.
+func revertReason(contractAbi *abi.ABI, err error) (string, bool) {
+	var dataErr rpc.DataError
+	if err == nil || !errors.As(err, &dataErr) {
+		return "", false
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err.Error(), true
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil {
+		return err.Error(), true
+	}
+	return DecodeRevert(contractAbi, data), true
+}
+
+// DecodeRevert decodes the revert data using the custom errors of the contract ABI.
+// It also decodes the Error(string) reverts; otherwise, it returns the data in hex.
+func DecodeRevert(contractAbi *abi.ABI, data []byte) string {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+	if len(data) >= 4 && contractAbi != nil {
+		for _, abiError := range contractAbi.Errors {
+			if !bytes.Equal(abiError.ID[:4], data[:4]) {
+				continue
+			}
+			values, err := abiError.Inputs.Unpack(data[4:])
+			if err != nil {
+				break
+			}
+			args := make([]string, len(values))
+			for i, value := range values {
+				if b, ok := value.([]byte); ok {
+					args[i] = hexutil.Encode(b)
+				} else {
+					args[i] = fmt.Sprint(value)
+				}
+			}
+			return fmt.Sprintf("%s(%s)", abiError.Name, strings.Join(args, ", "))
+		}
+	}
+	return hexutil.Encode(data)
+}
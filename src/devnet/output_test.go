@@ -0,0 +1,35 @@
+// Copyright (c) Gabriel de Quadros Ligneul
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package devnet
+
+import (
+	"testing"
+
+	"github.com/calindra/rollups-server/src/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeRevert(t *testing.T) {
+	parsed, err := contracts.ApplicationMetaData.GetAbi()
+	require.NoError(t, err)
+
+	notReexecutableError := parsed.Errors["OutputNotReexecutable"]
+	args, err := notReexecutableError.Inputs.Pack([]byte{0xbe, 0xef})
+	require.NoError(t, err)
+	notReexecutable := append(notReexecutableError.ID.Bytes()[:4], args...)
+	require.Equal(t, "OutputNotReexecutable(0xbeef)", DecodeRevert(parsed, notReexecutable))
+
+	incorrectHash := parsed.Errors["IncorrectEpochHash"].ID.Bytes()[:4]
+	require.Equal(t, "IncorrectEpochHash()", DecodeRevert(parsed, incorrectHash))
+
+	// Error(string) with the message "oops"
+	errorString := common.FromHex("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"6f6f707300000000000000000000000000000000000000000000000000000000")
+	require.Equal(t, "oops", DecodeRevert(parsed, errorString))
+
+	require.Equal(t, "0xdeadbeef", DecodeRevert(parsed, common.FromHex("0xdeadbeef")))
+}
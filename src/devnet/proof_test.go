@@ -0,0 +1,133 @@
+package devnet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/calindra/rollups-server/src/contracts"
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Subset of the Authority ABI used to claim the epochs in the tests.
+const testAuthorityAbi = `[{
+	"type": "function",
+	"name": "submitClaim",
+	"inputs": [
+		{"name": "appContract", "type": "address"},
+		{"name": "inputRange", "type": "tuple", "components": [
+			{"name": "firstIndex", "type": "uint64"},
+			{"name": "lastIndex", "type": "uint64"}
+		]},
+		{"name": "epochHash", "type": "bytes32"}
+	],
+	"outputs": [],
+	"stateMutability": "nonpayable"
+}]`
+
+// Start anvil with the devnet application in the given port and return its URL.
+// The worker stops when the context is canceled.
+func (s *AnvilSuite) startAnvil(ctx context.Context, port int) string {
+	w := AnvilWorker{
+		Address: AnvilDefaultAddress,
+		Port:    port,
+	}
+	ready := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		result <- w.Start(ctx, ready)
+	}()
+	select {
+	case <-ready:
+	case err := <-result:
+		s.Require().NoError(err)
+	case <-ctx.Done():
+		s.Require().NoError(ctx.Err())
+	}
+	return fmt.Sprintf("http://%s:%v", AnvilDefaultAddress, port)
+}
+
+// Check the proofs of the model against the application contract.
+// The contract only accepts them if the pristine leaf and the heights of the merkle trees
+// match the ones of LibOutputValidityProof.
+func (s *AnvilSuite) TestValidateOutputProof() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	rpcUrl := s.startAnvil(ctx, AnvilDefaultPort+101)
+	client, err := ethclient.DialContext(ctx, rpcUrl)
+	s.Require().NoError(err)
+	defer client.Close()
+
+	db := sqlx.MustConnect("sqlite3", path.Join(s.T().TempDir(), "proof.sqlite3"))
+	defer db.Close()
+	model := mdl.NewAppModel(nil, db)
+	sender := common.HexToAddress(SenderAddress)
+	for i := 0; i < 3; i++ {
+		err := model.AddAdvanceInput(sender, []byte{byte(i)}, uint64(i+1), time.Now(), i)
+		s.Require().NoError(err)
+	}
+	// the first input has no outputs, the second has a voucher and a notice and
+	// the third has a notice
+	_, err = model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = model.AddVoucher(sender, big.NewInt(0), []byte{0xbe, 0xef})
+	s.Require().NoError(err)
+	_, err = model.AddNotice([]byte{0xaa})
+	s.Require().NoError(err)
+	_, err = model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = model.AddNotice([]byte{0xbb})
+	s.Require().NoError(err)
+	_, err = model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	epoch, err := model.CloseEpoch()
+	s.Require().NoError(err)
+	s.Require().NotNil(epoch)
+	s.Require().Equal(mdl.EpochStatusProved, epoch.Status)
+
+	// claim the epoch as the owner of the authority
+	parsed, err := abi.JSON(strings.NewReader(testAuthorityAbi))
+	s.Require().NoError(err)
+	authority := bind.NewBoundContract(
+		common.HexToAddress(AuthorityAddress), parsed, client, client, client)
+	txOpts, err := newTransactOpts(ctx, client, SenderPrivateKey)
+	s.Require().NoError(err)
+	inputRange := contracts.InputRange{
+		FirstIndex: epoch.FirstInputIndex,
+		LastIndex:  epoch.LastInputIndex,
+	}
+	tx, err := authority.Transact(txOpts, "submitClaim",
+		common.HexToAddress(ApplicationAddress), inputRange, epoch.EpochHash())
+	s.Require().NoError(err)
+	receipt, err := waitMined(ctx, client, tx)
+	s.Require().NoError(err)
+	s.Require().Equal(types.ReceiptStatusSuccessful, receipt.Status)
+
+	application, err := contracts.NewApplication(common.HexToAddress(ApplicationAddress), client)
+	s.Require().NoError(err)
+	callOpts := &bind.CallOpts{Context: ctx}
+	var proof *mdl.Proof
+	for _, output := range [][2]uint64{{1, 0}, {1, 1}, {2, 0}} {
+		proof, err = model.GetProof(output[0], output[1])
+		s.Require().NoError(err)
+		s.Require().NotNil(proof)
+		err = application.ValidateOutput(callOpts, proof.Output, proof.ValidityProof())
+		s.NoError(err, "output %d of input %d", output[1], output[0])
+	}
+
+	// a proof of another output is rejected
+	err = application.ValidateOutput(callOpts, []byte{0xcc}, proof.ValidityProof())
+	s.Error(err)
+}
@@ -0,0 +1,88 @@
+// This package contains the bindings for the epochs OpenAPI spec and the worker that closes the epochs.
+package epoch
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen -config=oapi.yaml ../../api/epochs.yaml
+
+import (
+	"net/http"
+
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/labstack/echo/v4"
+)
+
+// Register the epochs API to echo
+func Register(e *echo.Echo, model *mdl.AppModel) {
+	var epochAPI ServerInterface = &EpochAPI{model}
+	RegisterHandlers(e, epochAPI)
+}
+
+// Shared struct for request handlers.
+type EpochAPI struct {
+	model *mdl.AppModel
+}
+
+// Handle GET requests to /epochs.
+func (a *EpochAPI) GetEpochs(c echo.Context) error {
+	epochs, err := a.model.GetEpochs()
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	resp := make([]Epoch, len(epochs))
+	for i, epoch := range epochs {
+		resp[i] = convertEpoch(epoch)
+	}
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Handle GET requests to /epochs/{epochIndex}.
+func (a *EpochAPI) GetEpoch(c echo.Context, epochIndex uint64) error {
+	epoch, err := a.model.GetEpoch(epochIndex)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	if epoch == nil {
+		return c.String(http.StatusNotFound, "epoch not found")
+	}
+	resp := convertEpoch(*epoch)
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Convert model epoch to API type.
+func convertEpoch(epoch mdl.Epoch) Epoch {
+	resp := Epoch{
+		Index:      epoch.Index,
+		FirstBlock: epoch.FirstBlock,
+		OpenedAt:   epoch.OpenedAt.UnixMilli(),
+	}
+	switch epoch.Status {
+	case mdl.EpochStatusOpen:
+		resp.Status = Open
+	case mdl.EpochStatusClosed:
+		resp.Status = Closed
+	case mdl.EpochStatusProved:
+		resp.Status = Proved
+	case mdl.EpochStatusClaimed:
+		resp.Status = Claimed
+	default:
+		panic("invalid epoch status")
+	}
+	if epoch.Status >= mdl.EpochStatusClosed {
+		closedAt := epoch.ClosedAt.UnixMilli()
+		resp.FirstInputIndex = &epoch.FirstInputIndex
+		resp.LastInputIndex = &epoch.LastInputIndex
+		resp.ClosedAt = &closedAt
+	}
+	if epoch.Status >= mdl.EpochStatusProved {
+		outputsEpochRootHash := epoch.OutputsEpochRootHash.Hex()
+		machineStateHash := epoch.MachineStateHash.Hex()
+		epochHash := epoch.EpochHash().Hex()
+		resp.OutputsEpochRootHash = &outputsEpochRootHash
+		resp.MachineStateHash = &machineStateHash
+		resp.EpochHash = &epochHash
+	}
+	if epoch.Status >= mdl.EpochStatusClaimed {
+		claimTransactionHash := epoch.ClaimTransactionHash.Hex()
+		resp.ClaimTransactionHash = &claimTransactionHash
+	}
+	return resp
+}
@@ -0,0 +1,136 @@
+package epoch
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+const testTimeout = 5 * time.Second
+
+type EpochSuite struct {
+	suite.Suite
+	model   *mdl.AppModel
+	server  *httptest.Server
+	client  *ClientWithResponses
+	tempDir string
+}
+
+func (s *EpochSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	tempDir, err := os.MkdirTemp("", "")
+	s.NoError(err)
+	s.tempDir = tempDir
+	db := sqlx.MustConnect("sqlite3", path.Join(tempDir, "epoch.sqlite3"))
+	s.model = mdl.NewAppModel(nil, db)
+	e := echo.New()
+	Register(e, s.model)
+	s.server = httptest.NewServer(e)
+	client, err := NewClientWithResponses(s.server.URL)
+	s.NoError(err)
+	s.client = client
+}
+
+func (s *EpochSuite) TearDownTest() {
+	s.server.Close()
+	os.RemoveAll(s.tempDir)
+}
+
+func TestEpochSuite(t *testing.T) {
+	suite.Run(t, new(EpochSuite))
+}
+
+func (s *EpochSuite) TestGetEpochs() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	err := s.model.AddAdvanceInput(common.Address{}, []byte{0xde, 0xad}, 7, time.Now(), 0)
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.CloseEpoch()
+	s.Require().NoError(err)
+	err = s.model.AddAdvanceInput(common.Address{}, []byte{0xbe, 0xef}, 8, time.Now(), 1)
+	s.Require().NoError(err)
+
+	resp, err := s.client.GetEpochsWithResponse(ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode())
+	epochs := *resp.JSON200
+	s.Require().Len(epochs, 2)
+	s.Equal(Proved, epochs[0].Status)
+	s.Equal(uint64(7), epochs[0].FirstBlock)
+	s.Equal(uint64(0), *epochs[0].LastInputIndex)
+	s.NotNil(epochs[0].OutputsEpochRootHash)
+	s.Equal(Open, epochs[1].Status)
+	s.Nil(epochs[1].LastInputIndex)
+	s.Nil(epochs[1].OutputsEpochRootHash)
+}
+
+func (s *EpochSuite) TestGetEpochNotFound() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	resp, err := s.client.GetEpochWithResponse(ctx, 0)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+}
+
+func (s *EpochSuite) TestWorkerClosesEpoch() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	s.model.EpochDuration = 50 * time.Millisecond
+	err := s.model.AddAdvanceInput(common.Address{}, []byte{0xde, 0xad}, 1, time.Now(), 0)
+	s.Require().NoError(err)
+
+	worker := EpochWorker{Model: s.model, CheckInterval: 10 * time.Millisecond}
+	ready := make(chan struct{}, 1)
+	go func() {
+		_ = worker.Start(ctx, ready)
+	}()
+	<-ready
+	s.Eventually(func() bool {
+		epoch, err := s.model.GetEpoch(0)
+		return err == nil && epoch.Status == mdl.EpochStatusClosed
+	}, testTimeout, 10*time.Millisecond)
+}
+
+func (s *EpochSuite) TestGetClaimedEpoch() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	err := s.model.AddAdvanceInput(common.Address{}, []byte{0xde, 0xad}, 7, time.Now(), 0)
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.CloseEpoch()
+	s.Require().NoError(err)
+	txHash := common.HexToHash("0xfa")
+	err = s.model.SetEpochClaimed(0, txHash)
+	s.Require().NoError(err)
+
+	resp, err := s.client.GetEpochWithResponse(ctx, 0)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode())
+	epoch := resp.JSON200
+	s.Equal(Claimed, epoch.Status)
+	s.Equal(txHash.Hex(), *epoch.ClaimTransactionHash)
+	s.Require().NotNil(epoch.EpochHash)
+	root := common.HexToHash(*epoch.OutputsEpochRootHash)
+	s.Equal(crypto.Keccak256Hash(root[:], common.Hash{}.Bytes()).Hex(), *epoch.EpochHash)
+}
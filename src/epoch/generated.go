@@ -0,0 +1,478 @@
+// Package epoch provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.3.0 DO NOT EDIT.
+package epoch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/runtime"
+)
+
+// Defines values for EpochStatus.
+const (
+	Claimed EpochStatus = "Claimed"
+	Closed  EpochStatus = "Closed"
+	Open    EpochStatus = "Open"
+	Proved  EpochStatus = "Proved"
+)
+
+// Epoch defines model for Epoch.
+type Epoch struct {
+	// ClaimTransactionHash A 32-byte hash in hex.
+	// The hashes of the epoch are only set when the epoch is proved;
+	// the claim transaction hash is only set when the epoch is claimed.
+	ClaimTransactionHash *Hash `json:"claim_transaction_hash,omitempty"`
+
+	// ClosedAt Unix timestamp in milliseconds of when the epoch was closed.
+	ClosedAt *int64 `json:"closed_at,omitempty"`
+
+	// EpochHash A 32-byte hash in hex.
+	// The hashes of the epoch are only set when the epoch is proved;
+	// the claim transaction hash is only set when the epoch is claimed.
+	EpochHash *Hash `json:"epoch_hash,omitempty"`
+
+	// FirstBlock Block of the first input of the epoch.
+	FirstBlock uint64 `json:"first_block"`
+
+	// FirstInputIndex First input of the epoch; only set when the epoch is closed.
+	FirstInputIndex *uint64 `json:"first_input_index,omitempty"`
+	Index           uint64  `json:"index"`
+
+	// LastInputIndex Last input of the epoch; only set when the epoch is closed.
+	LastInputIndex *uint64 `json:"last_input_index,omitempty"`
+
+	// MachineStateHash A 32-byte hash in hex.
+	// The hashes of the epoch are only set when the epoch is proved;
+	// the claim transaction hash is only set when the epoch is claimed.
+	MachineStateHash *Hash `json:"machine_state_hash,omitempty"`
+
+	// OpenedAt Unix timestamp in milliseconds of when the epoch was opened.
+	OpenedAt int64 `json:"opened_at"`
+
+	// OutputsEpochRootHash A 32-byte hash in hex.
+	// The hashes of the epoch are only set when the epoch is proved;
+	// the claim transaction hash is only set when the epoch is claimed.
+	OutputsEpochRootHash *Hash       `json:"outputs_epoch_root_hash,omitempty"`
+	Status               EpochStatus `json:"status"`
+}
+
+// EpochStatus defines model for EpochStatus.
+type EpochStatus string
+
+// Error Detailed error message.
+type Error = string
+
+// Hash A 32-byte hash in hex.
+// The hashes of the epoch are only set when the epoch is proved;
+// the claim transaction hash is only set when the epoch is claimed.
+type Hash = string
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// GetEpochs request
+	GetEpochs(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetEpoch request
+	GetEpoch(ctx context.Context, epochIndex uint64, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) GetEpochs(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetEpochsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetEpoch(ctx context.Context, epochIndex uint64, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetEpochRequest(c.Server, epochIndex)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewGetEpochsRequest generates requests for GetEpochs
+func NewGetEpochsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/epochs")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetEpochRequest generates requests for GetEpoch
+func NewGetEpochRequest(server string, epochIndex uint64) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "epochIndex", runtime.ParamLocationPath, epochIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/epochs/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// GetEpochsWithResponse request
+	GetEpochsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetEpochsResponse, error)
+
+	// GetEpochWithResponse request
+	GetEpochWithResponse(ctx context.Context, epochIndex uint64, reqEditors ...RequestEditorFn) (*GetEpochResponse, error)
+}
+
+type GetEpochsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]Epoch
+}
+
+// Status returns HTTPResponse.Status
+func (r GetEpochsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetEpochsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetEpochResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Epoch
+}
+
+// Status returns HTTPResponse.Status
+func (r GetEpochResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetEpochResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// GetEpochsWithResponse request returning *GetEpochsResponse
+func (c *ClientWithResponses) GetEpochsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetEpochsResponse, error) {
+	rsp, err := c.GetEpochs(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetEpochsResponse(rsp)
+}
+
+// GetEpochWithResponse request returning *GetEpochResponse
+func (c *ClientWithResponses) GetEpochWithResponse(ctx context.Context, epochIndex uint64, reqEditors ...RequestEditorFn) (*GetEpochResponse, error) {
+	rsp, err := c.GetEpoch(ctx, epochIndex, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetEpochResponse(rsp)
+}
+
+// ParseGetEpochsResponse parses an HTTP response from a GetEpochsWithResponse call
+func ParseGetEpochsResponse(rsp *http.Response) (*GetEpochsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetEpochsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []Epoch
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetEpochResponse parses an HTTP response from a GetEpochWithResponse call
+func ParseGetEpochResponse(rsp *http.Response) (*GetEpochResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetEpochResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Epoch
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Get all the epochs
+	// (GET /epochs)
+	GetEpochs(ctx echo.Context) error
+	// Get an epoch
+	// (GET /epochs/{epochIndex})
+	GetEpoch(ctx echo.Context, epochIndex uint64) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// GetEpochs converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEpochs(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEpochs(ctx)
+	return err
+}
+
+// GetEpoch converts echo context to params.
+func (w *ServerInterfaceWrapper) GetEpoch(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "epochIndex" -------------
+	var epochIndex uint64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "epochIndex", ctx.Param("epochIndex"), &epochIndex, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter epochIndex: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetEpoch(ctx, epochIndex)
+	return err
+}
+
+// This is a simple interface which specifies echo.Route addition functions which
+// are present on both echo.Echo and echo.Group, since we want to allow using
+// either of them for path registration
+type EchoRouter interface {
+	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// RegisterHandlers adds each server route to the EchoRouter.
+func RegisterHandlers(router EchoRouter, si ServerInterface) {
+	RegisterHandlersWithBaseURL(router, si, "")
+}
+
+// Registers handlers, and prepends BaseURL to the paths, so that the paths
+// can be served under a prefix.
+func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL string) {
+
+	wrapper := ServerInterfaceWrapper{
+		Handler: si,
+	}
+
+	router.GET(baseURL+"/epochs", wrapper.GetEpochs)
+	router.GET(baseURL+"/epochs/:epochIndex", wrapper.GetEpoch)
+
+}
@@ -0,0 +1,44 @@
+package epoch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mdl "github.com/calindra/rollups-server/src/model"
+)
+
+// Default interval between checks of the epoch duration.
+const DefaultCheckInterval = time.Second
+
+// This worker closes the open epoch when its duration elapses.
+// The block count rule is checked by the model when the inputs arrive.
+type EpochWorker struct {
+	Model         *mdl.AppModel
+	CheckInterval time.Duration
+}
+
+func (w EpochWorker) String() string {
+	return "epoch"
+}
+
+func (w EpochWorker) Start(ctx context.Context, ready chan<- struct{}) error {
+	interval := w.CheckInterval
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	ready <- struct{}{}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			_, err := w.Model.CloseEpochIfDue(now)
+			if err != nil {
+				return fmt.Errorf("epoch: %w", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) Gabriel de Quadros Ligneul
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+// This package contains the bindings for the inspect OpenAPI spec.
+package inspect
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen -config=oapi.yaml ../../api/inspect.yaml
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/labstack/echo/v4"
+)
+
+// Default time an inspect request waits for the DApp to finish the inspect.
+const DefaultInspectTimeout = 10 * time.Second
+
+// Register the inspect API to echo
+func Register(e *echo.Echo, model *mdl.AppModel, inspectTimeout time.Duration) {
+	if inspectTimeout <= 0 {
+		inspectTimeout = DefaultInspectTimeout
+	}
+	var inspectAPI ServerInterface = &InspectAPI{model, inspectTimeout}
+	RegisterHandlers(e, inspectAPI)
+}
+
+// Shared struct for request handlers.
+type InspectAPI struct {
+	model          *mdl.AppModel
+	inspectTimeout time.Duration
+}
+
+// Handle POST requests to /inspect.
+func (a *InspectAPI) InspectPost(c echo.Context) error {
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	return a.inspect(c, payload)
+}
+
+// Handle GET requests to /inspect/{payload}.
+func (a *InspectAPI) Inspect(c echo.Context, payload string) error {
+	return a.inspect(c, []byte(payload))
+}
+
+// Send the inspect input to the model and wait until it is completed.
+func (a *InspectAPI) inspect(c echo.Context, payload []byte) error {
+	index, err := a.model.AddInspectInput(payload)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+
+	// check the input each time the model finishes an input until the DApp finishes the inspect
+	ctx := c.Request().Context()
+	wait, cancel := context.WithTimeout(ctx, a.inspectTimeout)
+	defer cancel()
+	for {
+		completed := a.model.CompletedInputs()
+		input, err := a.model.GetInspectInput(index)
+		if err != nil {
+			return c.String(http.StatusInternalServerError, err.Error())
+		}
+		if input.Status != mdl.CompletionStatusUnprocessed {
+			resp, err := convertInput(input)
+			if err != nil {
+				return c.String(http.StatusInternalServerError, err.Error())
+			}
+			return c.JSON(http.StatusOK, &resp)
+		}
+		select {
+		case <-wait.Done():
+			if ctx.Err() != nil {
+				return c.String(http.StatusInternalServerError, ctx.Err().Error())
+			}
+			return c.String(http.StatusServiceUnavailable, "inspect timed out")
+		case <-completed:
+		}
+	}
+}
+
+// Convert model input to API type.
+func convertInput(input mdl.InspectInput) (InspectResult, error) {
+	var status CompletionStatus
+	switch input.Status {
+	case mdl.CompletionStatusUnprocessed:
+		status = Unprocessed
+	case mdl.CompletionStatusAccepted:
+		status = Accepted
+	case mdl.CompletionStatusRejected:
+		status = Rejected
+	case mdl.CompletionStatusException:
+		status = Exception
+	default:
+		return InspectResult{}, fmt.Errorf("invalid completion status: %v", input.Status)
+	}
+
+	reports := make([]Report, len(input.Reports))
+	for i, report := range input.Reports {
+		reports[i] = Report{
+			Payload: hexutil.Encode(report.Payload),
+		}
+	}
+
+	result := InspectResult{
+		Status:              status,
+		Reports:             reports,
+		ProcessedInputCount: input.ProcessedInputCount,
+	}
+	if len(input.Exception) > 0 {
+		exception := hexutil.Encode(input.Exception)
+		result.ExceptionPayload = &exception
+	}
+	return result, nil
+}
@@ -0,0 +1,143 @@
+package inspect
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/sequencer"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+const testTimeout = 5 * time.Second
+
+type InspectSuite struct {
+	suite.Suite
+	model   *mdl.AppModel
+	server  *httptest.Server
+	client  *ClientWithResponses
+	tempDir string
+}
+
+func (s *InspectSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	tempDir, err := os.MkdirTemp("", "")
+	s.NoError(err)
+	s.tempDir = tempDir
+	db := sqlx.MustConnect("sqlite3", path.Join(tempDir, "inspect.sqlite3"))
+	s.model = mdl.NewAppModel(nil, db)
+	e := echo.New()
+	Register(e, s.model, testTimeout)
+	s.server = httptest.NewServer(e)
+	client, err := NewClientWithResponses(s.server.URL)
+	s.NoError(err)
+	s.client = client
+}
+
+func (s *InspectSuite) TearDownTest() {
+	s.server.Close()
+	os.RemoveAll(s.tempDir)
+}
+
+func TestInspectSuite(t *testing.T) {
+	suite.Run(t, new(InspectSuite))
+}
+
+func (s *InspectSuite) TestInspectPost() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	result := make(chan *InspectPostResponse)
+	go func() {
+		resp, err := s.client.InspectPostWithBodyWithResponse(
+			ctx, "application/octet-stream", bytes.NewReader([]byte{0xde, 0xad}),
+		)
+		s.NoError(err)
+		result <- resp
+	}()
+
+	input := s.waitForInspect(ctx)
+	s.Equal([]byte{0xde, 0xad}, input.Payload)
+	s.NoError(s.model.AddReport([]byte{0xbe, 0xef}))
+	next, err := sequencer.FinishAndGetNext(s.model, true)
+	s.NoError(err)
+	s.Nil(next)
+
+	resp := <-result
+	s.Equal(http.StatusOK, resp.StatusCode())
+	s.Equal(Accepted, resp.JSON200.Status)
+	s.Equal(0, resp.JSON200.ProcessedInputCount)
+	s.Len(resp.JSON200.Reports, 1)
+	s.Equal("0xbeef", resp.JSON200.Reports[0].Payload)
+	s.Nil(resp.JSON200.ExceptionPayload)
+}
+
+func (s *InspectSuite) TestInspectGetWithException() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	result := make(chan *InspectResponse)
+	go func() {
+		resp, err := s.client.InspectWithResponse(ctx, "hello")
+		s.NoError(err)
+		result <- resp
+	}()
+
+	input := s.waitForInspect(ctx)
+	s.Equal([]byte("hello"), input.Payload)
+	s.NoError(s.model.RegisterException([]byte{0x01}))
+
+	resp := <-result
+	s.Equal(http.StatusOK, resp.StatusCode())
+	s.Equal(Exception, resp.JSON200.Status)
+	s.Require().NotNil(resp.JSON200.ExceptionPayload)
+	s.Equal("0x01", *resp.JSON200.ExceptionPayload)
+	s.Len(resp.JSON200.Reports, 0)
+}
+
+func (s *InspectSuite) TestInspectTimeout() {
+	e := echo.New()
+	Register(e, s.model, 10*time.Millisecond)
+	server := httptest.NewServer(e)
+	defer server.Close()
+	client, err := NewClientWithResponses(server.URL)
+	s.Require().NoError(err)
+
+	// the DApp never finishes the inspect
+	resp, err := client.InspectWithResponse(context.Background(), "hello")
+	s.Require().NoError(err)
+	s.Equal(http.StatusServiceUnavailable, resp.StatusCode())
+}
+
+func (s *InspectSuite) TestInvalidStatus() {
+	_, err := convertInput(mdl.InspectInput{Status: mdl.CompletionStatus(42)})
+	s.ErrorContains(err, "invalid completion status")
+}
+
+// Call finish until the inspect sent by the test reaches the DApp side.
+func (s *InspectSuite) waitForInspect(ctx context.Context) mdl.InspectInput {
+	for {
+		newInputs := s.model.NewInputs()
+		input, err := sequencer.FinishAndGetNext(s.model, true)
+		s.Require().NoError(err)
+		if inspect, ok := input.(mdl.InspectInput); ok {
+			return inspect
+		}
+		select {
+		case <-ctx.Done():
+			s.FailNow("timed out waiting for inspect")
+		case <-newInputs:
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// This package computes the Merkle trees of the Cartesi machine memory ranges
+// that hold the outputs, which the rollups contracts use to validate the outputs.
+package merkle
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Height of the tree of the output hashes of an input, which holds up to 2^16 outputs.
+const OutputsHeight = 16
+
+// Height of the tree of the output hashes roots of an epoch, which holds up to 2^32 inputs.
+const InputsHeight = 32
+
+// Merkle tree of hashes with a fixed height.
+// The leaves beyond the given ones are the hash of a pristine (zero) memory word.
+type Tree struct {
+	// levels[0] has the leaves and levels[height] has the root.
+	// Each level only has the nodes that are not pristine.
+	levels   [][]common.Hash
+	pristine []common.Hash
+}
+
+// Compute the tree of the given leaves.
+func NewTree(leaves []common.Hash, height int) (*Tree, error) {
+	if height < 0 || height >= 64 || uint64(len(leaves)) > uint64(1)<<height {
+		return nil, fmt.Errorf("merkle: %d leaves do not fit in a tree with height %d", len(leaves), height)
+	}
+	pristine := PristineHashes(height)
+	levels := make([][]common.Hash, height+1)
+	levels[0] = leaves
+	for level := 1; level <= height; level++ {
+		children := levels[level-1]
+		nodes := make([]common.Hash, (len(children)+1)/2)
+		for i := range nodes {
+			left := children[2*i]
+			right := pristine[level-1]
+			if 2*i+1 < len(children) {
+				right = children[2*i+1]
+			}
+			nodes[i] = crypto.Keccak256Hash(left[:], right[:])
+		}
+		levels[level] = nodes
+	}
+	return &Tree{levels: levels, pristine: pristine}, nil
+}
+
+// Get the root hash of the tree.
+func (t *Tree) Root() common.Hash {
+	root := t.levels[len(t.levels)-1]
+	if len(root) == 0 {
+		return t.pristine[len(t.pristine)-1]
+	}
+	return root[0]
+}
+
+// Get the siblings of the leaf at the index, from the bottom to the top of the tree.
+func (t *Tree) Siblings(index uint64) []common.Hash {
+	height := len(t.levels) - 1
+	siblings := make([]common.Hash, height)
+	for level := 0; level < height; level++ {
+		sibling := index ^ 1
+		if sibling < uint64(len(t.levels[level])) {
+			siblings[level] = t.levels[level][sibling]
+		} else {
+			siblings[level] = t.pristine[level]
+		}
+		index >>= 1
+	}
+	return siblings
+}
+
+// Compute the root after replacing the leaf at the index, given its siblings.
+// This is how the contracts validate a leaf of the tree.
+func RootFromSiblings(leaf common.Hash, index uint64, siblings []common.Hash) common.Hash {
+	node := leaf
+	for _, sibling := range siblings {
+		if index&1 == 0 {
+			node = crypto.Keccak256Hash(node[:], sibling[:])
+		} else {
+			node = crypto.Keccak256Hash(sibling[:], node[:])
+		}
+		index >>= 1
+	}
+	return node
+}
+
+// Compute the hashes of the pristine subtrees from height 0 to the given height.
+func PristineHashes(height int) []common.Hash {
+	pristine := make([]common.Hash, height+1)
+	pristine[0] = crypto.Keccak256Hash(common.Hash{}.Bytes())
+	for i := 1; i <= height; i++ {
+		pristine[i] = crypto.Keccak256Hash(pristine[i-1][:], pristine[i-1][:])
+	}
+	return pristine
+}
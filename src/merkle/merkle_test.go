@@ -0,0 +1,69 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+)
+
+type MerkleSuite struct {
+	suite.Suite
+}
+
+func TestMerkleSuite(t *testing.T) {
+	suite.Run(t, new(MerkleSuite))
+}
+
+func leaves(n int) []common.Hash {
+	hashes := make([]common.Hash, n)
+	for i := range hashes {
+		hashes[i] = crypto.Keccak256Hash([]byte{byte(i)})
+	}
+	return hashes
+}
+
+func (s *MerkleSuite) TestEmptyTree() {
+	tree, err := NewTree(nil, OutputsHeight)
+	s.Require().NoError(err)
+	pristine := PristineHashes(OutputsHeight)
+	s.Equal(pristine[OutputsHeight], tree.Root())
+	s.Equal(crypto.Keccak256Hash(make([]byte, common.HashLength)), pristine[0])
+}
+
+func (s *MerkleSuite) TestSmallTree() {
+	hashes := leaves(3)
+	tree, err := NewTree(hashes, 2)
+	s.Require().NoError(err)
+	pristine := PristineHashes(2)
+	left := crypto.Keccak256Hash(hashes[0][:], hashes[1][:])
+	right := crypto.Keccak256Hash(hashes[2][:], pristine[0][:])
+	s.Equal(crypto.Keccak256Hash(left[:], right[:]), tree.Root())
+	s.Equal([]common.Hash{pristine[0], left}, tree.Siblings(2))
+}
+
+func (s *MerkleSuite) TestSiblingsProveEveryLeaf() {
+	for _, n := range []int{1, 2, 5, 8, 33} {
+		hashes := leaves(n)
+		tree, err := NewTree(hashes, OutputsHeight)
+		s.Require().NoError(err)
+		for i, hash := range hashes {
+			siblings := tree.Siblings(uint64(i))
+			s.Len(siblings, OutputsHeight)
+			s.Equal(tree.Root(), RootFromSiblings(hash, uint64(i), siblings), "leaf %d of %d", i, n)
+		}
+	}
+}
+
+func (s *MerkleSuite) TestSiblingsOfWrongLeaf() {
+	hashes := leaves(4)
+	tree, err := NewTree(hashes, InputsHeight)
+	s.Require().NoError(err)
+	s.NotEqual(tree.Root(), RootFromSiblings(hashes[1], 0, tree.Siblings(0)))
+}
+
+func (s *MerkleSuite) TestTooManyLeaves() {
+	_, err := NewTree(leaves(5), 2)
+	s.ErrorContains(err, "5 leaves do not fit in a tree with height 2")
+}
@@ -0,0 +1,163 @@
+// This package manages the versions of the database schema.
+//
+// Each Migration has a version number and the statements that move the schema
+// from the previous version to it. The versions already applied to a database
+// are recorded in the schema_version table, so only the pending migrations run
+// when the server starts.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Names of the database drivers supported by the migrations.
+const (
+	DriverSQLite   = "sqlite3"
+	DriverPostgres = "postgres"
+)
+
+// Arbitrary key of the Postgres advisory lock that serializes the migrations
+// of servers sharing the same database.
+const postgresLockKey = 0x726f6c6c757073 // "rollups"
+
+// Change in the database schema.
+type Migration struct {
+	// Version of the schema after the migration; starts at 1 and increases by 1.
+	Version int
+	// Short description of the change.
+	Name string
+	// Statements that apply the change to a SQLite database.
+	SQLite string
+	// Statements that apply the change to a Postgres database; empty when it needs no change.
+	Postgres string
+}
+
+// Get the statements of the migration for the database driver.
+func (m Migration) statements(driver string) (string, error) {
+	switch driver {
+	case DriverSQLite:
+		return m.SQLite, nil
+	case DriverPostgres:
+		return m.Postgres, nil
+	default:
+		return "", fmt.Errorf("migrations: unsupported driver %s", driver)
+	}
+}
+
+const createSchemaVersion = `CREATE TABLE IF NOT EXISTS schema_version (
+	version		integer NOT NULL PRIMARY KEY,
+	name		text,
+	applied_at	integer);`
+
+// Get the latest schema version applied to the database; 0 when there is none.
+func CurrentVersion(ctx context.Context, db *sqlx.DB) (int, error) {
+	_, err := db.ExecContext(ctx, createSchemaVersion)
+	if err != nil {
+		return 0, fmt.Errorf("migrations: create schema_version: %w", err)
+	}
+	var version int
+	err = db.GetContext(ctx, &version, `SELECT COALESCE(MAX(version), 0) FROM schema_version`)
+	if err != nil {
+		return 0, fmt.Errorf("migrations: get version: %w", err)
+	}
+	return version, nil
+}
+
+// Get the migrations that were not applied to the database yet.
+func Pending(ctx context.Context, db *sqlx.DB) ([]Migration, error) {
+	return pending(ctx, db, Migrations)
+}
+
+// Apply the pending migrations in order, each one in its own transaction.
+// When dryRun is set, only log the pending migrations without applying them.
+// Return the pending migrations.
+func Migrate(ctx context.Context, db *sqlx.DB, dryRun bool) ([]Migration, error) {
+	return migrate(ctx, db, Migrations, dryRun)
+}
+
+func pending(ctx context.Context, db *sqlx.DB, migrations []Migration) ([]Migration, error) {
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("migrations: unexpected version %d for %s", migration.Version, migration.Name)
+		}
+	}
+	version, err := CurrentVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if version > len(migrations) {
+		return nil, fmt.Errorf("migrations: database version %d is newer than %d", version, len(migrations))
+	}
+	return migrations[version:], nil
+}
+
+func migrate(ctx context.Context, db *sqlx.DB, migrations []Migration, dryRun bool) ([]Migration, error) {
+	pending, err := pending(ctx, db, migrations)
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range pending {
+		if dryRun {
+			slog.Info("migrations: pending", "version", migration.Version, "name", migration.Name)
+			continue
+		}
+		err := apply(ctx, db, migration)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("migrations: applied", "version", migration.Version, "name", migration.Name)
+	}
+	return pending, nil
+}
+
+func apply(ctx context.Context, db *sqlx.DB, migration Migration) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migrations: begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if db.DriverName() == DriverPostgres {
+		// another server may be migrating the same database
+		_, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, postgresLockKey)
+		if err != nil {
+			return fmt.Errorf("migrations: lock: %w", err)
+		}
+		var applied bool
+		err = tx.GetContext(ctx, &applied,
+			`SELECT EXISTS (SELECT 1 FROM schema_version WHERE version = $1)`, migration.Version)
+		if err != nil {
+			return fmt.Errorf("migrations: get version: %w", err)
+		}
+		if applied {
+			return nil
+		}
+	}
+	statements, err := migration.statements(db.DriverName())
+	if err != nil {
+		return err
+	}
+	if statements != "" {
+		_, err = tx.ExecContext(ctx, statements)
+	}
+	if err != nil {
+		return fmt.Errorf("migrations: apply %d (%s): %w", migration.Version, migration.Name, err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_version (version, name, applied_at) VALUES ($1, $2, $3)`,
+		migration.Version, migration.Name, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("migrations: record %d: %w", migration.Version, err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("migrations: commit %d: %w", migration.Version, err)
+	}
+	return nil
+}
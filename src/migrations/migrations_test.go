@@ -0,0 +1,175 @@
+package migrations
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+type MigrationsSuite struct {
+	suite.Suite
+	db      *sqlx.DB
+	tempDir string
+}
+
+func (s *MigrationsSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	tempDir, err := os.MkdirTemp("", "")
+	s.NoError(err)
+	s.tempDir = tempDir
+	s.db = sqlx.MustConnect("sqlite3", path.Join(tempDir, "migrations.sqlite3"))
+}
+
+func (s *MigrationsSuite) TearDownTest() {
+	s.db.Close()
+	os.RemoveAll(s.tempDir)
+}
+
+func TestMigrationsSuite(t *testing.T) {
+	suite.Run(t, new(MigrationsSuite))
+}
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create items", SQLite: `CREATE TABLE items (id integer PRIMARY KEY);`},
+	{Version: 2, Name: "add name", SQLite: `ALTER TABLE items ADD COLUMN name text;`},
+}
+
+func (s *MigrationsSuite) TestMigrate() {
+	ctx := context.Background()
+	applied, err := migrate(ctx, s.db, testMigrations, false)
+	s.NoError(err)
+	s.Len(applied, 2)
+	version, err := CurrentVersion(ctx, s.db)
+	s.NoError(err)
+	s.Equal(2, version)
+	_, err = s.db.Exec(`INSERT INTO items (id, name) VALUES (1, 'one')`)
+	s.NoError(err)
+
+	applied, err = migrate(ctx, s.db, testMigrations, false)
+	s.NoError(err)
+	s.Len(applied, 0)
+}
+
+func (s *MigrationsSuite) TestMigrateOnlyPending() {
+	ctx := context.Background()
+	_, err := migrate(ctx, s.db, testMigrations[:1], false)
+	s.NoError(err)
+	applied, err := migrate(ctx, s.db, testMigrations, false)
+	s.NoError(err)
+	s.Len(applied, 1)
+	s.Equal(2, applied[0].Version)
+}
+
+func (s *MigrationsSuite) TestDryRun() {
+	ctx := context.Background()
+	pending, err := migrate(ctx, s.db, testMigrations, true)
+	s.NoError(err)
+	s.Len(pending, 2)
+	version, err := CurrentVersion(ctx, s.db)
+	s.NoError(err)
+	s.Equal(0, version)
+	_, err = s.db.Exec(`SELECT * FROM items`)
+	s.Error(err)
+}
+
+func (s *MigrationsSuite) TestFailedMigrationIsRolledBack() {
+	ctx := context.Background()
+	broken := append(testMigrations[:1:1], Migration{
+		Version: 2,
+		Name:    "broken",
+		SQLite:  `ALTER TABLE items ADD COLUMN name text; INSERT INTO missing VALUES (1);`,
+	})
+	_, err := migrate(ctx, s.db, broken, false)
+	s.ErrorContains(err, "migrations: apply 2 (broken)")
+	version, err := CurrentVersion(ctx, s.db)
+	s.NoError(err)
+	s.Equal(1, version)
+
+	applied, err := migrate(ctx, s.db, testMigrations, false)
+	s.NoError(err)
+	s.Len(applied, 1)
+}
+
+func (s *MigrationsSuite) TestUnorderedMigrations() {
+	ctx := context.Background()
+	unordered := []Migration{testMigrations[1], testMigrations[0]}
+	_, err := migrate(ctx, s.db, unordered, false)
+	s.ErrorContains(err, "unexpected version 2")
+}
+
+func (s *MigrationsSuite) TestDatabaseNewerThanServer() {
+	ctx := context.Background()
+	_, err := migrate(ctx, s.db, testMigrations, false)
+	s.NoError(err)
+	_, err = migrate(ctx, s.db, testMigrations[:1], false)
+	s.ErrorContains(err, "database version 2 is newer than 1")
+}
+
+func (s *MigrationsSuite) TestBaselineAdoptsExistingTables() {
+	ctx := context.Background()
+	_, err := s.db.Exec(`CREATE TABLE vouchers (
+		destination text,
+		payload 	text,
+		executed	BOOLEAN,
+		input_index  integer,
+		output_index integer,
+		PRIMARY KEY (input_index, output_index));
+	INSERT INTO vouchers VALUES ('0x01', '0x', false, 0, 0);`)
+	s.NoError(err)
+	applied, err := Migrate(ctx, s.db, false)
+	s.NoError(err)
+	s.Len(applied, len(Migrations))
+	var count int
+	s.NoError(s.db.Get(&count, `SELECT count(*) FROM vouchers`))
+	s.Equal(1, count)
+	pending, err := Pending(ctx, s.db)
+	s.NoError(err)
+	s.Len(pending, 0)
+}
+
+func (s *MigrationsSuite) TestInputStatusIsInteger() {
+	ctx := context.Background()
+	_, err := migrate(ctx, s.db, Migrations[:13], false)
+	s.Require().NoError(err)
+	_, err = s.db.Exec(`INSERT INTO inputs (input_index, status) VALUES (0, '1')`)
+	s.Require().NoError(err)
+	_, err = Migrate(ctx, s.db, false)
+	s.Require().NoError(err)
+	var status struct {
+		Type  string `db:"type"`
+		Value int    `db:"value"`
+	}
+	err = s.db.Get(&status, `SELECT typeof(status) AS type, status AS value FROM inputs`)
+	s.Require().NoError(err)
+	s.Equal("integer", status.Type)
+	s.Equal(1, status.Value)
+}
+
+func (s *MigrationsSuite) TestInspectIdsAreKept() {
+	ctx := context.Background()
+	_, err := migrate(ctx, s.db, Migrations[:14], false)
+	s.Require().NoError(err)
+	_, err = s.db.Exec(`INSERT INTO inspects (id, status, payload) VALUES (5, 1, '1122')`)
+	s.Require().NoError(err)
+	_, err = Migrate(ctx, s.db, false)
+	s.Require().NoError(err)
+	var payload string
+	s.Require().NoError(s.db.Get(&payload, `SELECT payload FROM inspects WHERE id = 5`))
+	s.Equal("1122", payload)
+
+	// the deleted ids are not reused
+	_, err = s.db.Exec(`DELETE FROM inspects`)
+	s.Require().NoError(err)
+	res, err := s.db.Exec(`INSERT INTO inspects (status) VALUES (0)`)
+	s.Require().NoError(err)
+	id, err := res.LastInsertId()
+	s.Require().NoError(err)
+	s.Equal(int64(6), id)
+}
@@ -0,0 +1,330 @@
+package migrations
+
+// Migrations of the database schema in version order.
+// Never change a migration that was released; append a new one instead.
+var Migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "baseline",
+		// The tables use IF NOT EXISTS to adopt the databases created before the migrations.
+		SQLite: `CREATE TABLE IF NOT EXISTS inputs (
+			id 				INTEGER NOT NULL PRIMARY KEY,
+			input_index		integer,
+			status	 		text,
+			msg_sender	 	text,
+			payload			text,
+			block_number	integer,
+			block_timestamp	integer,
+			prev_randao		integer,
+			exception		text);
+		CREATE TABLE IF NOT EXISTS reports (
+			output_index	integer,
+			payload 		text,
+			input_index 	integer);
+		CREATE TABLE IF NOT EXISTS inspects (
+			id 						INTEGER NOT NULL PRIMARY KEY,
+			status					integer,
+			payload					text,
+			processed_input_count	integer,
+			exception				text,
+			created_at				integer,
+			finished_at				integer);
+		CREATE INDEX IF NOT EXISTS inspects_status ON inspects (status);
+		CREATE TABLE IF NOT EXISTS inspect_reports (
+			inspect_index	integer,
+			output_index	integer,
+			payload			text,
+			PRIMARY KEY (inspect_index, output_index));
+		CREATE TABLE IF NOT EXISTS vouchers (
+			destination text,
+			payload 	text,
+			executed	BOOLEAN,
+			input_index  integer,
+			output_index integer,
+			PRIMARY KEY (input_index, output_index));
+		CREATE TABLE IF NOT EXISTS notices (
+			payload 		text,
+			input_index		integer,
+			output_index	integer,
+			PRIMARY KEY (input_index, output_index));`,
+		Postgres: `CREATE TABLE IF NOT EXISTS inputs (
+			id 				SERIAL PRIMARY KEY,
+			input_index		integer,
+			status	 		integer,
+			msg_sender	 	text,
+			payload			text,
+			block_number	bigint,
+			block_timestamp	bigint,
+			prev_randao		bigint,
+			exception		text);
+		CREATE TABLE IF NOT EXISTS reports (
+			output_index	integer,
+			payload 		text,
+			input_index 	integer);
+		CREATE TABLE IF NOT EXISTS inspects (
+			id 						SERIAL PRIMARY KEY,
+			status					integer,
+			payload					text,
+			processed_input_count	integer,
+			exception				text,
+			created_at				bigint,
+			finished_at				bigint);
+		CREATE INDEX IF NOT EXISTS inspects_status ON inspects (status);
+		CREATE TABLE IF NOT EXISTS inspect_reports (
+			inspect_index	integer,
+			output_index	integer,
+			payload			text,
+			PRIMARY KEY (inspect_index, output_index));
+		CREATE TABLE IF NOT EXISTS vouchers (
+			destination text,
+			payload 	text,
+			executed	boolean,
+			input_index  integer,
+			output_index integer,
+			PRIMARY KEY (input_index, output_index));
+		CREATE TABLE IF NOT EXISTS notices (
+			payload 		text,
+			input_index		integer,
+			output_index	integer,
+			PRIMARY KEY (input_index, output_index));`,
+	},
+	{
+		Version: 2,
+		Name:    "output proofs",
+		SQLite: `ALTER TABLE inputs ADD COLUMN output_hashes_root_hash text;
+		CREATE TABLE proofs (
+			input_index								integer,
+			output_index							integer,
+			output									text,
+			output_hash								text,
+			output_hashes_root_hash					text,
+			output_hash_in_output_hashes_siblings	text,
+			first_input_index						integer,
+			last_input_index						integer,
+			input_index_within_epoch				integer,
+			outputs_epoch_root_hash					text,
+			machine_state_hash						text,
+			output_hashes_in_epoch_siblings			text,
+			PRIMARY KEY (input_index, output_index));`,
+		Postgres: `ALTER TABLE inputs ADD COLUMN output_hashes_root_hash text;
+		CREATE TABLE proofs (
+			input_index								integer,
+			output_index							integer,
+			output									text,
+			output_hash								text,
+			output_hashes_root_hash					text,
+			output_hash_in_output_hashes_siblings	text,
+			first_input_index						integer,
+			last_input_index						integer,
+			input_index_within_epoch				integer,
+			outputs_epoch_root_hash					text,
+			machine_state_hash						text,
+			output_hashes_in_epoch_siblings			text,
+			PRIMARY KEY (input_index, output_index));`,
+	},
+	{
+		Version: 3,
+		Name:    "epochs",
+		SQLite: `ALTER TABLE inputs ADD COLUMN epoch_index integer;
+		CREATE TABLE epochs (
+			epoch_index				integer PRIMARY KEY,
+			status					integer,
+			first_block				integer,
+			opened_at				integer,
+			first_input_index		integer,
+			last_input_index		integer,
+			closed_at				integer,
+			outputs_epoch_root_hash	text,
+			machine_state_hash		text);
+		CREATE INDEX epochs_status ON epochs (status);
+		CREATE INDEX inputs_epoch_index ON inputs (epoch_index);`,
+		Postgres: `ALTER TABLE inputs ADD COLUMN epoch_index integer;
+		CREATE TABLE epochs (
+			epoch_index				integer PRIMARY KEY,
+			status					integer,
+			first_block				bigint,
+			opened_at				bigint,
+			first_input_index		integer,
+			last_input_index		integer,
+			closed_at				bigint,
+			outputs_epoch_root_hash	text,
+			machine_state_hash		text);
+		CREATE INDEX epochs_status ON epochs (status);
+		CREATE INDEX inputs_epoch_index ON inputs (epoch_index);`,
+	},
+	{
+		Version:  4,
+		Name:     "epoch claims",
+		SQLite:   `ALTER TABLE epochs ADD COLUMN claim_transaction_hash text;`,
+		Postgres: `ALTER TABLE epochs ADD COLUMN claim_transaction_hash text;`,
+	},
+	{
+		Version: 5,
+		Name:    "voucher executions",
+		SQLite: `ALTER TABLE vouchers ADD COLUMN executed_tx_hash text;
+		ALTER TABLE vouchers ADD COLUMN executed_block integer;`,
+		Postgres: `ALTER TABLE vouchers ADD COLUMN executed_tx_hash text;
+		ALTER TABLE vouchers ADD COLUMN executed_block bigint;`,
+	},
+	{
+		Version: 6,
+		Name:    "voucher simulations",
+		SQLite: `ALTER TABLE vouchers ADD COLUMN value text;
+		ALTER TABLE vouchers ADD COLUMN simulation_success boolean;
+		ALTER TABLE vouchers ADD COLUMN simulation_gas_estimate integer;
+		ALTER TABLE vouchers ADD COLUMN simulation_revert_data text;
+		ALTER TABLE vouchers ADD COLUMN simulation_error text;
+		CREATE INDEX vouchers_simulation_success ON vouchers (simulation_success);`,
+		Postgres: `ALTER TABLE vouchers ADD COLUMN value text;
+		ALTER TABLE vouchers ADD COLUMN simulation_success boolean;
+		ALTER TABLE vouchers ADD COLUMN simulation_gas_estimate bigint;
+		ALTER TABLE vouchers ADD COLUMN simulation_revert_data text;
+		ALTER TABLE vouchers ADD COLUMN simulation_error text;
+		CREATE INDEX vouchers_simulation_success ON vouchers (simulation_success);`,
+	},
+	{
+		Version: 7,
+		Name:    "advance metadata",
+		// The prev_randao column used to be an integer that was never filled, but the value
+		// is a uint256; so, the column is replaced by a text one.
+		SQLite: `ALTER TABLE inputs DROP COLUMN prev_randao;
+		ALTER TABLE inputs ADD COLUMN prev_randao text;
+		ALTER TABLE inputs ADD COLUMN chain_id integer;
+		ALTER TABLE inputs ADD COLUMN app_contract text;
+		ALTER TABLE inputs ADD COLUMN transaction_hash text;
+		ALTER TABLE inputs ADD COLUMN log_index integer;`,
+		Postgres: `ALTER TABLE inputs DROP COLUMN prev_randao;
+		ALTER TABLE inputs ADD COLUMN prev_randao text;
+		ALTER TABLE inputs ADD COLUMN chain_id bigint;
+		ALTER TABLE inputs ADD COLUMN app_contract text;
+		ALTER TABLE inputs ADD COLUMN transaction_hash text;
+		ALTER TABLE inputs ADD COLUMN log_index bigint;`,
+	},
+	{
+		Version: 8,
+		Name:    "checkpoints",
+		SQLite: `CREATE TABLE checkpoints (
+			name			text PRIMARY KEY,
+			block_number	integer NOT NULL);`,
+		Postgres: `CREATE TABLE checkpoints (
+			name			text PRIMARY KEY,
+			block_number	bigint NOT NULL);`,
+	},
+	{
+		Version: 9,
+		Name:    "block hashes",
+		SQLite: `ALTER TABLE inputs ADD COLUMN block_hash text;
+		ALTER TABLE checkpoints ADD COLUMN block_hash text;
+		CREATE INDEX inputs_block_number ON inputs (block_number);`,
+		Postgres: `ALTER TABLE inputs ADD COLUMN block_hash text;
+		ALTER TABLE checkpoints ADD COLUMN block_hash text;
+		CREATE INDEX inputs_block_number ON inputs (block_number);`,
+	},
+	{
+		Version: 10,
+		Name:    "deposits",
+		SQLite: `CREATE TABLE deposits (
+			input_index		integer PRIMARY KEY,
+			kind			text NOT NULL,
+			portal			text NOT NULL,
+			token			text NOT NULL,
+			depositor		text NOT NULL,
+			amount			text,
+			token_ids		text,
+			amounts			text,
+			base_layer_data	text,
+			exec_layer_data	text);
+		CREATE INDEX deposits_token ON deposits (token);
+		CREATE INDEX deposits_depositor ON deposits (depositor);`,
+		Postgres: `CREATE TABLE deposits (
+			input_index		bigint PRIMARY KEY,
+			kind			text NOT NULL,
+			portal			text NOT NULL,
+			token			text NOT NULL,
+			depositor		text NOT NULL,
+			amount			text,
+			token_ids		text,
+			amounts			text,
+			base_layer_data	text,
+			exec_layer_data	text);
+		CREATE INDEX deposits_token ON deposits (token);
+		CREATE INDEX deposits_depositor ON deposits (depositor);`,
+	},
+	{
+		Version: 11,
+		Name:    "voucher withdrawals",
+		SQLite: `ALTER TABLE vouchers ADD COLUMN contract text;
+		ALTER TABLE vouchers ADD COLUMN beneficiary text;
+		ALTER TABLE vouchers ADD COLUMN amount text;
+		ALTER TABLE vouchers ADD COLUMN token_id text;
+		ALTER TABLE vouchers ADD COLUMN method_signature text;
+		ALTER TABLE vouchers ADD COLUMN ercx text;
+		CREATE INDEX vouchers_beneficiary ON vouchers (beneficiary);`,
+		Postgres: `ALTER TABLE vouchers ADD COLUMN contract text;
+		ALTER TABLE vouchers ADD COLUMN beneficiary text;
+		ALTER TABLE vouchers ADD COLUMN amount text;
+		ALTER TABLE vouchers ADD COLUMN token_id text;
+		ALTER TABLE vouchers ADD COLUMN method_signature text;
+		ALTER TABLE vouchers ADD COLUMN ercx text;
+		CREATE INDEX vouchers_beneficiary ON vouchers (beneficiary);`,
+	},
+	{
+		Version: 12,
+		Name:    "decoded outputs",
+		SQLite: `ALTER TABLE vouchers ADD COLUMN decoded_function text;
+		ALTER TABLE vouchers ADD COLUMN decoded_args text;
+		ALTER TABLE notices ADD COLUMN decoded_schema text;
+		ALTER TABLE notices ADD COLUMN decoded_fields text;`,
+		Postgres: `ALTER TABLE vouchers ADD COLUMN decoded_function text;
+		ALTER TABLE vouchers ADD COLUMN decoded_args text;
+		ALTER TABLE notices ADD COLUMN decoded_schema text;
+		ALTER TABLE notices ADD COLUMN decoded_fields text;`,
+	},
+	{
+		Version: 13,
+		Name:    "voucher labels and amount keys",
+		// The amount key is the amount padded with zeros to the 78 digits of a uint256,
+		// so comparing the keys as text compares the amounts as numbers.
+		SQLite: `ALTER TABLE vouchers ADD COLUMN label text;
+		ALTER TABLE vouchers ADD COLUMN amount_key text;
+		UPDATE vouchers SET amount_key = substr('000000000000000000000000000000000000000000000000000000000000000000000000000000' || amount, -78)
+			WHERE amount IS NOT NULL;
+		CREATE INDEX vouchers_amount_key ON vouchers (amount_key);`,
+		Postgres: `ALTER TABLE vouchers ADD COLUMN label text;
+		ALTER TABLE vouchers ADD COLUMN amount_key text;
+		UPDATE vouchers SET amount_key = lpad(amount, 78, '0') WHERE amount IS NOT NULL;
+		CREATE INDEX vouchers_amount_key ON vouchers (amount_key);`,
+	},
+	{
+		Version: 14,
+		Name:    "integer input status",
+		// The baseline created the status as text in SQLite and as integer in Postgres.
+		SQLite: `ALTER TABLE inputs ADD COLUMN status_integer integer;
+		UPDATE inputs SET status_integer = CAST(status AS integer);
+		ALTER TABLE inputs DROP COLUMN status;
+		ALTER TABLE inputs RENAME COLUMN status_integer TO status;`,
+		Postgres: `ALTER TABLE inputs ALTER COLUMN status TYPE integer;`,
+	},
+	{
+		Version: 15,
+		Name:    "inspect ids are not reused",
+		// Without AUTOINCREMENT SQLite reuses the ids of the newest inspects after they are
+		// pruned, so a client polling an old id would get another inspect.
+		// Postgres already takes the ids from a sequence.
+		SQLite: `CREATE TABLE inspects_autoincrement (
+			id 						INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+			status					integer,
+			payload					text,
+			processed_input_count	integer,
+			exception				text,
+			created_at				integer,
+			finished_at				integer);
+		INSERT INTO inspects_autoincrement
+			(id, status, payload, processed_input_count, exception, created_at, finished_at)
+			SELECT id, status, payload, processed_input_count, exception, created_at, finished_at
+			FROM inspects;
+		DROP TABLE inspects;
+		ALTER TABLE inspects_autoincrement RENAME TO inspects;
+		CREATE INDEX inspects_status ON inspects (status);`,
+	},
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/calindra/rollups-server/src/migrations"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jmoiron/sqlx"
+)
+
+// Repository of the last block fully processed by each worker that reads the chain.
+type CheckpointRepository struct {
+	Db *sqlx.DB
+}
+
+type checkpointRow struct {
+	BlockNumber uint64  `db:"block_number"`
+	BlockHash   *string `db:"block_hash"`
+}
+
+// Create the tables by applying the pending schema migrations.
+func (r *CheckpointRepository) CreateTables() error {
+	_, err := migrations.Migrate(context.Background(), r.Db, false)
+	return err
+}
+
+// Find the block of the checkpoint; return nil if the checkpoint was never saved.
+// The hash of the block is zero when it is unknown.
+func (r *CheckpointRepository) Find(ctx context.Context, name string) (*Block, error) {
+	var row checkpointRow
+	query := `SELECT block_number, block_hash FROM checkpoints WHERE name = $1`
+	err := sqlx.GetContext(ctx, executor(ctx, r.Db), &row, query, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find checkpoint: %w", err)
+	}
+	block := Block{Number: row.BlockNumber}
+	if row.BlockHash != nil {
+		block.Hash = common.HexToHash(*row.BlockHash)
+	}
+	return &block, nil
+}
+
+// Save the block of the checkpoint, replacing the previous one.
+func (r *CheckpointRepository) Save(ctx context.Context, name string, block Block) error {
+	query := `INSERT INTO checkpoints (name, block_number, block_hash) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET
+		block_number = excluded.block_number, block_hash = excluded.block_hash`
+	_, err := executor(ctx, r.Db).ExecContext(ctx, query, name, block.Number, block.Hash.Hex())
+	if err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Move the checkpoints after the block back to it.
+// The hash of the block is unknown, so it is cleared.
+func (r *CheckpointRepository) Rewind(ctx context.Context, blockNumber uint64) error {
+	query := `UPDATE checkpoints SET block_number = $1, block_hash = NULL WHERE block_number > $1`
+	_, err := executor(ctx, r.Db).ExecContext(ctx, query, blockNumber)
+	if err != nil {
+		return fmt.Errorf("rewind checkpoints: %w", err)
+	}
+	return nil
+}
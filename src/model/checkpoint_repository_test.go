@@ -0,0 +1,70 @@
+package model
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/suite"
+)
+
+type CheckpointRepositorySuite struct {
+	suite.Suite
+	repository *CheckpointRepository
+	driver     string
+	database   *testDatabase
+}
+
+func (s *CheckpointRepositorySuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	var err error
+	s.database, err = openTestDatabase(s.driver)
+	s.Require().NoError(err)
+	s.repository = &CheckpointRepository{Db: s.database.Db}
+	s.Require().NoError(s.repository.CreateTables())
+}
+
+func (s *CheckpointRepositorySuite) TearDownTest() {
+	s.database.Close()
+}
+
+func TestCheckpointRepositorySuite(t *testing.T) {
+	runWithDrivers(t, func(driver string) *CheckpointRepositorySuite {
+		return &CheckpointRepositorySuite{driver: driver}
+	})
+}
+
+func (s *CheckpointRepositorySuite) TestSaveAndFind() {
+	ctx := context.Background()
+	block, err := s.repository.Find(ctx, "inputter")
+	s.NoError(err)
+	s.Nil(block)
+
+	hash := common.HexToHash("0xabcd")
+	s.NoError(s.repository.Save(ctx, "inputter", Block{Number: 10}))
+	s.NoError(s.repository.Save(ctx, "other", Block{Number: 3}))
+	s.NoError(s.repository.Save(ctx, "inputter", Block{Number: 20, Hash: hash}))
+
+	block, err = s.repository.Find(ctx, "inputter")
+	s.NoError(err)
+	s.Equal(&Block{Number: 20, Hash: hash}, block)
+	block, err = s.repository.Find(ctx, "other")
+	s.NoError(err)
+	s.Equal(&Block{Number: 3}, block)
+}
+
+func (s *CheckpointRepositorySuite) TestRewind() {
+	ctx := context.Background()
+	s.NoError(s.repository.Save(ctx, "inputter", Block{Number: 20, Hash: common.HexToHash("0xabcd")}))
+	s.NoError(s.repository.Save(ctx, "other", Block{Number: 3, Hash: common.HexToHash("0x1234")}))
+	s.NoError(s.repository.Rewind(ctx, 10))
+
+	block, err := s.repository.Find(ctx, "inputter")
+	s.NoError(err)
+	s.Equal(&Block{Number: 10}, block)
+	block, err = s.repository.Find(ctx, "other")
+	s.NoError(err)
+	s.Equal(&Block{Number: 3, Hash: common.HexToHash("0x1234")}, block)
+}
@@ -0,0 +1,219 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"math/big"
+	"strings"
+
+	"github.com/calindra/rollups-server/src/migrations"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/jmoiron/sqlx"
+)
+
+type DepositRepository struct {
+	Db sqlx.DB
+}
+
+type depositRow struct {
+	InputIndex    uint64  `db:"input_index"`
+	Kind          string  `db:"kind"`
+	Portal        string  `db:"portal"`
+	Token         string  `db:"token"`
+	Depositor     string  `db:"depositor"`
+	Amount        *string `db:"amount"`
+	TokenIds      *string `db:"token_ids"`
+	Amounts       *string `db:"amounts"`
+	BaseLayerData *string `db:"base_layer_data"`
+	ExecLayerData *string `db:"exec_layer_data"`
+}
+
+// Create the tables by applying the pending schema migrations.
+func (c *DepositRepository) CreateTables() error {
+	_, err := migrations.Migrate(context.Background(), &c.Db, false)
+	return err
+}
+
+func (c *DepositRepository) Create(
+	ctx context.Context, deposit *ConvenienceDeposit,
+) (*ConvenienceDeposit, error) {
+	insertSql := `INSERT INTO deposits (
+		input_index,
+		kind,
+		portal,
+		token,
+		depositor,
+		amount,
+		token_ids,
+		amounts,
+		base_layer_data,
+		exec_layer_data) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := executor(ctx, &c.Db).ExecContext(
+		ctx,
+		insertSql,
+		deposit.InputIndex,
+		string(deposit.Kind),
+		deposit.Portal.Hex(),
+		deposit.Token.Hex(),
+		deposit.Depositor.Hex(),
+		encodeValue(deposit.Amount),
+		encodeValues(deposit.TokenIds),
+		encodeValues(deposit.Amounts),
+		hexutil.Encode(deposit.BaseLayerData),
+		hexutil.Encode(deposit.ExecLayerData),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return deposit, nil
+}
+
+// Find the deposit of the input; return nil if the input is not a deposit.
+func (c *DepositRepository) FindByInputIndex(
+	ctx context.Context, inputIndex uint64,
+) (*ConvenienceDeposit, error) {
+	query := `SELECT * FROM deposits WHERE input_index = $1 LIMIT 1`
+	var row depositRow
+	err := sqlx.GetContext(ctx, executor(ctx, &c.Db), &row, query, inputIndex)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	deposit := convertToConvenienceDeposit(row)
+	return &deposit, nil
+}
+
+// Delete the deposits of the inputs from the given index on.
+func (c *DepositRepository) DeleteFrom(ctx context.Context, inputIndex uint64) error {
+	_, err := executor(ctx, &c.Db).ExecContext(ctx,
+		`DELETE FROM deposits WHERE input_index >= $1`, inputIndex)
+	return err
+}
+
+func (c *DepositRepository) Count(
+	ctx context.Context,
+	filter []*ConvenienceFilter,
+) (uint64, error) {
+	query := `SELECT count(*) FROM deposits `
+	where, args, _, err := compileFilter(filter, depositColumns)
+	if err != nil {
+		return 0, err
+	}
+	query += where
+	slog.Debug("Query", "query", query, "args", args)
+	var count uint64
+	err = sqlx.GetContext(ctx, executor(ctx, &c.Db), &count, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (c *DepositRepository) FindAllDeposits(
+	ctx context.Context,
+	first *int,
+	last *int,
+	after *string,
+	before *string,
+	filter []*ConvenienceFilter,
+	options ...util.PageOption,
+) (*util.PageResult[ConvenienceDeposit], error) {
+	page, err := util.ComputePage(first, last, after, before, options...)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT * FROM deposits `
+	where, args, argsCount, err := compileFilter(filter, depositColumns)
+	if err != nil {
+		return nil, err
+	}
+	pageQuery, args := compilePage(page, where, args, argsCount, "input_index")
+	query += pageQuery
+
+	slog.Debug("Query", "query", query, "args", args)
+	var rows []depositRow
+	err = sqlx.SelectContext(ctx, executor(ctx, &c.Db), &rows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	deposits := make([]ConvenienceDeposit, len(rows))
+	for i, row := range rows {
+		deposits[i] = convertToConvenienceDeposit(row)
+	}
+	result := util.NewPageResult(page, deposits, depositCursor)
+	err = result.SetTotal(page, func() (uint64, error) {
+		return c.Count(ctx, filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func depositCursor(deposit ConvenienceDeposit) util.Cursor {
+	return util.Cursor{InputIndex: deposit.InputIndex}
+}
+
+// Encode the values as a comma separated list of decimals; nil is stored as null.
+func encodeValues(values []*big.Int) *string {
+	if values == nil {
+		return nil
+	}
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		encoded[i] = value.String()
+	}
+	joined := strings.Join(encoded, ",")
+	return &joined
+}
+
+func decodeValues(encoded *string) []*big.Int {
+	if encoded == nil {
+		return nil
+	}
+	if *encoded == "" {
+		return []*big.Int{}
+	}
+	parts := strings.Split(*encoded, ",")
+	values := make([]*big.Int, len(parts))
+	for i, part := range parts {
+		values[i], _ = new(big.Int).SetString(part, 10)
+	}
+	return values
+}
+
+func convertToConvenienceDeposit(row depositRow) ConvenienceDeposit {
+	deposit := ConvenienceDeposit{
+		InputIndex: row.InputIndex,
+		Kind:       DepositKind(row.Kind),
+		Portal:     common.HexToAddress(row.Portal),
+		Token:      common.HexToAddress(row.Token),
+		Depositor:  common.HexToAddress(row.Depositor),
+		TokenIds:   decodeValues(row.TokenIds),
+		Amounts:    decodeValues(row.Amounts),
+	}
+	if row.Amount != nil {
+		deposit.Amount, _ = new(big.Int).SetString(*row.Amount, 10)
+	}
+	if row.BaseLayerData != nil {
+		deposit.BaseLayerData = common.FromHex(*row.BaseLayerData)
+	}
+	if row.ExecLayerData != nil {
+		deposit.ExecLayerData = common.FromHex(*row.ExecLayerData)
+	}
+	return deposit
+}
+
+// Filterable columns of the deposits table.
+var depositColumns = filterColumns{
+	INPUT_INDEX: integerColumn("input_index"),
+	KIND:        textColumn("kind"),
+	TOKEN:       addressColumn("token"),
+	DEPOSITOR:   addressColumn("depositor"),
+}
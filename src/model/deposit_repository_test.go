@@ -0,0 +1,138 @@
+package model
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"testing"
+
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	testToken     = common.HexToAddress("0xc6e7DF5E7b4f2A278906862b61205850344D4e7d")
+	testDepositor = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+)
+
+type DepositRepositorySuite struct {
+	suite.Suite
+	repository *DepositRepository
+	driver     string
+	database   *testDatabase
+}
+
+func (s *DepositRepositorySuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	var err error
+	s.database, err = openTestDatabase(s.driver)
+	s.Require().NoError(err)
+	s.repository = &DepositRepository{
+		Db: *s.database.Db,
+	}
+	err = s.repository.CreateTables()
+	s.NoError(err)
+}
+
+func (s *DepositRepositorySuite) TearDownTest() {
+	s.database.Close()
+}
+
+func TestDepositRepositorySuite(t *testing.T) {
+	runWithDrivers(t, func(driver string) *DepositRepositorySuite {
+		return &DepositRepositorySuite{driver: driver}
+	})
+}
+
+// Create an Ether deposit, an ERC-20 deposit and an ERC-1155 batch deposit.
+func (s *DepositRepositorySuite) createDeposits() {
+	ctx := context.Background()
+	deposits := []ConvenienceDeposit{{
+		InputIndex:    0,
+		Kind:          DepositKindEther,
+		Depositor:     testDepositor,
+		Amount:        big.NewInt(100),
+		ExecLayerData: []byte{0xe0},
+	}, {
+		InputIndex: 2,
+		Kind:       DepositKindERC20,
+		Token:      testToken,
+		Depositor:  testDepositor,
+		Amount:     big.NewInt(7),
+	}, {
+		InputIndex:    3,
+		Kind:          DepositKindERC1155Batch,
+		Token:         testToken,
+		Depositor:     common.HexToAddress("0x01"),
+		TokenIds:      []*big.Int{big.NewInt(1), big.NewInt(2)},
+		Amounts:       []*big.Int{big.NewInt(10), big.NewInt(20)},
+		BaseLayerData: []byte{0xba},
+	}}
+	for _, deposit := range deposits {
+		_, err := s.repository.Create(ctx, &deposit)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *DepositRepositorySuite) TestCreateAndFind() {
+	ctx := context.Background()
+	s.createDeposits()
+	deposit, err := s.repository.FindByInputIndex(ctx, 3)
+	s.NoError(err)
+	s.Require().NotNil(deposit)
+	s.Equal(DepositKindERC1155Batch, deposit.Kind)
+	s.Equal(testToken, deposit.Token)
+	s.Nil(deposit.Amount)
+	s.Equal([]*big.Int{big.NewInt(1), big.NewInt(2)}, deposit.TokenIds)
+	s.Equal([]*big.Int{big.NewInt(10), big.NewInt(20)}, deposit.Amounts)
+	s.Equal([]byte{0xba}, deposit.BaseLayerData)
+
+	deposit, err = s.repository.FindByInputIndex(ctx, 0)
+	s.NoError(err)
+	s.Require().NotNil(deposit)
+	s.Equal(big.NewInt(100), deposit.Amount)
+	s.Nil(deposit.TokenIds)
+	s.Equal([]byte{0xe0}, deposit.ExecLayerData)
+
+	deposit, err = s.repository.FindByInputIndex(ctx, 1)
+	s.NoError(err)
+	s.Nil(deposit)
+}
+
+func (s *DepositRepositorySuite) TestFilterDeposits() {
+	ctx := context.Background()
+	s.createDeposits()
+	token := TOKEN
+	depositor := DEPOSITOR
+	value := testToken.Hex()
+	result, err := s.repository.FindAllDeposits(ctx, nil, nil, nil, nil,
+		[]*ConvenienceFilter{{Field: &token, Eq: &value}})
+	s.Require().NoError(err)
+	s.Equal(2, len(result.Rows))
+	s.Equal(uint64(2), result.Rows[0].InputIndex)
+	s.Equal(uint64(3), result.Rows[1].InputIndex)
+
+	value = testDepositor.Hex()
+	count, err := s.repository.Count(ctx, []*ConvenienceFilter{{Field: &depositor, Eq: &value}})
+	s.NoError(err)
+	s.Equal(2, int(count))
+
+	kind := KIND
+	value = string(DepositKindEther)
+	result, err = s.repository.FindAllDeposits(ctx, nil, nil, nil, nil,
+		[]*ConvenienceFilter{{Field: &kind, Eq: &value}})
+	s.Require().NoError(err)
+	s.Equal(1, len(result.Rows))
+	s.Equal(uint64(0), result.Rows[0].InputIndex)
+}
+
+func (s *DepositRepositorySuite) TestDeleteFrom() {
+	ctx := context.Background()
+	s.createDeposits()
+	s.NoError(s.repository.DeleteFrom(ctx, 2))
+	count, err := s.repository.Count(ctx, nil)
+	s.NoError(err)
+	s.Equal(1, int(count))
+}
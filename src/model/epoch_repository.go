@@ -0,0 +1,257 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/calindra/rollups-server/src/migrations"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jmoiron/sqlx"
+)
+
+type EpochRepository struct {
+	Db *sqlx.DB
+}
+
+type epochRow struct {
+	Index                uint64      `db:"epoch_index"`
+	Status               EpochStatus `db:"status"`
+	FirstBlock           uint64      `db:"first_block"`
+	OpenedAt             int64       `db:"opened_at"`
+	FirstInputIndex      *uint64     `db:"first_input_index"`
+	LastInputIndex       *uint64     `db:"last_input_index"`
+	ClosedAt             *int64      `db:"closed_at"`
+	OutputsEpochRootHash *string     `db:"outputs_epoch_root_hash"`
+	MachineStateHash     *string     `db:"machine_state_hash"`
+	ClaimTransactionHash *string     `db:"claim_transaction_hash"`
+}
+
+// Create the tables by applying the pending schema migrations.
+func (r *EpochRepository) CreateTables() error {
+	_, err := migrations.Migrate(context.Background(), r.Db, false)
+	return err
+}
+
+func (r *EpochRepository) Create(ctx context.Context, epoch Epoch) (*Epoch, error) {
+	row := convertToEpochRow(epoch)
+	insertSql := `INSERT INTO epochs (
+		epoch_index,
+		status,
+		first_block,
+		opened_at,
+		first_input_index,
+		last_input_index,
+		closed_at,
+		outputs_epoch_root_hash,
+		machine_state_hash,
+		claim_transaction_hash) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err := executor(ctx, r.Db).ExecContext(
+		ctx,
+		insertSql,
+		row.Index,
+		row.Status,
+		row.FirstBlock,
+		row.OpenedAt,
+		row.FirstInputIndex,
+		row.LastInputIndex,
+		row.ClosedAt,
+		row.OutputsEpochRootHash,
+		row.MachineStateHash,
+		row.ClaimTransactionHash,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create epoch: %w", err)
+	}
+	return &epoch, nil
+}
+
+func (r *EpochRepository) Update(ctx context.Context, epoch Epoch) (*Epoch, error) {
+	row := convertToEpochRow(epoch)
+	updateSql := `UPDATE epochs SET
+		status = $1,
+		first_input_index = $2,
+		last_input_index = $3,
+		closed_at = $4,
+		outputs_epoch_root_hash = $5,
+		machine_state_hash = $6,
+		claim_transaction_hash = $7
+		WHERE epoch_index = $8`
+	_, err := executor(ctx, r.Db).ExecContext(
+		ctx,
+		updateSql,
+		row.Status,
+		row.FirstInputIndex,
+		row.LastInputIndex,
+		row.ClosedAt,
+		row.OutputsEpochRootHash,
+		row.MachineStateHash,
+		row.ClaimTransactionHash,
+		row.Index,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update epoch: %w", err)
+	}
+	return &epoch, nil
+}
+
+// Delete the epochs from the given index on.
+func (r *EpochRepository) DeleteFrom(ctx context.Context, index uint64) error {
+	_, err := executor(ctx, r.Db).ExecContext(ctx, `DELETE FROM epochs WHERE epoch_index >= $1`, index)
+	if err != nil {
+		return fmt.Errorf("delete epochs: %w", err)
+	}
+	return nil
+}
+
+// Get the index of the next epoch, which is zero when there are no epochs.
+func (r *EpochRepository) NextIndex(ctx context.Context) (uint64, error) {
+	var next uint64
+	query := `SELECT COALESCE(MAX(epoch_index) + 1, 0) FROM epochs`
+	err := sqlx.GetContext(ctx, executor(ctx, r.Db), &next, query)
+	if err != nil {
+		return 0, fmt.Errorf("next epoch index: %w", err)
+	}
+	return next, nil
+}
+
+// Find the epoch by its index; return nil if it does not exist.
+func (r *EpochRepository) FindByIndex(ctx context.Context, index uint64) (*Epoch, error) {
+	return r.findOne(ctx, `SELECT * FROM epochs WHERE epoch_index = $1`, index)
+}
+
+// Find the open epoch; return nil if all epochs are closed.
+func (r *EpochRepository) FindOpen(ctx context.Context) (*Epoch, error) {
+	return r.findOne(ctx, `SELECT * FROM epochs WHERE status = $1`, EpochStatusOpen)
+}
+
+// Find the epochs with the status in index order.
+func (r *EpochRepository) FindByStatus(ctx context.Context, status EpochStatus) ([]Epoch, error) {
+	return r.findMany(ctx, `SELECT * FROM epochs WHERE status = $1 ORDER BY epoch_index ASC`, status)
+}
+
+// Find all the epochs in index order.
+func (r *EpochRepository) FindAll(ctx context.Context) ([]Epoch, error) {
+	return r.findMany(ctx, `SELECT * FROM epochs ORDER BY epoch_index ASC`)
+}
+
+func (r *EpochRepository) findOne(ctx context.Context, query string, args ...interface{}) (*Epoch, error) {
+	var row epochRow
+	err := sqlx.GetContext(ctx, executor(ctx, r.Db), &row, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find epoch: %w", err)
+	}
+	epoch := convertToEpoch(row)
+	return &epoch, nil
+}
+
+func (r *EpochRepository) findMany(ctx context.Context, query string, args ...interface{}) ([]Epoch, error) {
+	var rows []epochRow
+	err := sqlx.SelectContext(ctx, executor(ctx, r.Db), &rows, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("find epochs: %w", err)
+	}
+	epochs := make([]Epoch, len(rows))
+	for i, row := range rows {
+		epochs[i] = convertToEpoch(row)
+	}
+	return epochs, nil
+}
+
+// The columns that are not set in the epoch status are stored as null.
+func convertToEpochRow(epoch Epoch) epochRow {
+	row := epochRow{
+		Index:      epoch.Index,
+		Status:     epoch.Status,
+		FirstBlock: epoch.FirstBlock,
+		OpenedAt:   epoch.OpenedAt.UnixMilli(),
+	}
+	if epoch.Status >= EpochStatusClosed {
+		closedAt := epoch.ClosedAt.UnixMilli()
+		row.FirstInputIndex = &epoch.FirstInputIndex
+		row.LastInputIndex = &epoch.LastInputIndex
+		row.ClosedAt = &closedAt
+	}
+	if epoch.Status >= EpochStatusProved {
+		outputsEpochRootHash := epoch.OutputsEpochRootHash.Hex()
+		machineStateHash := epoch.MachineStateHash.Hex()
+		row.OutputsEpochRootHash = &outputsEpochRootHash
+		row.MachineStateHash = &machineStateHash
+	}
+	if epoch.Status >= EpochStatusClaimed {
+		claimTransactionHash := epoch.ClaimTransactionHash.Hex()
+		row.ClaimTransactionHash = &claimTransactionHash
+	}
+	return row
+}
+
+func convertToEpoch(row epochRow) Epoch {
+	epoch := Epoch{
+		Index:      row.Index,
+		Status:     row.Status,
+		FirstBlock: row.FirstBlock,
+		OpenedAt:   time.UnixMilli(row.OpenedAt),
+	}
+	if row.FirstInputIndex != nil {
+		epoch.FirstInputIndex = *row.FirstInputIndex
+	}
+	if row.LastInputIndex != nil {
+		epoch.LastInputIndex = *row.LastInputIndex
+	}
+	if row.ClosedAt != nil {
+		epoch.ClosedAt = time.UnixMilli(*row.ClosedAt)
+	}
+	if row.OutputsEpochRootHash != nil {
+		epoch.OutputsEpochRootHash = common.HexToHash(*row.OutputsEpochRootHash)
+	}
+	if row.MachineStateHash != nil {
+		epoch.MachineStateHash = common.HexToHash(*row.MachineStateHash)
+	}
+	if row.ClaimTransactionHash != nil {
+		epoch.ClaimTransactionHash = common.HexToHash(*row.ClaimTransactionHash)
+	}
+	return epoch
+}
+
+// Prove the epoch if it is closed and all its inputs were processed.
+// Return whether the epoch was proved.
+func proveEpoch(
+	ctx context.Context,
+	epochRepository *EpochRepository,
+	inputRepository *InputRepository,
+	proofRepository *ProofRepository,
+	index uint64,
+) (bool, error) {
+	epoch, err := epochRepository.FindByIndex(ctx, index)
+	if err != nil {
+		return false, err
+	}
+	if epoch == nil || epoch.Status != EpochStatusClosed {
+		return false, nil
+	}
+	roots, err := inputRepository.FindOutputHashesRootHashes(ctx, epoch.FirstInputIndex, epoch.LastInputIndex)
+	if err != nil {
+		return false, err
+	}
+	if uint64(len(roots)) != epoch.LastInputIndex-epoch.FirstInputIndex+1 {
+		return false, nil
+	}
+	root, err := proofRepository.ProveEpoch(ctx, epoch.FirstInputIndex, epoch.LastInputIndex, roots)
+	if err != nil {
+		return false, err
+	}
+	epoch.Status = EpochStatusProved
+	epoch.OutputsEpochRootHash = root
+	// there is no Cartesi machine whose state could be committed to, so the hash is zero
+	epoch.MachineStateHash = common.Hash{}
+	_, err = epochRepository.Update(ctx, *epoch)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
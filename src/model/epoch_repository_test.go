@@ -0,0 +1,169 @@
+package model
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/suite"
+)
+
+type EpochSuite struct {
+	suite.Suite
+	model    *AppModel
+	driver   string
+	database *testDatabase
+}
+
+func (s *EpochSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	var err error
+	s.database, err = openTestDatabase(s.driver)
+	s.Require().NoError(err)
+	s.model = NewAppModel(nil, s.database.Db)
+	s.model.EpochDuration = 0
+}
+
+func (s *EpochSuite) TearDownTest() {
+	s.database.Close()
+}
+
+func TestEpochSuite(t *testing.T) {
+	runWithDrivers(t, func(driver string) *EpochSuite {
+		return &EpochSuite{driver: driver}
+	})
+}
+
+func (s *EpochSuite) addInputs(blockNumbers ...uint64) {
+	count, err := s.model.InputRepository.Count(context.Background(), nil)
+	s.Require().NoError(err)
+	for i, blockNumber := range blockNumbers {
+		err := s.model.AddAdvanceInput(common.Address{}, []byte{0xaa}, blockNumber, time.Now(), int(count)+i)
+		s.Require().NoError(err)
+	}
+}
+
+// Process all the pending advance inputs.
+func (s *EpochSuite) processInputs() {
+	for {
+		input, err := s.model.FinishAndGetNext(true)
+		s.Require().NoError(err)
+		if input == nil {
+			return
+		}
+	}
+}
+
+func (s *EpochSuite) epochOf(inputIndex int) uint64 {
+	input, err := s.model.InputRepository.FindByIndex(context.Background(), inputIndex)
+	s.Require().NoError(err)
+	s.Require().NotNil(input)
+	return input.EpochIndex
+}
+
+func (s *EpochSuite) TestInputsShareTheOpenEpoch() {
+	s.addInputs(1, 2, 3)
+	s.Equal(uint64(0), s.epochOf(0))
+	s.Equal(uint64(0), s.epochOf(2))
+	epoch, err := s.model.GetEpoch(0)
+	s.NoError(err)
+	s.Equal(EpochStatusOpen, epoch.Status)
+	s.Equal(uint64(1), epoch.FirstBlock)
+}
+
+func (s *EpochSuite) TestDuplicatedInputKeepsItsEpoch() {
+	s.addInputs(1)
+	_, err := s.model.CloseEpoch()
+	s.NoError(err)
+	err = s.model.AddAdvanceInput(common.Address{}, []byte{0xaa}, 1, time.Now(), 0)
+	s.NoError(err)
+	epochs, err := s.model.GetEpochs()
+	s.NoError(err)
+	s.Len(epochs, 1)
+}
+
+func (s *EpochSuite) TestCloseByBlockCount() {
+	s.model.EpochBlockCount = 10
+	s.addInputs(5, 14, 15, 20)
+	s.Equal(uint64(0), s.epochOf(1))
+	s.Equal(uint64(1), s.epochOf(2))
+	s.Equal(uint64(1), s.epochOf(3))
+	epoch, err := s.model.GetEpoch(0)
+	s.NoError(err)
+	s.Equal(EpochStatusClosed, epoch.Status)
+	s.Equal(uint64(0), epoch.FirstInputIndex)
+	s.Equal(uint64(1), epoch.LastInputIndex)
+}
+
+func (s *EpochSuite) TestCloseByDuration() {
+	s.model.EpochDuration = time.Hour
+	s.addInputs(1, 2)
+	closed, err := s.model.CloseEpochIfDue(time.Now())
+	s.NoError(err)
+	s.Nil(closed)
+	closed, err = s.model.CloseEpochIfDue(time.Now().Add(time.Hour))
+	s.NoError(err)
+	s.Require().NotNil(closed)
+	s.Equal(uint64(0), closed.Index)
+	s.Equal(uint64(1), closed.LastInputIndex)
+	s.addInputs(3)
+	s.Equal(uint64(1), s.epochOf(2))
+}
+
+func (s *EpochSuite) TestCloseWithoutInputs() {
+	closed, err := s.model.CloseEpoch()
+	s.NoError(err)
+	s.Nil(closed)
+}
+
+func (s *EpochSuite) TestEpochIsProvedAfterItsInputsAreProcessed() {
+	s.addInputs(1, 2)
+	closed, err := s.model.CloseEpoch()
+	s.NoError(err)
+	s.Require().NotNil(closed)
+	s.Equal(EpochStatusClosed, closed.Status)
+	s.addInputs(3)
+
+	s.processInputs()
+	epoch, err := s.model.GetEpoch(0)
+	s.NoError(err)
+	s.Equal(EpochStatusProved, epoch.Status)
+	s.NotEqual(common.Hash{}, epoch.OutputsEpochRootHash)
+	epoch, err = s.model.GetEpoch(1)
+	s.NoError(err)
+	s.Equal(EpochStatusOpen, epoch.Status)
+
+	closed, err = s.model.CloseEpoch()
+	s.NoError(err)
+	s.Require().NotNil(closed)
+	s.Equal(EpochStatusProved, closed.Status)
+	proved, err := s.model.EpochRepository.FindByStatus(context.Background(), EpochStatusProved)
+	s.NoError(err)
+	s.Len(proved, 2)
+}
+
+func (s *EpochSuite) TestClaimProvedEpoch() {
+	s.addInputs(1)
+	_, err := s.model.CloseEpoch()
+	s.NoError(err)
+	err = s.model.SetEpochClaimed(0, common.HexToHash("0x01"))
+	s.Error(err)
+
+	s.processInputs()
+	epochs, err := s.model.GetEpochsToClaim()
+	s.NoError(err)
+	s.Require().Len(epochs, 1)
+	err = s.model.SetEpochClaimed(0, common.HexToHash("0x01"))
+	s.NoError(err)
+	epochs, err = s.model.GetEpochsToClaim()
+	s.NoError(err)
+	s.Empty(epochs)
+	epoch, err := s.model.GetEpoch(0)
+	s.NoError(err)
+	s.Equal(EpochStatusClaimed, epoch.Status)
+	s.Equal(common.HexToHash("0x01"), epoch.ClaimTransactionHash)
+	s.NotEqual(common.Hash{}, epoch.OutputsEpochRootHash)
+}
@@ -0,0 +1,264 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Error returned when a filter cannot be compiled, such as when it has an unknown field.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Error of the filter compiler, which keeps the message of the wrapped error.
+type filterError struct {
+	error
+}
+
+func (e filterError) Is(target error) bool {
+	return target == ErrInvalidFilter
+}
+
+func (e filterError) Unwrap() error {
+	return e.error
+}
+
+// Column of a table that can be used in a ConvenienceFilter.
+type filterColumn struct {
+	// Name of the column in the database.
+	name string
+	// Parse the filter value into the type stored in the column.
+	convert func(value string) (any, error)
+}
+
+// Filterable columns of a table indexed by the field name used in the filter.
+type filterColumns map[string]filterColumn
+
+func integerColumn(name string) filterColumn {
+	return filterColumn{name: name, convert: toInteger}
+}
+
+func booleanColumn(name string) filterColumn {
+	return filterColumn{name: name, convert: toBoolean}
+}
+
+func addressColumn(name string) filterColumn {
+	return filterColumn{name: name, convert: toAddress}
+}
+
+func textColumn(name string) filterColumn {
+	return filterColumn{name: name, convert: toText}
+}
+
+// Column of the keys of uint256 amounts, which are compared as numbers.
+func amountColumn(name string) filterColumn {
+	return filterColumn{name: name, convert: toAmountKey}
+}
+
+func toInteger(value string) (any, error) {
+	integer, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected integer value %s", value)
+	}
+	return integer, nil
+}
+
+func toBoolean(value string) (any, error) {
+	switch value {
+	case "true":
+		return true, nil
+	case FALSE:
+		return false, nil
+	default:
+		return nil, fmt.Errorf("unexpected boolean value %s", value)
+	}
+}
+
+func toText(value string) (any, error) {
+	return value, nil
+}
+
+func toAmountKey(value string) (any, error) {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 || amount.BitLen() > 256 {
+		return nil, fmt.Errorf("unexpected amount value %s", value)
+	}
+	return amountKey(amount), nil
+}
+
+// Number of decimal digits of the largest uint256.
+const amountKeyDigits = 78
+
+// Pad the amount with zeros to the digits of a uint256, so the keys sort as the amounts.
+func amountKey(amount *big.Int) string {
+	digits := amount.String()
+	return strings.Repeat("0", amountKeyDigits-len(digits)) + digits
+}
+
+func toAddress(value string) (any, error) {
+	if !common.IsHexAddress(value) {
+		return nil, fmt.Errorf("wrong address value")
+	}
+	// addresses are stored in the checksum format
+	return common.HexToAddress(value).Hex(), nil
+}
+
+// Compile the filters into a SQL where clause.
+// The filters in the list and the operators within a filter are combined with AND;
+// nested groups are combined with the And and Or fields.
+// Return the where clause, its arguments and the index of the next query argument.
+func compileFilter(
+	filter []*ConvenienceFilter,
+	columns filterColumns,
+) (string, []interface{}, int, error) {
+	compiler := filterCompiler{columns: columns, count: 1}
+	query := ""
+	if len(filter) > 0 {
+		conditions, err := compiler.compileList(filter, "and")
+		if err != nil {
+			return "", nil, 0, filterError{err}
+		}
+		query = WHERE + conditions + " "
+	}
+	return query, compiler.args, compiler.count, nil
+}
+
+type filterCompiler struct {
+	columns filterColumns
+	args    []interface{}
+	count   int
+}
+
+func (c *filterCompiler) compileList(filter []*ConvenienceFilter, operator string) (string, error) {
+	conditions := make([]string, len(filter))
+	for i, f := range filter {
+		if f == nil {
+			return "", fmt.Errorf("empty filter")
+		}
+		condition, err := c.compileFilter(f)
+		if err != nil {
+			return "", err
+		}
+		conditions[i] = condition
+	}
+	if len(conditions) == 1 {
+		return conditions[0], nil
+	}
+	return "(" + strings.Join(conditions, " "+operator+" ") + ")", nil
+}
+
+func (c *filterCompiler) compileFilter(filter *ConvenienceFilter) (string, error) {
+	conditions := []string{}
+	if filter.Field != nil {
+		fieldConditions, err := c.compileField(filter)
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, fieldConditions...)
+	}
+	if filter.And != nil {
+		condition, err := c.compileList(filter.And, "and")
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, condition)
+	}
+	if filter.Or != nil {
+		condition, err := c.compileList(filter.Or, "or")
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, condition)
+	}
+	switch len(conditions) {
+	case 0:
+		return "", fmt.Errorf("empty filter")
+	case 1:
+		return conditions[0], nil
+	default:
+		return "(" + strings.Join(conditions, " and ") + ")", nil
+	}
+}
+
+func (c *filterCompiler) compileField(filter *ConvenienceFilter) ([]string, error) {
+	column, ok := c.columns[*filter.Field]
+	if !ok {
+		return nil, fmt.Errorf("unexpected field %s", *filter.Field)
+	}
+	conditions := []string{}
+	comparisons := []struct {
+		operator string
+		value    *string
+	}{
+		{"=", filter.Eq},
+		{"<>", filter.Ne},
+		{">", filter.Gt},
+		{">=", filter.Gte},
+		{"<", filter.Lt},
+		{"<=", filter.Lte},
+	}
+	for _, comparison := range comparisons {
+		if comparison.value == nil {
+			continue
+		}
+		placeholder, err := c.addArg(column, *comparison.value)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, fmt.Sprintf("%s %s %s", column.name, comparison.operator, placeholder))
+	}
+	if filter.In != nil {
+		condition, err := c.compileIn(column, "IN", filter.In)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	if filter.Nin != nil {
+		condition, err := c.compileIn(column, "NOT IN", filter.Nin)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	if len(conditions) == 0 {
+		return nil, fmt.Errorf("operation not implemented")
+	}
+	return conditions, nil
+}
+
+func (c *filterCompiler) compileIn(column filterColumn, operator string, values []*string) (string, error) {
+	if len(values) == 0 {
+		// an empty IN matches nothing and an empty NOT IN matches everything
+		if operator == "IN" {
+			return "1 = 0", nil
+		}
+		return "1 = 1", nil
+	}
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		if value == nil {
+			return "", fmt.Errorf("unexpected null value for %s", column.name)
+		}
+		placeholder, err := c.addArg(column, *value)
+		if err != nil {
+			return "", err
+		}
+		placeholders[i] = placeholder
+	}
+	return fmt.Sprintf("%s %s (%s)", column.name, operator, strings.Join(placeholders, ", ")), nil
+}
+
+func (c *filterCompiler) addArg(column filterColumn, value string) (string, error) {
+	arg, err := column.convert(value)
+	if err != nil {
+		return "", err
+	}
+	c.args = append(c.args, arg)
+	placeholder := fmt.Sprintf("$%d", c.count)
+	c.count += 1
+	return placeholder, nil
+}
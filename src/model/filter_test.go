@@ -0,0 +1,134 @@
+package model
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+type FilterSuite struct {
+	suite.Suite
+	repository *VoucherRepository
+	driver     string
+	database   *testDatabase
+}
+
+func (s *FilterSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	var err error
+	s.database, err = openTestDatabase(s.driver)
+	s.Require().NoError(err)
+	s.repository = &VoucherRepository{
+		Db: *s.database.Db,
+	}
+	err = s.repository.CreateTables()
+	s.NoError(err)
+}
+
+func (s *FilterSuite) TearDownTest() {
+	s.database.Close()
+}
+
+func TestFilterSuite(t *testing.T) {
+	runWithDrivers(t, func(driver string) *FilterSuite {
+		return &FilterSuite{driver: driver}
+	})
+}
+
+func ptr(value string) *string {
+	return &value
+}
+
+func (s *FilterSuite) TestCompileEmpty() {
+	query, args, count, err := compileFilter(nil, voucherColumns)
+	s.NoError(err)
+	s.Equal("", query)
+	s.Len(args, 0)
+	s.Equal(1, count)
+}
+
+func (s *FilterSuite) TestCompileOperators() {
+	filter := []*ConvenienceFilter{
+		{Field: ptr(INPUT_INDEX), Gte: ptr("10"), Lte: ptr("50")},
+		{Field: ptr(OUTPUT_INDEX), In: []*string{ptr("1"), ptr("2")}},
+		{Field: ptr(EXECUTED), Ne: ptr("true")},
+	}
+	query, args, count, err := compileFilter(filter, voucherColumns)
+	s.NoError(err)
+	s.Equal("WHERE ((input_index >= $1 and input_index <= $2) and output_index IN ($3, $4) and executed <> $5) ", query)
+	s.Equal([]interface{}{int64(10), int64(50), int64(1), int64(2), true}, args)
+	s.Equal(6, count)
+}
+
+func (s *FilterSuite) TestCompileNestedGroups() {
+	filter := []*ConvenienceFilter{
+		{Or: []*ConvenienceFilter{
+			{Field: ptr(INPUT_INDEX), Lt: ptr("1")},
+			{And: []*ConvenienceFilter{
+				{Field: ptr(INPUT_INDEX), Gt: ptr("5")},
+				{Field: ptr(OUTPUT_INDEX), Nin: []*string{ptr("0")}},
+			}},
+		}},
+	}
+	query, args, _, err := compileFilter(filter, voucherColumns)
+	s.NoError(err)
+	s.Equal("WHERE (input_index < $1 or (input_index > $2 and output_index NOT IN ($3))) ", query)
+	s.Len(args, 3)
+}
+
+func (s *FilterSuite) TestCompileErrors() {
+	_, _, _, err := compileFilter([]*ConvenienceFilter{{Field: ptr("Payload"), Eq: ptr("0x")}}, voucherColumns)
+	s.EqualError(err, "unexpected field Payload")
+	_, _, _, err = compileFilter([]*ConvenienceFilter{{Field: ptr(INPUT_INDEX)}}, voucherColumns)
+	s.EqualError(err, "operation not implemented")
+	_, _, _, err = compileFilter([]*ConvenienceFilter{{}}, voucherColumns)
+	s.EqualError(err, "empty filter")
+	_, _, _, err = compileFilter([]*ConvenienceFilter{{Field: ptr(EXECUTED), Eq: ptr("yes")}}, voucherColumns)
+	s.EqualError(err, "unexpected boolean value yes")
+	_, _, _, err = compileFilter([]*ConvenienceFilter{{Field: ptr(INPUT_INDEX), Eq: ptr("one")}}, voucherColumns)
+	s.EqualError(err, "unexpected integer value one")
+	s.ErrorIs(err, ErrInvalidFilter)
+}
+
+func (s *FilterSuite) TestFindVouchersToAnyDestinationInRange() {
+	ctx := context.Background()
+	x := common.HexToAddress("0x26A61aF89053c847B4bd5084E2caFe7211874a29")
+	y := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	z := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	for i := 0; i < 60; i++ {
+		destination := []common.Address{x, y, z}[i%3]
+		_, err := s.repository.CreateVoucher(ctx, &ConvenienceVoucher{
+			Destination: destination,
+			InputIndex:  uint64(i),
+			OutputIndex: 0,
+			Executed:    i%2 == 0,
+		})
+		s.NoError(err)
+	}
+	filter := []*ConvenienceFilter{
+		{Or: []*ConvenienceFilter{
+			{Field: ptr(DESTINATION), Eq: ptr(x.Hex())},
+			// addresses are matched regardless of the case
+			{Field: ptr(DESTINATION), Eq: ptr("0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266")},
+		}},
+		{Field: ptr(INPUT_INDEX), Gte: ptr("10"), Lte: ptr("50")},
+		{Field: ptr(EXECUTED), Eq: ptr(FALSE)},
+	}
+	count, err := s.repository.Count(ctx, filter)
+	s.NoError(err)
+	s.Equal(13, int(count))
+	vouchers, err := s.repository.FindAllVouchers(ctx, nil, nil, nil, nil, filter)
+	s.NoError(err)
+	s.Len(vouchers.Rows, 13)
+	for _, voucher := range vouchers.Rows {
+		s.NotEqual(z, voucher.Destination)
+		s.False(voucher.Executed)
+		s.GreaterOrEqual(voucher.InputIndex, uint64(10))
+		s.LessOrEqual(voucher.InputIndex, uint64(50))
+	}
+}
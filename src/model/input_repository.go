@@ -1,11 +1,14 @@
 package model
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"log/slog"
-	"strings"
+	"math/big"
 	"time"
 
+	"github.com/calindra/rollups-server/src/migrations"
 	"github.com/calindra/rollups-server/src/util"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jmoiron/sqlx"
@@ -14,22 +17,30 @@ import (
 const INDEX_FIELD = "Index"
 const WHERE = "WHERE "
 
+// Columns of the inputs read by parseInput.
+const selectInputs = `SELECT
+		input_index,
+		status,
+		msg_sender,
+		payload,
+		block_number,
+		block_timestamp,
+		prev_randao,
+		exception,
+		epoch_index,
+		chain_id,
+		app_contract,
+		transaction_hash,
+		log_index,
+		block_hash FROM inputs `
+
 type InputRepository struct {
 	Db *sqlx.DB
 }
 
+// Create the tables by applying the pending schema migrations.
 func (r *InputRepository) CreateTables() error {
-	schema := `CREATE TABLE IF NOT EXISTS inputs (
-		id 				INTEGER NOT NULL PRIMARY KEY,
-		input_index		integer,
-		status	 		text,
-		msg_sender	 	text,
-		payload			text,
-		block_number	integer,
-		block_timestamp	integer,
-		prev_randao		integer,
-		exception		text);`
-	_, err := r.Db.Exec(schema)
+	_, err := migrations.Migrate(context.Background(), r.Db, false)
 	if err == nil {
 		slog.Debug("Inputs table created")
 	} else {
@@ -38,18 +49,18 @@ func (r *InputRepository) CreateTables() error {
 	return err
 }
 
-func (r *InputRepository) Create(input AdvanceInput) (*AdvanceInput, error) {
-	exist, err := r.FindByIndex(input.Index)
+func (r *InputRepository) Create(ctx context.Context, input AdvanceInput) (*AdvanceInput, error) {
+	exist, err := r.FindByIndex(ctx, input.Index)
 	if err != nil {
 		return nil, err
 	}
 	if exist != nil {
 		return exist, nil
 	}
-	return r.rawCreate(input)
+	return r.rawCreate(ctx, input)
 }
 
-func (r *InputRepository) rawCreate(input AdvanceInput) (*AdvanceInput, error) {
+func (r *InputRepository) rawCreate(ctx context.Context, input AdvanceInput) (*AdvanceInput, error) {
 	insertSql := `INSERT INTO inputs (
 		input_index,
 		status,
@@ -58,7 +69,13 @@ func (r *InputRepository) rawCreate(input AdvanceInput) (*AdvanceInput, error) {
 		block_number,
 		block_timestamp,
 		prev_randao,
-		exception
+		exception,
+		epoch_index,
+		chain_id,
+		app_contract,
+		transaction_hash,
+		log_index,
+		block_hash
 	) VALUES (
 		$1,
 		$2,
@@ -67,9 +84,16 @@ func (r *InputRepository) rawCreate(input AdvanceInput) (*AdvanceInput, error) {
 		$5,
 		$6,
 		$7,
-		$8
+		$8,
+		$9,
+		$10,
+		$11,
+		$12,
+		$13,
+		$14
 	);`
-	_, err := r.Db.Exec(
+	_, err := executor(ctx, r.Db).ExecContext(
+		ctx,
 		insertSql,
 		input.Index,
 		input.Status,
@@ -77,8 +101,14 @@ func (r *InputRepository) rawCreate(input AdvanceInput) (*AdvanceInput, error) {
 		common.Bytes2Hex(input.Payload),
 		input.BlockNumber,
 		input.BlockTimestamp.UnixMilli(),
-		input.PrevRandao,
+		encodeValue(input.PrevRandao),
 		common.Bytes2Hex(input.Exception),
+		input.EpochIndex,
+		input.ChainId,
+		input.AppContract.Hex(),
+		input.TransactionHash.Hex(),
+		input.LogIndex,
+		input.BlockHash.Hex(),
 	)
 	if err != nil {
 		return nil, err
@@ -86,14 +116,16 @@ func (r *InputRepository) rawCreate(input AdvanceInput) (*AdvanceInput, error) {
 	return &input, nil
 }
 
-func (r *InputRepository) Update(input AdvanceInput) (*AdvanceInput, error) {
+func (r *InputRepository) Update(ctx context.Context, input AdvanceInput) (*AdvanceInput, error) {
 	sql := `UPDATE inputs
-		SET status = $1, exception = $2
-		WHERE input_index = $3`
-	_, err := r.Db.Exec(
+		SET status = $1, exception = $2, output_hashes_root_hash = $3
+		WHERE input_index = $4`
+	_, err := executor(ctx, r.Db).ExecContext(
+		ctx,
 		sql,
 		input.Status,
 		common.Bytes2Hex(input.Exception),
+		input.OutputHashesRootHash.Hex(),
 		input.Index,
 	)
 	if err != nil {
@@ -102,17 +134,11 @@ func (r *InputRepository) Update(input AdvanceInput) (*AdvanceInput, error) {
 	return &input, nil
 }
 
-func (r *InputRepository) FindByStatusNeDesc(status CompletionStatus) (*AdvanceInput, error) {
-	sql := `SELECT
-		input_index,
-		status,
-		msg_sender,
-		payload,
-		block_number,
-		timestamp,
-		exception FROM inputs WHERE status <> $1
+func (r *InputRepository) FindByStatusNeDesc(ctx context.Context, status CompletionStatus) (*AdvanceInput, error) {
+	sql := selectInputs + `WHERE status <> $1
 		ORDER BY input_index DESC`
-	res, err := r.Db.Queryx(
+	res, err := executor(ctx, r.Db).QueryxContext(
+		ctx,
 		sql,
 		status,
 	)
@@ -130,18 +156,11 @@ func (r *InputRepository) FindByStatusNeDesc(status CompletionStatus) (*AdvanceI
 	return nil, nil
 }
 
-func (r *InputRepository) FindByStatus(status CompletionStatus) (*AdvanceInput, error) {
-	sql := `SELECT
-		input_index,
-		status,
-		msg_sender,
-		payload,
-		block_number,
-		block_timestamp,
-		prev_randao,
-		exception FROM inputs WHERE status = $1
+func (r *InputRepository) FindByStatus(ctx context.Context, status CompletionStatus) (*AdvanceInput, error) {
+	sql := selectInputs + `WHERE status = $1
 		ORDER BY input_index ASC`
-	res, err := r.Db.Queryx(
+	res, err := executor(ctx, r.Db).QueryxContext(
+		ctx,
 		sql,
 		status,
 	)
@@ -159,17 +178,10 @@ func (r *InputRepository) FindByStatus(status CompletionStatus) (*AdvanceInput,
 	return nil, nil
 }
 
-func (r *InputRepository) FindByIndex(index int) (*AdvanceInput, error) {
-	sql := `SELECT
-		input_index,
-		status,
-		msg_sender,
-		payload,
-		block_number,
-		block_timestamp,
-		prev_randao,
-		exception FROM inputs WHERE input_index = $1`
-	res, err := r.Db.Queryx(
+func (r *InputRepository) FindByIndex(ctx context.Context, index int) (*AdvanceInput, error) {
+	sql := selectInputs + `WHERE input_index = $1`
+	res, err := executor(ctx, r.Db).QueryxContext(
+		ctx,
 		sql,
 		index,
 	)
@@ -188,6 +200,7 @@ func (r *InputRepository) FindByIndex(index int) (*AdvanceInput, error) {
 }
 
 func (c *InputRepository) Count(
+	ctx context.Context,
 	filter []*ConvenienceFilter,
 ) (uint64, error) {
 	query := `SELECT count(*) FROM inputs `
@@ -198,14 +211,8 @@ func (c *InputRepository) Count(
 	}
 	query += where
 	slog.Debug("Query", "query", query, "args", args)
-	stmt, err := c.Db.Preparex(query)
-	if err != nil {
-		slog.Error("Count execution error")
-		return 0, err
-	}
-	defer stmt.Close()
 	var count uint64
-	err = stmt.Get(&count, args...)
+	err = sqlx.GetContext(ctx, executor(ctx, c.Db), &count, query, args...)
 	if err != nil {
 		slog.Error("Count execution error")
 		return 0, err
@@ -214,54 +221,34 @@ func (c *InputRepository) Count(
 }
 
 func (c *InputRepository) FindAll(
+	ctx context.Context,
 	first *int,
 	last *int,
 	after *string,
 	before *string,
 	filter []*ConvenienceFilter,
+	options ...util.PageOption,
 ) (*util.PageResult[AdvanceInput], error) {
-	total, err := c.Count(filter)
+	page, err := util.ComputePage(first, last, after, before, options...)
 	if err != nil {
-		slog.Error("database error", "err", err)
 		return nil, err
 	}
-	query := `SELECT
-		input_index,
-		status,
-		msg_sender,
-		payload,
-		block_number,
-		block_timestamp,
-		prev_randao,
-		exception FROM inputs `
+	query := selectInputs + ``
 	where, args, argsCount, err := transformToInputQuery(filter)
 	if err != nil {
 		slog.Error("database error", "err", err)
 		return nil, err
 	}
-	query += where
-	query += `ORDER BY input_index ASC `
-	offset, limit, err := util.ComputePage(first, last, after, before, int(total))
-	if err != nil {
-		return nil, err
-	}
-	query += fmt.Sprintf(`LIMIT $%d `, argsCount)
-	args = append(args, limit)
-	argsCount += 1
-	query += fmt.Sprintf(`OFFSET $%d `, argsCount)
-	args = append(args, offset)
+	pageQuery, args := compilePage(page, where, args, argsCount, "input_index")
+	query += pageQuery
 
-	slog.Debug("Query", "query", query, "args", args, "total", total)
-	stmt, err := c.Db.Preparex(query)
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
+	slog.Debug("Query", "query", query, "args", args)
 	var inputs []AdvanceInput
-	rows, err := stmt.Queryx(args...)
+	rows, err := executor(ctx, c.Db).QueryxContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	for rows.Next() {
 		input, err := parseInput(rows)
 		if err != nil {
@@ -270,65 +257,136 @@ func (c *InputRepository) FindAll(
 		inputs = append(inputs, *input)
 	}
 
-	pageResult := &util.PageResult[AdvanceInput]{
-		Rows:   inputs,
-		Total:  total,
-		Offset: uint64(offset),
+	result := util.NewPageResult(page, inputs, inputCursor)
+	err = result.SetTotal(page, func() (uint64, error) {
+		return c.Count(ctx, filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Find the range of the inputs of the epoch.
+// Return false if the epoch has no inputs.
+func (r *InputRepository) FindEpochRange(ctx context.Context, epochIndex uint64) (uint64, uint64, bool, error) {
+	var inputRange struct {
+		First *uint64 `db:"first"`
+		Last  *uint64 `db:"last"`
+	}
+	query := `SELECT MIN(input_index) AS first, MAX(input_index) AS last FROM inputs WHERE epoch_index = $1`
+	err := sqlx.GetContext(ctx, executor(ctx, r.Db), &inputRange, query, epochIndex)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("find epoch range: %w", err)
+	}
+	if inputRange.First == nil || inputRange.Last == nil {
+		return 0, 0, false, nil
+	}
+	return *inputRange.First, *inputRange.Last, true, nil
+}
+
+// Find the output hashes roots of the processed inputs in the range, in index order.
+func (r *InputRepository) FindOutputHashesRootHashes(
+	ctx context.Context, firstIndex uint64, lastIndex uint64,
+) ([]common.Hash, error) {
+	var roots []string
+	query := `SELECT output_hashes_root_hash FROM inputs
+		WHERE input_index >= $1 and input_index <= $2 and status <> $3
+		ORDER BY input_index ASC`
+	err := sqlx.SelectContext(ctx, executor(ctx, r.Db), &roots, query,
+		firstIndex, lastIndex, CompletionStatusUnprocessed)
+	if err != nil {
+		return nil, fmt.Errorf("find output hashes roots: %w", err)
+	}
+	hashes := make([]common.Hash, len(roots))
+	for i, root := range roots {
+		hashes[i] = common.HexToHash(root)
+	}
+	return hashes, nil
+}
+
+// Find the blocks that have inputs, from the latest to the earliest.
+func (r *InputRepository) FindBlocks(ctx context.Context) ([]Block, error) {
+	var rows []struct {
+		Number uint64  `db:"block_number"`
+		Hash   *string `db:"block_hash"`
+	}
+	query := `SELECT DISTINCT block_number, block_hash FROM inputs ORDER BY block_number DESC`
+	err := sqlx.SelectContext(ctx, executor(ctx, r.Db), &rows, query)
+	if err != nil {
+		return nil, fmt.Errorf("find input blocks: %w", err)
+	}
+	blocks := make([]Block, len(rows))
+	for i, row := range rows {
+		blocks[i].Number = row.Number
+		if row.Hash != nil {
+			blocks[i].Hash = common.HexToHash(*row.Hash)
+		}
+	}
+	return blocks, nil
+}
+
+// Find the first input added after the block; return nil if there is none.
+func (r *InputRepository) FindFirstAfterBlock(ctx context.Context, blockNumber uint64) (*AdvanceInput, error) {
+	query := selectInputs + `WHERE block_number > $1 ORDER BY input_index ASC`
+	res, err := executor(ctx, r.Db).QueryxContext(ctx, query, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("find first input after block: %w", err)
+	}
+	defer res.Close()
+	if res.Next() {
+		return parseInput(res)
+	}
+	return nil, res.Err()
+}
+
+// Delete the inputs from the given index on.
+// Return the number of deleted inputs.
+func (r *InputRepository) DeleteFrom(ctx context.Context, inputIndex uint64) (int, error) {
+	res, err := executor(ctx, r.Db).ExecContext(ctx,
+		`DELETE FROM inputs WHERE input_index >= $1`, inputIndex)
+	if err != nil {
+		return 0, fmt.Errorf("delete inputs: %w", err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("delete inputs: %w", err)
 	}
-	return pageResult, nil
+	return int(deleted), nil
+}
+
+func inputCursor(input AdvanceInput) util.Cursor {
+	return util.Cursor{InputIndex: uint64(input.Index)}
+}
+
+// Filterable columns of the inputs table.
+var inputColumns = filterColumns{
+	INDEX_FIELD:  integerColumn("input_index"),
+	STATUS:       integerColumn("status"),
+	MSG_SENDER:   addressColumn("msg_sender"),
+	BLOCK_NUMBER: integerColumn("block_number"),
 }
 
 func transformToInputQuery(
 	filter []*ConvenienceFilter,
 ) (string, []interface{}, int, error) {
-	query := ""
-	if len(filter) > 0 {
-		query += WHERE
-	}
-	args := []interface{}{}
-	where := []string{}
-	count := 1
-	for _, filter := range filter {
-		if *filter.Field == INDEX_FIELD {
-			if filter.Eq != nil {
-				where = append(where, fmt.Sprintf("input_index = $%d ", count))
-				args = append(args, *filter.Eq)
-				count += 1
-			} else if filter.Gt != nil {
-				where = append(where, fmt.Sprintf("input_index > $%d ", count))
-				args = append(args, *filter.Gt)
-				count += 1
-			} else if filter.Lt != nil {
-				where = append(where, fmt.Sprintf("input_index < $%d ", count))
-				args = append(args, *filter.Lt)
-				count += 1
-			} else {
-				return "", nil, 0, fmt.Errorf("operation not implemented")
-			}
-		} else if *filter.Field == "Status" {
-			if filter.Ne != nil {
-				where = append(where, fmt.Sprintf("status <> $%d ", count))
-				args = append(args, *filter.Ne)
-				count += 1
-			} else {
-				return "", nil, 0, fmt.Errorf("operation not implemented")
-			}
-		} else {
-			return "", nil, 0, fmt.Errorf("unexpected field %s", *filter.Field)
-		}
-	}
-	query += strings.Join(where, " and ")
-	return query, args, count, nil
+	return compileFilter(filter, inputColumns)
 }
 
 func parseInput(res *sqlx.Rows) (*AdvanceInput, error) {
 	var (
-		input          AdvanceInput
-		msgSender      string
-		payload        string
-		blockTimestamp int64
-		prevRandao     uint64
-		exception      string
+		input           AdvanceInput
+		msgSender       string
+		payload         string
+		blockTimestamp  int64
+		prevRandao      sql.NullString
+		exception       string
+		epochIndex      sql.NullInt64
+		chainId         sql.NullInt64
+		appContract     sql.NullString
+		transactionHash sql.NullString
+		logIndex        sql.NullInt64
+		blockHash       sql.NullString
 	)
 	err := res.Scan(
 		&input.Index,
@@ -339,6 +397,12 @@ func parseInput(res *sqlx.Rows) (*AdvanceInput, error) {
 		&blockTimestamp,
 		&prevRandao,
 		&exception,
+		&epochIndex,
+		&chainId,
+		&appContract,
+		&transactionHash,
+		&logIndex,
+		&blockHash,
 	)
 	if err != nil {
 		return nil, err
@@ -346,7 +410,17 @@ func parseInput(res *sqlx.Rows) (*AdvanceInput, error) {
 	input.Payload = common.Hex2Bytes(payload)
 	input.MsgSender = common.HexToAddress(msgSender)
 	input.BlockTimestamp = time.UnixMilli(blockTimestamp)
-	input.PrevRandao = prevRandao
+	if prevRandao.Valid {
+		input.PrevRandao, _ = new(big.Int).SetString(prevRandao.String, 10)
+	}
 	input.Exception = common.Hex2Bytes(exception)
+	// the inputs added before the epochs were introduced belong to epoch zero
+	input.EpochIndex = uint64(epochIndex.Int64)
+	// the metadata is missing for the inputs added before it was stored
+	input.ChainId = uint64(chainId.Int64)
+	input.AppContract = common.HexToAddress(appContract.String)
+	input.TransactionHash = common.HexToHash(transactionHash.String)
+	input.LogIndex = uint64(logIndex.Int64)
+	input.BlockHash = common.HexToHash(blockHash.String)
 	return &input, nil
 }
@@ -1,57 +1,54 @@
 package model
 
 import (
-	"fmt"
+	"context"
 	"log/slog"
-	"math/rand"
-	"os"
-	"path"
+	"math/big"
 	"testing"
 	"time"
 
 	"github.com/calindra/rollups-server/src/util"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/suite"
 )
 
 type InputRepositorySuite struct {
 	suite.Suite
 	inputRepository *InputRepository
-	tempDir         string
+	driver          string
+	database        *testDatabase
 }
 
 func (s *InputRepositorySuite) SetupTest() {
 	util.ConfigureLog(slog.LevelDebug)
-	tempDir, err := os.MkdirTemp("", "")
-	s.tempDir = tempDir
-	s.NoError(err)
-	sqliteFileName := fmt.Sprintf("input%d.sqlite3", rand.Intn(1000))
-	sqliteFileName = path.Join(tempDir, sqliteFileName)
-	// db := sqlx.MustConnect("sqlite3", ":memory:")
-	db := sqlx.MustConnect("sqlite3", sqliteFileName)
+	var err error
+	s.database, err = openTestDatabase(s.driver)
+	s.Require().NoError(err)
 	s.inputRepository = &InputRepository{
-		Db: db,
+		Db: s.database.Db,
 	}
 	err = s.inputRepository.CreateTables()
 	s.NoError(err)
 }
 
+func (s *InputRepositorySuite) TearDownTest() {
+	s.database.Close()
+}
+
 func TestInputRepositorySuite(t *testing.T) {
-	// t.Parallel()
-	suite.Run(t, new(InputRepositorySuite))
+	runWithDrivers(t, func(driver string) *InputRepositorySuite {
+		return &InputRepositorySuite{driver: driver}
+	})
 }
 
 func (s *InputRepositorySuite) TestCreateTables() {
-	defer s.teardown()
 	err := s.inputRepository.CreateTables()
 	s.NoError(err)
 }
 
 func (s *InputRepositorySuite) TestCreateInput() {
-	defer s.teardown()
-	input, err := s.inputRepository.Create(AdvanceInput{
+	input, err := s.inputRepository.Create(context.Background(), AdvanceInput{
 		Index:          0,
 		Status:         CompletionStatusUnprocessed,
 		MsgSender:      common.Address{},
@@ -64,8 +61,7 @@ func (s *InputRepositorySuite) TestCreateInput() {
 }
 
 func (s *InputRepositorySuite) TestFixCreateInputDuplicated() {
-	defer s.teardown()
-	input, err := s.inputRepository.Create(AdvanceInput{
+	input, err := s.inputRepository.Create(context.Background(), AdvanceInput{
 		Index:          0,
 		Status:         CompletionStatusUnprocessed,
 		MsgSender:      common.Address{},
@@ -75,7 +71,7 @@ func (s *InputRepositorySuite) TestFixCreateInputDuplicated() {
 	})
 	s.NoError(err)
 	s.Equal(0, input.Index)
-	input, err = s.inputRepository.Create(AdvanceInput{
+	input, err = s.inputRepository.Create(context.Background(), AdvanceInput{
 		Index:          0,
 		Status:         CompletionStatusUnprocessed,
 		MsgSender:      common.Address{},
@@ -85,25 +81,59 @@ func (s *InputRepositorySuite) TestFixCreateInputDuplicated() {
 	})
 	s.NoError(err)
 	s.Equal(0, input.Index)
-	count, err := s.inputRepository.Count(nil)
+	count, err := s.inputRepository.Count(context.Background(), nil)
 	s.NoError(err)
 	s.Equal(uint64(1), count)
 }
 
+func (s *InputRepositorySuite) TestReadInTransaction() {
+	err := inTransaction(s.inputRepository.Db, func(ctx context.Context) error {
+		input := AdvanceInput{
+			Index:          0,
+			Status:         CompletionStatusUnprocessed,
+			Payload:        common.Hex2Bytes("1122"),
+			BlockNumber:    1,
+			BlockTimestamp: time.Now(),
+		}
+		_, err := s.inputRepository.Create(ctx, input)
+		s.Require().NoError(err)
+		// the uncommitted input is seen by the reads of the transaction
+		_, err = s.inputRepository.Create(ctx, input)
+		s.Require().NoError(err)
+		stored, err := s.inputRepository.FindByStatus(ctx, CompletionStatusUnprocessed)
+		s.Require().NoError(err)
+		s.Require().NotNil(stored)
+		count, err := s.inputRepository.Count(ctx, nil)
+		s.Require().NoError(err)
+		s.Equal(uint64(1), count)
+		page, err := s.inputRepository.FindAll(ctx, nil, nil, nil, nil, nil)
+		s.Require().NoError(err)
+		s.Len(page.Rows, 1)
+		return nil
+	})
+	s.NoError(err)
+}
+
 func (s *InputRepositorySuite) TestCreateAndFindInputByIndex() {
-	defer s.teardown()
-	input, err := s.inputRepository.Create(AdvanceInput{
-		Index:          123,
-		Status:         CompletionStatusUnprocessed,
-		MsgSender:      common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
-		Payload:        common.Hex2Bytes("1122"),
-		BlockNumber:    1,
-		BlockTimestamp: time.Now(),
+	// a RANDAO mix uses the whole uint256
+	prevRandao := new(big.Int).Lsh(big.NewInt(0xdead), 240)
+	input, err := s.inputRepository.Create(context.Background(), AdvanceInput{
+		Index:           123,
+		Status:          CompletionStatusUnprocessed,
+		MsgSender:       common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		Payload:         common.Hex2Bytes("1122"),
+		BlockNumber:     1,
+		BlockTimestamp:  time.Now(),
+		PrevRandao:      prevRandao,
+		ChainId:         31337,
+		AppContract:     common.HexToAddress("0x45290f5A64Ea31887C7bc25fB3269173bCd01093"),
+		TransactionHash: common.HexToHash("0xabcd"),
+		LogIndex:        3,
 	})
 	s.NoError(err)
 	s.Equal(123, input.Index)
 
-	input2, err := s.inputRepository.FindByIndex(123)
+	input2, err := s.inputRepository.FindByIndex(context.Background(), 123)
 	s.NoError(err)
 	s.Equal(123, input.Index)
 	s.Equal(input.Status, input2.Status)
@@ -111,11 +141,15 @@ func (s *InputRepositorySuite) TestCreateAndFindInputByIndex() {
 	s.Equal("1122", common.Bytes2Hex(input.Payload))
 	s.Equal(1, int(input2.BlockNumber))
 	s.Equal(input.BlockTimestamp.UnixMilli(), input2.BlockTimestamp.UnixMilli())
+	s.Equal(prevRandao, input2.PrevRandao)
+	s.Equal(uint64(31337), input2.ChainId)
+	s.Equal(input.AppContract, input2.AppContract)
+	s.Equal(input.TransactionHash, input2.TransactionHash)
+	s.Equal(uint64(3), input2.LogIndex)
 }
 
 func (s *InputRepositorySuite) TestCreateInputAndUpdateStatus() {
-	defer s.teardown()
-	input, err := s.inputRepository.Create(AdvanceInput{
+	input, err := s.inputRepository.Create(context.Background(), AdvanceInput{
 		Index:          2222,
 		Status:         CompletionStatusUnprocessed,
 		MsgSender:      common.Address{},
@@ -127,49 +161,68 @@ func (s *InputRepositorySuite) TestCreateInputAndUpdateStatus() {
 	s.Equal(2222, input.Index)
 
 	input.Status = CompletionStatusAccepted
-	_, err = s.inputRepository.Update(*input)
+	_, err = s.inputRepository.Update(context.Background(), *input)
 	s.NoError(err)
 
-	input2, err := s.inputRepository.FindByIndex(2222)
+	input2, err := s.inputRepository.FindByIndex(context.Background(), 2222)
 	s.NoError(err)
 	s.Equal(CompletionStatusAccepted, input2.Status)
 }
 
+func (s *InputRepositorySuite) TestFindByStatusNeDesc() {
+	for i := 0; i < 3; i++ {
+		input := AdvanceInput{
+			Index:          i,
+			Status:         CompletionStatusUnprocessed,
+			Payload:        common.Hex2Bytes("1122"),
+			BlockTimestamp: time.Now(),
+		}
+		if i < 2 {
+			input.Status = CompletionStatusAccepted
+		}
+		_, err := s.inputRepository.Create(context.Background(), input)
+		s.NoError(err)
+	}
+	input, err := s.inputRepository.FindByStatusNeDesc(context.Background(), CompletionStatusUnprocessed)
+	s.NoError(err)
+	s.Require().NotNil(input)
+	s.Equal(1, input.Index)
+	s.Equal(CompletionStatusAccepted, input.Status)
+}
+
 func (s *InputRepositorySuite) TestCreateInputFindByStatus() {
-	defer s.teardown()
-	input, err := s.inputRepository.Create(AdvanceInput{
+	input, err := s.inputRepository.Create(context.Background(), AdvanceInput{
 		Index:          2222,
 		Status:         CompletionStatusUnprocessed,
 		MsgSender:      common.Address{},
 		Payload:        common.Hex2Bytes("0x1122"),
 		BlockNumber:    1,
-		PrevRandao:     0,
+		PrevRandao:     big.NewInt(0),
 		BlockTimestamp: time.Now(),
 	})
 	s.NoError(err)
 	s.Equal(2222, input.Index)
 
-	input2, err := s.inputRepository.FindByStatus(CompletionStatusUnprocessed)
+	input2, err := s.inputRepository.FindByStatus(context.Background(), CompletionStatusUnprocessed)
 	s.NoError(err)
 	s.Equal(2222, input2.Index)
 
 	input.Status = CompletionStatusAccepted
-	_, err = s.inputRepository.Update(*input)
+	_, err = s.inputRepository.Update(context.Background(), *input)
 	s.NoError(err)
 
-	input2, err = s.inputRepository.FindByStatus(CompletionStatusUnprocessed)
+	input2, err = s.inputRepository.FindByStatus(context.Background(), CompletionStatusUnprocessed)
 	s.NoError(err)
 	s.Nil(input2)
 
-	input2, err = s.inputRepository.FindByStatus(CompletionStatusAccepted)
+	input2, err = s.inputRepository.FindByStatus(context.Background(), CompletionStatusAccepted)
 	s.NoError(err)
 	s.Equal(2222, input2.Index)
 }
 
 func (s *InputRepositorySuite) TestFindByIndexGt() {
-	defer s.teardown()
 	for i := 0; i < 5; i++ {
-		input, err := s.inputRepository.Create(AdvanceInput{
+		input, err := s.inputRepository.Create(context.Background(), AdvanceInput{
 			Index:          i,
 			Status:         CompletionStatusUnprocessed,
 			MsgSender:      common.Address{},
@@ -187,15 +240,17 @@ func (s *InputRepositorySuite) TestFindByIndexGt() {
 		Field: &field,
 		Gt:    &value,
 	})
-	resp, err := s.inputRepository.FindAll(nil, nil, nil, nil, filters)
+	resp, err := s.inputRepository.FindAll(context.Background(), nil, nil, nil, nil, filters)
+	s.NoError(err)
+	s.Len(resp.Rows, 3)
+	total, err := s.inputRepository.Count(context.Background(), filters)
 	s.NoError(err)
-	s.Equal(3, int(resp.Total))
+	s.Equal(3, int(total))
 }
 
 func (s *InputRepositorySuite) TestFindByIndexLt() {
-	defer s.teardown()
 	for i := 0; i < 5; i++ {
-		input, err := s.inputRepository.Create(AdvanceInput{
+		input, err := s.inputRepository.Create(context.Background(), AdvanceInput{
 			Index:          i,
 			Status:         CompletionStatusUnprocessed,
 			MsgSender:      common.Address{},
@@ -213,11 +268,10 @@ func (s *InputRepositorySuite) TestFindByIndexLt() {
 		Field: &field,
 		Lt:    &value,
 	})
-	resp, err := s.inputRepository.FindAll(nil, nil, nil, nil, filters)
+	resp, err := s.inputRepository.FindAll(context.Background(), nil, nil, nil, nil, filters)
 	s.NoError(err)
-	s.Equal(3, int(resp.Total))
-}
-
-func (s *InputRepositorySuite) teardown() {
-	defer os.RemoveAll(s.tempDir)
+	s.Len(resp.Rows, 3)
+	total, err := s.inputRepository.Count(context.Background(), filters)
+	s.NoError(err)
+	s.Equal(3, int(total))
 }
@@ -0,0 +1,214 @@
+package model
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/calindra/rollups-server/src/migrations"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jmoiron/sqlx"
+)
+
+type InspectRepository struct {
+	Db *sqlx.DB
+}
+
+// Create the tables by applying the pending schema migrations.
+func (r *InspectRepository) CreateTables() error {
+	_, err := migrations.Migrate(context.Background(), r.Db, false)
+	if err == nil {
+		slog.Debug("Inspects table created")
+	} else {
+		slog.Error("Create table error", "error", err)
+	}
+	return err
+}
+
+// Store a new inspect input.
+// Return the input with the index assigned by the database.
+func (r *InspectRepository) Create(input InspectInput) (*InspectInput, error) {
+	insertSql := `INSERT INTO inspects (
+		status,
+		payload,
+		processed_input_count,
+		exception,
+		created_at,
+		finished_at
+	) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	err := r.Db.QueryRowx(
+		insertSql,
+		input.Status,
+		common.Bytes2Hex(input.Payload),
+		input.ProcessedInputCount,
+		common.Bytes2Hex(input.Exception),
+		time.Now().UnixMilli(),
+		0,
+	).Scan(&input.Index)
+	if err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// Save the result of an inspect input, including its reports.
+func (r *InspectRepository) Update(input InspectInput) (*InspectInput, error) {
+	tx, err := r.Db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	updateSql := `UPDATE inspects
+		SET status = $1, processed_input_count = $2, exception = $3, finished_at = $4
+		WHERE id = $5`
+	_, err = tx.Exec(
+		updateSql,
+		input.Status,
+		input.ProcessedInputCount,
+		common.Bytes2Hex(input.Exception),
+		time.Now().UnixMilli(),
+		input.Index,
+	)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`DELETE FROM inspect_reports WHERE inspect_index = $1`, input.Index)
+	if err != nil {
+		return nil, err
+	}
+	insertReport := `INSERT INTO inspect_reports (
+		inspect_index,
+		output_index,
+		payload) VALUES ($1, $2, $3)`
+	for _, report := range input.Reports {
+		_, err = tx.Exec(
+			insertReport,
+			input.Index,
+			report.Index,
+			common.Bytes2Hex(report.Payload),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+func (r *InspectRepository) FindByIndex(index int) (*InspectInput, error) {
+	sql := `SELECT
+		id,
+		status,
+		payload,
+		processed_input_count,
+		exception FROM inspects WHERE id = $1`
+	return r.findOne(sql, index)
+}
+
+// Find the oldest inspect input with the given status.
+func (r *InspectRepository) FindByStatus(status CompletionStatus) (*InspectInput, error) {
+	sql := `SELECT
+		id,
+		status,
+		payload,
+		processed_input_count,
+		exception FROM inspects WHERE status = $1
+		ORDER BY id ASC
+		LIMIT 1`
+	return r.findOne(sql, status)
+}
+
+// Delete the finished inspect inputs older than the given time.
+// Return the number of deleted inputs.
+func (r *InspectRepository) DeleteFinishedBefore(before time.Time) (int64, error) {
+	tx, err := r.Db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	where := `status <> $1 AND finished_at < $2`
+	_, err = tx.Exec(
+		`DELETE FROM inspect_reports WHERE inspect_index IN (SELECT id FROM inspects WHERE `+where+`)`,
+		CompletionStatusUnprocessed,
+		before.UnixMilli(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	res, err := tx.Exec(
+		`DELETE FROM inspects WHERE `+where,
+		CompletionStatusUnprocessed,
+		before.UnixMilli(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *InspectRepository) findOne(query string, args ...any) (*InspectInput, error) {
+	res, err := r.Db.Queryx(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+	if !res.Next() {
+		return nil, res.Err()
+	}
+	var (
+		input     InspectInput
+		payload   string
+		exception string
+	)
+	err = res.Scan(
+		&input.Index,
+		&input.Status,
+		&payload,
+		&input.ProcessedInputCount,
+		&exception,
+	)
+	if err != nil {
+		return nil, err
+	}
+	res.Close()
+	input.Payload = common.Hex2Bytes(payload)
+	input.Exception = common.Hex2Bytes(exception)
+	input.Reports, err = r.findReports(input.Index)
+	if err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+func (r *InspectRepository) findReports(index int) ([]Report, error) {
+	rows, err := r.Db.Queryx(`
+		SELECT output_index, payload FROM inspect_reports
+			WHERE inspect_index = $1
+			ORDER BY output_index ASC`,
+		index,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var reports []Report
+	for rows.Next() {
+		var payload string
+		report := Report{InputIndex: index}
+		if err := rows.Scan(&report.Index, &payload); err != nil {
+			return nil, err
+		}
+		report.Payload = common.Hex2Bytes(payload)
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
@@ -0,0 +1,178 @@
+package model
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+type InspectRepositorySuite struct {
+	suite.Suite
+	inspectRepository *InspectRepository
+	driver            string
+	database          *testDatabase
+}
+
+func (s *InspectRepositorySuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	var err error
+	s.database, err = openTestDatabase(s.driver)
+	s.Require().NoError(err)
+	s.inspectRepository = &InspectRepository{
+		Db: s.database.Db,
+	}
+	err = s.inspectRepository.CreateTables()
+	s.NoError(err)
+}
+
+func (s *InspectRepositorySuite) TearDownTest() {
+	s.database.Close()
+}
+
+func TestInspectRepositorySuite(t *testing.T) {
+	runWithDrivers(t, func(driver string) *InspectRepositorySuite {
+		return &InspectRepositorySuite{driver: driver}
+	})
+}
+
+func (s *InspectRepositorySuite) TestCreateTables() {
+	err := s.inspectRepository.CreateTables()
+	s.NoError(err)
+}
+
+func (s *InspectRepositorySuite) TestCreateAssignsIndex() {
+	for i := 1; i <= 3; i++ {
+		input, err := s.inspectRepository.Create(InspectInput{
+			Status:  CompletionStatusUnprocessed,
+			Payload: common.Hex2Bytes("1122"),
+		})
+		s.NoError(err)
+		s.Equal(i, input.Index)
+	}
+}
+
+func (s *InspectRepositorySuite) TestCreateAndFindByIndex() {
+	input, err := s.inspectRepository.Create(InspectInput{
+		Status:  CompletionStatusUnprocessed,
+		Payload: common.Hex2Bytes("1122"),
+	})
+	s.NoError(err)
+	input2, err := s.inspectRepository.FindByIndex(input.Index)
+	s.NoError(err)
+	s.Equal(input.Index, input2.Index)
+	s.Equal(CompletionStatusUnprocessed, input2.Status)
+	s.Equal("1122", common.Bytes2Hex(input2.Payload))
+	s.Len(input2.Reports, 0)
+}
+
+func (s *InspectRepositorySuite) TestInspectNotFound() {
+	input, err := s.inspectRepository.FindByIndex(404)
+	s.NoError(err)
+	s.Nil(input)
+}
+
+func (s *InspectRepositorySuite) TestUpdateWithReports() {
+	input, err := s.inspectRepository.Create(InspectInput{
+		Status:  CompletionStatusUnprocessed,
+		Payload: common.Hex2Bytes("1122"),
+	})
+	s.NoError(err)
+	input.Status = CompletionStatusException
+	input.ProcessedInputCount = 7
+	input.Exception = common.Hex2Bytes("ff")
+	input.Reports = []Report{
+		{Index: 0, InputIndex: input.Index, Payload: common.Hex2Bytes("aa")},
+		{Index: 1, InputIndex: input.Index, Payload: common.Hex2Bytes("bb")},
+	}
+	_, err = s.inspectRepository.Update(*input)
+	s.NoError(err)
+
+	input2, err := s.inspectRepository.FindByIndex(input.Index)
+	s.NoError(err)
+	s.Equal(CompletionStatusException, input2.Status)
+	s.Equal(7, input2.ProcessedInputCount)
+	s.Equal("ff", common.Bytes2Hex(input2.Exception))
+	s.Len(input2.Reports, 2)
+	s.Equal("aa", common.Bytes2Hex(input2.Reports[0].Payload))
+	s.Equal(1, input2.Reports[1].Index)
+	s.Equal("bb", common.Bytes2Hex(input2.Reports[1].Payload))
+}
+
+func (s *InspectRepositorySuite) TestFindByStatusReturnsOldest() {
+	for i := 0; i < 3; i++ {
+		_, err := s.inspectRepository.Create(InspectInput{
+			Status: CompletionStatusUnprocessed,
+		})
+		s.NoError(err)
+	}
+	input, err := s.inspectRepository.FindByStatus(CompletionStatusUnprocessed)
+	s.NoError(err)
+	s.Equal(1, input.Index)
+
+	input.Status = CompletionStatusAccepted
+	_, err = s.inspectRepository.Update(*input)
+	s.NoError(err)
+
+	input, err = s.inspectRepository.FindByStatus(CompletionStatusUnprocessed)
+	s.NoError(err)
+	s.Equal(2, input.Index)
+}
+
+func (s *InspectRepositorySuite) TestDeleteFinishedBefore() {
+	finished, err := s.inspectRepository.Create(InspectInput{
+		Status: CompletionStatusUnprocessed,
+	})
+	s.NoError(err)
+	finished.Status = CompletionStatusAccepted
+	finished.Reports = []Report{{Index: 0, Payload: common.Hex2Bytes("aa")}}
+	_, err = s.inspectRepository.Update(*finished)
+	s.NoError(err)
+	pending, err := s.inspectRepository.Create(InspectInput{
+		Status: CompletionStatusUnprocessed,
+	})
+	s.NoError(err)
+
+	deleted, err := s.inspectRepository.DeleteFinishedBefore(time.Now().Add(-time.Hour))
+	s.NoError(err)
+	s.Equal(int64(0), deleted)
+
+	deleted, err = s.inspectRepository.DeleteFinishedBefore(time.Now().Add(time.Second))
+	s.NoError(err)
+	s.Equal(int64(1), deleted)
+
+	input, err := s.inspectRepository.FindByIndex(finished.Index)
+	s.NoError(err)
+	s.Nil(input)
+	input, err = s.inspectRepository.FindByIndex(pending.Index)
+	s.NoError(err)
+	s.NotNil(input)
+}
+
+func (s *InspectRepositorySuite) TestPrunedIndexIsNotReused() {
+	var last int
+	for i := 0; i < 2; i++ {
+		input, err := s.inspectRepository.Create(InspectInput{
+			Status: CompletionStatusUnprocessed,
+		})
+		s.Require().NoError(err)
+		input.Status = CompletionStatusAccepted
+		_, err = s.inspectRepository.Update(*input)
+		s.Require().NoError(err)
+		last = input.Index
+	}
+	// prune the newest inspects, so the highest id in the table is gone
+	deleted, err := s.inspectRepository.DeleteFinishedBefore(time.Now().Add(time.Second))
+	s.Require().NoError(err)
+	s.Require().Equal(int64(2), deleted)
+
+	input, err := s.inspectRepository.Create(InspectInput{
+		Status: CompletionStatusUnprocessed,
+	})
+	s.Require().NoError(err)
+	s.Greater(input.Index, last)
+}
@@ -5,8 +5,11 @@
 package model
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math/big"
 	"sync"
 	"time"
 
@@ -15,15 +18,36 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// Default time that finished inspect inputs are kept in the database.
+const DefaultInspectRetention = 10 * time.Minute
+
+// Default time after which an epoch is closed.
+const DefaultEpochDuration = time.Minute
+
+// Error returned when rewinding the inputs of a claimed epoch.
+var ErrClaimedEpoch = errors.New("cannot rewind claimed epoch")
+
 // Nonodo model shared among the internal workers.
 // The model store inputs as pointers because these pointers are shared with the rollup state.
 type AppModel struct {
-	Mutex            sync.Mutex
-	Inspects         []*InspectInput
-	State            rollupsState
-	Decoder          Decoder
-	ReportRepository *ReportRepository
-	InputRepository  *InputRepository
+	Mutex             sync.Mutex
+	State             rollupsState
+	Decoder           Decoder
+	ReportRepository  *ReportRepository
+	InputRepository   *InputRepository
+	InspectRepository *InspectRepository
+	ProofRepository   *ProofRepository
+	EpochRepository   *EpochRepository
+	// Last blocks processed by the workers that read the chain.
+	CheckpointRepository *CheckpointRepository
+	// Finished inspect inputs older than this are pruned; zero keeps them forever.
+	InspectRetention time.Duration
+	// The epoch is closed when an input arrives this many blocks after its first block; zero disables it.
+	EpochBlockCount uint64
+	// The epoch is closed this long after it was opened; zero disables it.
+	EpochDuration time.Duration
+	notifier      inputNotifier
+	completions   inputNotifier
 }
 
 func NewAppModel(decoder Decoder, db *sqlx.DB) *AppModel {
@@ -38,11 +62,37 @@ func NewAppModel(decoder Decoder, db *sqlx.DB) *AppModel {
 	if err != nil {
 		panic(err)
 	}
+	inspectRepository := InspectRepository{Db: db}
+	err = inspectRepository.CreateTables()
+	if err != nil {
+		panic(err)
+	}
+	proofRepository := ProofRepository{Db: db}
+	err = proofRepository.CreateTables()
+	if err != nil {
+		panic(err)
+	}
+	epochRepository := EpochRepository{Db: db}
+	err = epochRepository.CreateTables()
+	if err != nil {
+		panic(err)
+	}
+	checkpointRepository := CheckpointRepository{Db: db}
+	err = checkpointRepository.CreateTables()
+	if err != nil {
+		panic(err)
+	}
 	return &AppModel{
-		State:            &RollupsStateIdle{},
-		Decoder:          decoder,
-		ReportRepository: &reportRepository,
-		InputRepository:  &inputRepository,
+		State:                &RollupsStateIdle{},
+		Decoder:              decoder,
+		ReportRepository:     &reportRepository,
+		InputRepository:      &inputRepository,
+		InspectRepository:    &inspectRepository,
+		ProofRepository:      &proofRepository,
+		EpochRepository:      &epochRepository,
+		CheckpointRepository: &checkpointRepository,
+		InspectRetention:     DefaultInspectRetention,
+		EpochDuration:        DefaultEpochDuration,
 	}
 }
 
@@ -50,6 +100,24 @@ func (m *AppModel) GetInputRepository() *InputRepository {
 	return m.InputRepository
 }
 
+// Return a channel that is closed when the next advance or inspect input is added.
+// Callers should get the channel before looking for inputs to avoid missing a notification.
+func (m *AppModel) NewInputs() <-chan struct{} {
+	return m.notifier.wait()
+}
+
+// Return a channel that is closed when the current advance or inspect input is finished.
+// Callers should get the channel before checking the input status to avoid missing a notification.
+func (m *AppModel) CompletedInputs() <-chan struct{} {
+	return m.completions.wait()
+}
+
+// Wake up the goroutines waiting for the current input to finish.
+// Sequencers that finish the current input on their own must call it afterwards.
+func (m *AppModel) NotifyCompletedInputs() {
+	m.completions.notify()
+}
+
 //
 // Methods for Inputter
 //
@@ -61,23 +129,364 @@ func (m *AppModel) AddAdvanceInput(
 	blockNumber uint64,
 	timestamp time.Time,
 	index int,
-) {
-	m.Mutex.Lock()
-	defer m.Mutex.Unlock()
-	input := AdvanceInput{
+) error {
+	return m.AddEvmAdvanceInput(AdvanceInput{
 		Index:          index,
-		Status:         CompletionStatusUnprocessed,
 		MsgSender:      sender,
 		Payload:        payload,
-		BlockTimestamp: timestamp,
 		BlockNumber:    blockNumber,
-	}
-	_, err := m.InputRepository.Create(input)
+		BlockTimestamp: timestamp,
+	})
+}
+
+// Add an advance input to the model with the metadata of its EvmAdvance and the location of
+// the log that added it to the input box.
+// The status and the epoch of the input are set by the model.
+func (m *AppModel) AddEvmAdvanceInput(input AdvanceInput) error {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+	// the input, its epoch and its deposit are either all stored or none, so a failed
+	// input is read again instead of being skipped as a duplicate
+	skipped := false
+	err := inTransaction(m.InputRepository.Db, func(ctx context.Context) error {
+		exist, err := m.InputRepository.FindByIndex(ctx, input.Index)
+		if err != nil {
+			return fmt.Errorf("find advance input: %w", err)
+		}
+		if exist != nil {
+			skipped = true
+			return nil
+		}
+		epoch, err := m.epochForInput(ctx, input.BlockNumber, time.Now())
+		if err != nil {
+			return fmt.Errorf("epoch of advance input: %w", err)
+		}
+		input.Status = CompletionStatusUnprocessed
+		input.EpochIndex = epoch.Index
+		_, err = m.InputRepository.Create(ctx, input)
+		if err != nil {
+			return fmt.Errorf("create advance input: %w", err)
+		}
+		if m.Decoder != nil {
+			err = m.Decoder.HandleInput(ctx, input)
+			if err != nil {
+				return fmt.Errorf("handle advance input: %w", err)
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		panic(err)
+		return err
 	}
+	if skipped {
+		slog.Debug("rollups-server: skipped existing advance input", "index", input.Index)
+		return nil
+	}
+	m.notifier.notify()
 	slog.Info("rollups-server: added advance input", "index", input.Index, "sender", input.MsgSender,
 		"payload", hexutil.Encode(input.Payload))
+	return nil
+}
+
+// Get the last block fully processed by the reader with the given name.
+// Return nil if the reader has not processed any block yet.
+func (m *AppModel) GetCheckpoint(name string) (*Block, error) {
+	block, err := m.CheckpointRepository.Find(context.Background(), name)
+	if err != nil {
+		return nil, fmt.Errorf("get checkpoint: %w", err)
+	}
+	return block, nil
+}
+
+// Save the last block fully processed by the reader with the given name.
+func (m *AppModel) SetCheckpoint(name string, block Block) error {
+	err := m.CheckpointRepository.Save(context.Background(), name, block)
+	if err != nil {
+		return fmt.Errorf("set checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Get the blocks that have advance inputs, from the latest to the earliest.
+func (m *AppModel) GetInputBlocks() ([]Block, error) {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	blocks, err := m.InputRepository.FindBlocks(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("get input blocks: %w", err)
+	}
+	return blocks, nil
+}
+
+// Remove the advance inputs added after the block, along with their outputs, and move the
+// checkpoints back to the block, so the inputs are read again from the new chain.
+// The epochs of the removed inputs are removed too, except for the epoch that also has
+// inputs before the block, which is opened again.
+// Return the number of removed inputs.
+func (m *AppModel) RewindInputs(blockNumber uint64) (int, error) {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	removed := 0
+	processed := false
+	err := inTransaction(m.InputRepository.Db, func(ctx context.Context) error {
+		first, err := m.InputRepository.FindFirstAfterBlock(ctx, blockNumber)
+		if err != nil {
+			return err
+		}
+		if first != nil {
+			from := uint64(first.Index)
+			processed = first.Status != CompletionStatusUnprocessed
+			err = m.rewindEpochs(ctx, first)
+			if err != nil {
+				return err
+			}
+			removed, err = m.InputRepository.DeleteFrom(ctx, from)
+			if err != nil {
+				return err
+			}
+			err = m.ReportRepository.DeleteFrom(ctx, from)
+			if err != nil {
+				return err
+			}
+			err = m.ProofRepository.DeleteFrom(ctx, from)
+			if err != nil {
+				return err
+			}
+			if m.Decoder != nil {
+				err = m.Decoder.RewindOutputs(ctx, from)
+				if err != nil {
+					return fmt.Errorf("rewind outputs: %w", err)
+				}
+			}
+			if state, ok := m.State.(*rollupsStateAdvance); ok && state.input.Index >= first.Index {
+				m.State = NewRollupsStateIdle()
+			}
+		}
+		return m.CheckpointRepository.Rewind(ctx, blockNumber)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("rewind inputs: %w", err)
+	}
+	slog.Warn("rollups-server: rewound inputs", "block", blockNumber, "removed", removed)
+	if processed {
+		slog.Warn("rollups-server: the application processed removed inputs; restart it to discard them")
+	}
+	return removed, nil
+}
+
+// Remove the epochs of the inputs from the first one on.
+// Return an error if one of them was claimed.
+func (m *AppModel) rewindEpochs(ctx context.Context, first *AdvanceInput) error {
+	epochs, err := m.EpochRepository.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, epoch := range epochs {
+		if epoch.Index >= first.EpochIndex && epoch.Status == EpochStatusClaimed {
+			return fmt.Errorf("%w: %v", ErrClaimedEpoch, epoch.Index)
+		}
+	}
+	epoch, err := m.EpochRepository.FindByIndex(ctx, first.EpochIndex)
+	if err != nil {
+		return err
+	}
+	firstInEpoch, _, ok, err := m.InputRepository.FindEpochRange(ctx, first.EpochIndex)
+	if err != nil {
+		return err
+	}
+	if epoch == nil || !ok || firstInEpoch >= uint64(first.Index) {
+		return m.EpochRepository.DeleteFrom(ctx, first.EpochIndex)
+	}
+	err = m.EpochRepository.DeleteFrom(ctx, first.EpochIndex+1)
+	if err != nil {
+		return err
+	}
+	// the epoch keeps its first inputs, so it is opened again
+	err = m.ProofRepository.ClearEpochFrom(ctx, firstInEpoch)
+	if err != nil {
+		return err
+	}
+	epoch.Status = EpochStatusOpen
+	_, err = m.EpochRepository.Update(ctx, *epoch)
+	return err
+}
+
+// Get the open epoch for a new input, closing the current one when it is due.
+// It runs in the transaction of the context.
+func (m *AppModel) epochForInput(ctx context.Context, blockNumber uint64, now time.Time) (*Epoch, error) {
+	epoch, err := m.EpochRepository.FindOpen(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if epoch != nil {
+		due := m.EpochBlockCount > 0 && blockNumber >= epoch.FirstBlock+m.EpochBlockCount
+		due = due || m.epochDurationElapsed(epoch, now)
+		if !due {
+			return epoch, nil
+		}
+		closed, err := m.closeEpochInTransaction(ctx, epoch, now)
+		if err != nil {
+			return nil, fmt.Errorf("close epoch: %w", err)
+		}
+		if closed == nil {
+			// the epoch has no inputs, so it is kept open
+			return epoch, nil
+		}
+	}
+	index, err := m.EpochRepository.NextIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	epoch, err = m.EpochRepository.Create(ctx, Epoch{
+		Index:      index,
+		Status:     EpochStatusOpen,
+		FirstBlock: blockNumber,
+		OpenedAt:   now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("rollups-server: opened epoch", "index", epoch.Index)
+	return epoch, nil
+}
+
+//
+// Methods for Epochs
+//
+
+// Close the open epoch now.
+// Return the closed epoch or nil if there is no open epoch with inputs.
+func (m *AppModel) CloseEpoch() (*Epoch, error) {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	epoch, err := m.EpochRepository.FindOpen(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("find open epoch: %w", err)
+	}
+	if epoch == nil {
+		return nil, nil
+	}
+	return m.closeEpoch(epoch, time.Now())
+}
+
+// Close the open epoch if its duration elapsed.
+// Return the closed epoch or nil if no epoch was closed.
+func (m *AppModel) CloseEpochIfDue(now time.Time) (*Epoch, error) {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	epoch, err := m.EpochRepository.FindOpen(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("find open epoch: %w", err)
+	}
+	if epoch == nil || !m.epochDurationElapsed(epoch, now) {
+		return nil, nil
+	}
+	return m.closeEpoch(epoch, now)
+}
+
+// Get the epoch; return nil if it does not exist.
+func (m *AppModel) GetEpoch(index uint64) (*Epoch, error) {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	epoch, err := m.EpochRepository.FindByIndex(context.Background(), index)
+	if err != nil {
+		return nil, fmt.Errorf("find epoch: %w", err)
+	}
+	return epoch, nil
+}
+
+// Get all the epochs in index order.
+func (m *AppModel) GetEpochs() ([]Epoch, error) {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	epochs, err := m.EpochRepository.FindAll(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("find epochs: %w", err)
+	}
+	return epochs, nil
+}
+
+// Get the proved epochs that were not claimed yet in index order.
+func (m *AppModel) GetEpochsToClaim() ([]Epoch, error) {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	epochs, err := m.EpochRepository.FindByStatus(context.Background(), EpochStatusProved)
+	if err != nil {
+		return nil, fmt.Errorf("find proved epochs: %w", err)
+	}
+	return epochs, nil
+}
+
+// Mark the proved epoch as claimed by the transaction.
+func (m *AppModel) SetEpochClaimed(index uint64, txHash common.Hash) error {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	ctx := context.Background()
+	epoch, err := m.EpochRepository.FindByIndex(ctx, index)
+	if err != nil {
+		return fmt.Errorf("find epoch: %w", err)
+	}
+	if epoch == nil || epoch.Status != EpochStatusProved {
+		return fmt.Errorf("epoch %v is not proved", index)
+	}
+	epoch.Status = EpochStatusClaimed
+	epoch.ClaimTransactionHash = txHash
+	_, err = m.EpochRepository.Update(ctx, *epoch)
+	return err
+}
+
+func (m *AppModel) epochDurationElapsed(epoch *Epoch, now time.Time) bool {
+	return m.EpochDuration > 0 && !now.Before(epoch.OpenedAt.Add(m.EpochDuration))
+}
+
+// Close the epoch and prove it right away if its inputs were already processed.
+// Epochs without inputs are kept open, since there is nothing to prove.
+func (m *AppModel) closeEpoch(epoch *Epoch, now time.Time) (*Epoch, error) {
+	var closed *Epoch
+	err := inTransaction(m.EpochRepository.Db, func(ctx context.Context) error {
+		var err error
+		closed, err = m.closeEpochInTransaction(ctx, epoch, now)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("close epoch: %w", err)
+	}
+	return closed, nil
+}
+
+// Close the epoch in the transaction of the context.
+// Return the closed epoch or nil if the epoch has no inputs.
+func (m *AppModel) closeEpochInTransaction(ctx context.Context, epoch *Epoch, now time.Time) (*Epoch, error) {
+	first, last, ok, err := m.InputRepository.FindEpochRange(ctx, epoch.Index)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	epoch.Status = EpochStatusClosed
+	epoch.FirstInputIndex = first
+	epoch.LastInputIndex = last
+	epoch.ClosedAt = now
+	_, err = m.EpochRepository.Update(ctx, *epoch)
+	if err != nil {
+		return nil, err
+	}
+	_, err = proveEpoch(ctx, m.EpochRepository, m.InputRepository, m.ProofRepository, epoch.Index)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("rollups-server: closed epoch", "index", epoch.Index,
+		"firstInput", epoch.FirstInputIndex, "lastInput", epoch.LastInputIndex)
+	return m.EpochRepository.FindByIndex(ctx, epoch.Index)
 }
 
 //
@@ -86,32 +495,53 @@ func (m *AppModel) AddAdvanceInput(
 
 // Add an inspect input to the model.
 // Return the inspect input index that should be used for polling.
-func (m *AppModel) AddInspectInput(payload []byte) int {
+func (m *AppModel) AddInspectInput(payload []byte) (int, error) {
 	m.Mutex.Lock()
 	defer m.Mutex.Unlock()
 
-	index := len(m.Inspects)
-	input := InspectInput{
-		Index:   index,
+	m.pruneInspectInputs()
+	input, err := m.InspectRepository.Create(InspectInput{
 		Status:  CompletionStatusUnprocessed,
 		Payload: payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("create inspect input: %w", err)
 	}
-	m.Inspects = append(m.Inspects, &input)
+	m.notifier.notify()
 	slog.Info("nonodo: added inspect input", "index", input.Index,
 		"payload", hexutil.Encode(input.Payload))
 
-	return index
+	return input.Index, nil
 }
 
 // Get the inspect input from the model.
-func (m *AppModel) GetInspectInput(index int) InspectInput {
+func (m *AppModel) GetInspectInput(index int) (InspectInput, error) {
 	m.Mutex.Lock()
 	defer m.Mutex.Unlock()
 
-	if index >= len(m.Inspects) {
-		panic(fmt.Sprintf("invalid inspect input index: %v", index))
+	input, err := m.InspectRepository.FindByIndex(index)
+	if err != nil {
+		return InspectInput{}, fmt.Errorf("find inspect input: %w", err)
+	}
+	if input == nil {
+		return InspectInput{}, fmt.Errorf("invalid inspect input index: %v", index)
+	}
+	return *input, nil
+}
+
+// Delete the finished inspect inputs older than the retention period.
+func (m *AppModel) pruneInspectInputs() {
+	if m.InspectRetention <= 0 {
+		return
+	}
+	deleted, err := m.InspectRepository.DeleteFinishedBefore(time.Now().Add(-m.InspectRetention))
+	if err != nil {
+		slog.Warn("rollups-server: failed to prune inspect inputs", "error", err)
+		return
+	}
+	if deleted > 0 {
+		slog.Debug("rollups-server: pruned inspect inputs", "count", deleted)
 	}
-	return *m.Inspects[index]
 }
 
 //
@@ -120,9 +550,10 @@ func (m *AppModel) GetInspectInput(index int) InspectInput {
 
 // Finish the current input and get the next one.
 // If there is no input to be processed return nil.
+// If finishing the current input fails, the state is kept so the finish can be retried.
 //
 // Note: use in v2 the sequencer instead.
-func (m *AppModel) FinishAndGetNext(accepted bool) Input {
+func (m *AppModel) FinishAndGetNext(accepted bool) (Input, error) {
 	m.Mutex.Lock()
 	defer m.Mutex.Unlock()
 
@@ -133,20 +564,27 @@ func (m *AppModel) FinishAndGetNext(accepted bool) Input {
 	} else {
 		status = CompletionStatusRejected
 	}
-	m.State.Finish(status)
+	err := m.State.Finish(status)
+	if err != nil {
+		return nil, fmt.Errorf("finish input: %w", err)
+	}
+	m.State = NewRollupsStateIdle()
+	m.completions.notify()
 
 	// try to get first unprocessed inspect
-	for _, input := range m.Inspects {
-		if input.Status == CompletionStatusUnprocessed {
-			m.State = NewRollupsStateInspect(input, m.GetProcessedInputCount)
-			return *input
-		}
+	inspect, err := m.InspectRepository.FindByStatus(CompletionStatusUnprocessed)
+	if err != nil {
+		return nil, fmt.Errorf("find next inspect: %w", err)
+	}
+	if inspect != nil {
+		m.State = NewRollupsStateInspect(inspect, m.InspectRepository, m.GetProcessedInputCount)
+		return *inspect, nil
 	}
 
 	// try to get first unprocessed advance
-	input, err := m.InputRepository.FindByStatus(CompletionStatusUnprocessed)
+	input, err := m.InputRepository.FindByStatus(context.Background(), CompletionStatusUnprocessed)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("find next advance: %w", err)
 	}
 	if input != nil {
 		m.State = NewRollupsStateAdvance(
@@ -154,27 +592,28 @@ func (m *AppModel) FinishAndGetNext(accepted bool) Input {
 			m.Decoder,
 			m.ReportRepository,
 			m.InputRepository,
+			m.ProofRepository,
+			m.EpochRepository,
 		)
-		return *input
+		return *input, nil
 	}
 
-	// if no input was found, set state to idle
-	m.State = NewRollupsStateIdle()
-	return nil
+	// if no input was found, the state stays idle
+	return nil, nil
 }
 
 // Add a voucher to the model.
-// Return the voucher index within the input.
+// Return the output index of the voucher within the input.
 // Return an error if the state isn't advance.
-func (m *AppModel) AddVoucher(destination common.Address, payload []byte) (int, error) {
+func (m *AppModel) AddVoucher(destination common.Address, value *big.Int, payload []byte) (int, error) {
 	m.Mutex.Lock()
 	defer m.Mutex.Unlock()
 
-	return m.State.AddVoucher(destination, payload)
+	return m.State.AddVoucher(destination, value, payload)
 }
 
 // Add a notice to the model.
-// Return the notice index within the input.
+// Return the output index of the notice within the input.
 // Return an error if the state isn't advance.
 func (m *AppModel) AddNotice(payload []byte) (int, error) {
 	m.Mutex.Lock()
@@ -205,24 +644,65 @@ func (m *AppModel) RegisterException(payload []byte) error {
 
 	// set state to idle
 	m.State = NewRollupsStateIdle()
+	m.completions.notify()
 	return nil
 }
 
+// Get the proof of the output of an input.
+// Return nil if the output does not exist or if its epoch was not proved yet.
+func (m *AppModel) GetProof(inputIndex uint64, outputIndex uint64) (*Proof, error) {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	proof, err := m.ProofRepository.FindByInputAndOutputIndex(context.Background(), inputIndex, outputIndex)
+	if err != nil {
+		return nil, fmt.Errorf("find proof: %w", err)
+	}
+	return proof, nil
+}
+
 //
 // Auxiliary Methods
 //
 
-func (m *AppModel) GetProcessedInputCount() int {
+// Broadcast the arrival or the completion of inputs to the goroutines waiting for them.
+// The zero value is ready to use.
+type inputNotifier struct {
+	mutex   sync.Mutex
+	channel chan struct{}
+}
+
+// Get the channel that will be closed on the next notification.
+func (n *inputNotifier) wait() <-chan struct{} {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.channel == nil {
+		n.channel = make(chan struct{})
+	}
+	return n.channel
+}
+
+// Wake up all the waiting goroutines.
+func (n *inputNotifier) notify() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.channel != nil {
+		close(n.channel)
+		n.channel = nil
+	}
+}
+
+func (m *AppModel) GetProcessedInputCount() (int, error) {
 	filter := []*ConvenienceFilter{}
-	field := "Status"
+	field := STATUS
 	value := fmt.Sprintf("%d", CompletionStatusUnprocessed)
 	filter = append(filter, &ConvenienceFilter{
 		Field: &field,
 		Ne:    &value,
 	})
-	total, err := m.InputRepository.Count(filter)
+	total, err := m.InputRepository.Count(context.Background(), filter)
 	if err != nil {
-		panic(err)
+		return 0, err
 	}
-	return int(total)
+	return int(total), nil
 }
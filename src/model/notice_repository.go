@@ -3,11 +3,11 @@ package model
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
-	"fmt"
 	"log/slog"
-	"strings"
 
+	"github.com/calindra/rollups-server/src/migrations"
 	"github.com/calindra/rollups-server/src/util"
 	"github.com/jmoiron/sqlx"
 )
@@ -16,15 +16,17 @@ type NoticeRepository struct {
 	Db sqlx.DB
 }
 
+type noticeRow struct {
+	Payload       string  `db:"payload"`
+	InputIndex    uint64  `db:"input_index"`
+	OutputIndex   uint64  `db:"output_index"`
+	DecodedSchema *string `db:"decoded_schema"`
+	DecodedFields *string `db:"decoded_fields"`
+}
+
+// Create the tables by applying the pending schema migrations.
 func (c *NoticeRepository) CreateTables() error {
-	schema := `CREATE TABLE IF NOT EXISTS notices (
-		payload 		text,
-		input_index		integer,
-		output_index	integer,
-		PRIMARY KEY (input_index, output_index));`
-
-	// execute a query on the server
-	_, err := c.Db.Exec(schema)
+	_, err := migrations.Migrate(context.Background(), &c.Db, false)
 	return err
 }
 
@@ -34,12 +36,16 @@ func (c *NoticeRepository) Create(
 	insertSql := `INSERT INTO notices (
 		payload,
 		input_index,
-		output_index) VALUES ($1, $2, $3)`
-	_, err := c.Db.ExecContext(ctx,
+		output_index,
+		decoded_schema,
+		decoded_fields) VALUES ($1, $2, $3, $4, $5)`
+	_, err := executor(ctx, &c.Db).ExecContext(ctx,
 		insertSql,
 		data.Payload,
 		data.InputIndex,
 		data.OutputIndex,
+		encodeText(data.DecodedSchema),
+		encodeText(string(data.DecodedFields)),
 	)
 	if err != nil {
 		return nil, err
@@ -51,12 +57,16 @@ func (c *NoticeRepository) Update(
 	ctx context.Context, data *ConvenienceNotice,
 ) (*ConvenienceNotice, error) {
 	sqlUpdate := `UPDATE notices SET 
-		payload = $1
-		WHERE input_index = $2 and output_index = $3`
-	_, err := c.Db.ExecContext(
+		payload = $1,
+		decoded_schema = $2,
+		decoded_fields = $3
+		WHERE input_index = $4 and output_index = $5`
+	_, err := executor(ctx, &c.Db).ExecContext(
 		ctx,
 		sqlUpdate,
 		data.Payload,
+		encodeText(data.DecodedSchema),
+		encodeText(string(data.DecodedFields)),
 		data.InputIndex,
 		data.OutputIndex,
 	)
@@ -66,6 +76,28 @@ func (c *NoticeRepository) Update(
 	return data, nil
 }
 
+// Store the fields of the notice decoded with the ABI registry.
+func (c *NoticeRepository) SetDecodedFields(
+	ctx context.Context, inputIndex uint64, outputIndex uint64,
+	schema string, fields json.RawMessage,
+) error {
+	query := `UPDATE notices SET
+		decoded_schema = $1,
+		decoded_fields = $2
+		WHERE input_index = $3 and output_index = $4`
+	_, err := executor(ctx, &c.Db).ExecContext(
+		ctx, query, encodeText(schema), encodeText(string(fields)), inputIndex, outputIndex,
+	)
+	return err
+}
+
+// Delete the notices of the inputs from the given index on.
+func (c *NoticeRepository) DeleteFrom(ctx context.Context, inputIndex uint64) error {
+	_, err := executor(ctx, &c.Db).ExecContext(ctx,
+		`DELETE FROM notices WHERE input_index >= $1`, inputIndex)
+	return err
+}
+
 func (c *NoticeRepository) Count(
 	ctx context.Context,
 	filter []*ConvenienceFilter,
@@ -77,13 +109,8 @@ func (c *NoticeRepository) Count(
 	}
 	query += where
 	slog.Debug("Query", "query", query, "args", args)
-	stmt, err := c.Db.Preparex(query)
-	if err != nil {
-		return 0, err
-	}
-	defer stmt.Close()
 	var count uint64
-	err = stmt.GetContext(ctx, &count, args...)
+	err = sqlx.GetContext(ctx, executor(ctx, &c.Db), &count, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -97,8 +124,9 @@ func (c *NoticeRepository) FindAllNotices(
 	after *string,
 	before *string,
 	filter []*ConvenienceFilter,
+	options ...util.PageOption,
 ) (*util.PageResult[ConvenienceNotice], error) {
-	total, err := c.Count(ctx, filter)
+	page, err := util.ComputePage(first, last, after, before, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -107,84 +135,77 @@ func (c *NoticeRepository) FindAllNotices(
 	if err != nil {
 		return nil, err
 	}
-	query += where
-	query += `ORDER BY input_index ASC, output_index ASC `
-	offset, limit, err := util.ComputePage(first, last, after, before, int(total))
+	pageQuery, args := compilePage(page, where, args, argsCount, "input_index", "output_index")
+	query += pageQuery
+
+	slog.Debug("Query", "query", query, "args", args)
+	var rows []noticeRow
+	err = sqlx.SelectContext(ctx, executor(ctx, &c.Db), &rows, query, args...)
 	if err != nil {
 		return nil, err
 	}
-	query += fmt.Sprintf("LIMIT $%d ", argsCount)
-	args = append(args, limit)
-	argsCount = argsCount + 1
-	query += fmt.Sprintf("OFFSET $%d ", argsCount)
-	args = append(args, offset)
-
-	slog.Debug("Query", "query", query, "args", args, "total", total)
-	stmt, err := c.Db.Preparex(query)
-	if err != nil {
-		return nil, err
+	notices := make([]ConvenienceNotice, len(rows))
+	for i, row := range rows {
+		notices[i] = convertToConvenienceNotice(row)
 	}
-	defer stmt.Close()
-	var notices []ConvenienceNotice
-	err = stmt.SelectContext(ctx, &notices, args...)
+	result := util.NewPageResult(page, notices, noticeCursor)
+	err = result.SetTotal(page, func() (uint64, error) {
+		return c.Count(ctx, filter)
+	})
 	if err != nil {
 		return nil, err
 	}
-	pageResult := &util.PageResult[ConvenienceNotice]{
-		Rows:   notices,
-		Total:  total,
-		Offset: uint64(offset),
-	}
-	return pageResult, nil
+	return result, nil
+}
+
+func noticeCursor(notice ConvenienceNotice) util.Cursor {
+	return util.Cursor{InputIndex: notice.InputIndex, OutputIndex: notice.OutputIndex}
 }
 
 func (c *NoticeRepository) FindByInputAndOutputIndex(
 	ctx context.Context, inputIndex uint64, outputIndex uint64,
 ) (*ConvenienceNotice, error) {
 	query := `SELECT * FROM notices WHERE input_index = $1 and output_index = $2 LIMIT 1`
-	stmt, err := c.Db.Preparex(query)
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-	var p ConvenienceNotice
-	err = stmt.GetContext(ctx, &p, inputIndex, outputIndex)
+	var row noticeRow
+	err := sqlx.GetContext(ctx, executor(ctx, &c.Db), &row, query, inputIndex, outputIndex)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
+	p := convertToConvenienceNotice(row)
 	return &p, nil
 }
 
+func convertToConvenienceNotice(row noticeRow) ConvenienceNotice {
+	notice := ConvenienceNotice{
+		Payload:     row.Payload,
+		InputIndex:  row.InputIndex,
+		OutputIndex: row.OutputIndex,
+	}
+	if row.DecodedSchema != nil {
+		notice.DecodedSchema = *row.DecodedSchema
+	}
+	if row.DecodedFields != nil {
+		notice.DecodedFields = json.RawMessage(*row.DecodedFields)
+	}
+	return notice
+}
+
+// Filterable columns of the notices table.
+var noticeColumns = filterColumns{
+	INPUT_INDEX:  integerColumn("input_index"),
+	OUTPUT_INDEX: integerColumn("output_index"),
+}
+
 func transformToNoticeQuery(
 	filter []*ConvenienceFilter,
 ) (string, []interface{}, int, error) {
-	query := ""
-	if len(filter) > 0 {
-		query += WHERE
-	}
-	args := []interface{}{}
-	where := []string{}
-	count := 1
-	for _, filter := range filter {
-		if *filter.Field == INPUT_INDEX {
-			if filter.Eq != nil {
-				where = append(
-					where,
-					fmt.Sprintf("input_index = $%d ", count),
-				)
-				args = append(args, *filter.Eq)
-				count += 1
-			} else {
-				return "", nil, 0, fmt.Errorf("operation not implemented")
-			}
-		} else {
-			return "", nil, 0, fmt.Errorf("unexpected field %s", *filter.Field)
-		}
+	query, args, count, err := compileFilter(filter, noticeColumns)
+	if err != nil {
+		return "", nil, 0, err
 	}
-	query += strings.Join(where, " and ")
 	slog.Debug("Query", "query", query, "args", args)
 	return query, args, count, nil
 }
@@ -6,7 +6,6 @@ import (
 	"testing"
 
 	"github.com/calindra/rollups-server/src/util"
-	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/suite"
 )
@@ -14,20 +13,30 @@ import (
 type NoticeRepositorySuite struct {
 	suite.Suite
 	repository *NoticeRepository
+	driver     string
+	database   *testDatabase
 }
 
 func (s *NoticeRepositorySuite) SetupTest() {
 	util.ConfigureLog(slog.LevelDebug)
-	db := sqlx.MustConnect("sqlite3", ":memory:")
+	var err error
+	s.database, err = openTestDatabase(s.driver)
+	s.Require().NoError(err)
 	s.repository = &NoticeRepository{
-		Db: *db,
+		Db: *s.database.Db,
 	}
-	err := s.repository.CreateTables()
+	err = s.repository.CreateTables()
 	s.NoError(err)
 }
 
+func (s *NoticeRepositorySuite) TearDownTest() {
+	s.database.Close()
+}
+
 func TestNoticeRepositorySuite(t *testing.T) {
-	suite.Run(t, new(NoticeRepositorySuite))
+	runWithDrivers(t, func(driver string) *NoticeRepositorySuite {
+		return &NoticeRepositorySuite{driver: driver}
+	})
 }
 
 func (s *NoticeRepositorySuite) TestCreateNotice() {
@@ -112,7 +121,7 @@ func (s *NoticeRepositorySuite) TestNoticePagination() {
 	s.Equal(0, int(notices.Rows[0].InputIndex))
 	s.Equal(9, int(notices.Rows[len(notices.Rows)-1].InputIndex))
 
-	after := util.EncodeCursor(10)
+	after := util.EncodeCursor(util.Cursor{InputIndex: 10})
 	notices, err = s.repository.FindAllNotices(ctx, &first, nil, &after, nil, filters)
 	s.NoError(err)
 	s.Equal(10, len(notices.Rows))
@@ -126,10 +135,31 @@ func (s *NoticeRepositorySuite) TestNoticePagination() {
 	s.Equal(20, int(notices.Rows[0].InputIndex))
 	s.Equal(29, int(notices.Rows[len(notices.Rows)-1].InputIndex))
 
-	before := util.EncodeCursor(20)
+	before := util.EncodeCursor(util.Cursor{InputIndex: 20})
 	notices, err = s.repository.FindAllNotices(ctx, nil, &last, nil, &before, filters)
 	s.NoError(err)
 	s.Equal(10, len(notices.Rows))
 	s.Equal(10, int(notices.Rows[0].InputIndex))
 	s.Equal(19, int(notices.Rows[len(notices.Rows)-1].InputIndex))
 }
+
+func (s *NoticeRepositorySuite) TestSetDecodedFields() {
+	ctx := context.Background()
+	_, err := s.repository.Create(ctx, &ConvenienceNotice{
+		Payload:     "0x0011",
+		InputIndex:  1,
+		OutputIndex: 2,
+	})
+	s.Require().NoError(err)
+	notice, err := s.repository.FindByInputAndOutputIndex(ctx, 1, 2)
+	s.Require().NoError(err)
+	s.Empty(notice.DecodedSchema)
+	s.Nil(notice.DecodedFields)
+
+	err = s.repository.SetDecodedFields(ctx, 1, 2, "balance", []byte(`{"balance":"5"}`))
+	s.Require().NoError(err)
+	notice, err = s.repository.FindByInputAndOutputIndex(ctx, 1, 2)
+	s.Require().NoError(err)
+	s.Equal("balance", notice.DecodedSchema)
+	s.JSONEq(`{"balance":"5"}`, string(notice.DecodedFields))
+}
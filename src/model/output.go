@@ -0,0 +1,69 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/calindra/rollups-server/src/contracts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Encode the voucher the way the application contract expects it in executeOutput.
+func EncodeVoucherOutput(destination common.Address, value *big.Int, payload []byte) ([]byte, error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+	return packOutput("Voucher", destination, value, payload)
+}
+
+// Encode the notice the way the application contract expects it in validateOutput.
+func EncodeNoticeOutput(payload []byte) ([]byte, error) {
+	return packOutput("Notice", payload)
+}
+
+func packOutput(name string, args ...interface{}) ([]byte, error) {
+	abi, err := contracts.OutputsMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("outputs abi: %w", err)
+	}
+	output, err := abi.Pack(name, args...)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", name, err)
+	}
+	return output, nil
+}
+
+// Check whether the encoded output is a voucher.
+func IsVoucherOutput(output []byte) bool {
+	abi, err := contracts.OutputsMetaData.GetAbi()
+	if err != nil || len(output) < 4 {
+		return false
+	}
+	return bytes.Equal(output[:4], abi.Methods["Voucher"].ID)
+}
+
+// Convert the proof to the argument of executeOutput and validateOutput.
+func (p Proof) ValidityProof() contracts.OutputValidityProof {
+	return contracts.OutputValidityProof{
+		InputRange: contracts.InputRange{
+			FirstIndex: p.FirstInputIndex,
+			LastIndex:  p.LastInputIndex,
+		},
+		InputIndexWithinEpoch:            p.InputIndexWithinEpoch,
+		OutputIndexWithinInput:           p.OutputIndex,
+		OutputHashesRootHash:             p.OutputHashesRootHash,
+		OutputsEpochRootHash:             p.OutputsEpochRootHash,
+		MachineStateHash:                 p.MachineStateHash,
+		OutputHashInOutputHashesSiblings: hashesToBytes32(p.OutputHashInOutputHashesSiblings),
+		OutputHashesInEpochSiblings:      hashesToBytes32(p.OutputHashesInEpochSiblings),
+	}
+}
+
+func hashesToBytes32(hashes []common.Hash) [][32]byte {
+	converted := make([][32]byte, len(hashes))
+	for i, hash := range hashes {
+		converted[i] = hash
+	}
+	return converted
+}
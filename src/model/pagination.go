@@ -0,0 +1,55 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/calindra/rollups-server/src/util"
+)
+
+// Compile the keyset condition, the ordering and the limit of the page.
+// The where clause and its arguments come from compileFilter; the keys are the columns of
+// the sort key, in the same order as the cursor fields (input_index, then output_index).
+// The limit fetches one row beyond the page so util.NewPageResult can tell whether there are more rows.
+func compilePage(
+	page *util.Page,
+	where string,
+	args []interface{},
+	argsCount int,
+	keys ...string,
+) (string, []interface{}) {
+	query := where
+	if page.Cursor != nil {
+		operator := ">"
+		if !page.Forward {
+			operator = "<"
+		}
+		values := []uint64{page.Cursor.InputIndex, page.Cursor.OutputIndex}
+		placeholders := make([]string, len(keys))
+		for i := range keys {
+			placeholders[i] = fmt.Sprintf("$%d", argsCount)
+			args = append(args, values[i])
+			argsCount += 1
+		}
+		condition := fmt.Sprintf("(%s) %s (%s) ",
+			strings.Join(keys, ", "), operator, strings.Join(placeholders, ", "))
+		if query == "" {
+			query = WHERE + condition
+		} else {
+			query += "and " + condition
+		}
+	}
+
+	direction := "ASC"
+	if !page.Forward {
+		direction = "DESC"
+	}
+	order := make([]string, len(keys))
+	for i, key := range keys {
+		order[i] = key + " " + direction
+	}
+	query += "ORDER BY " + strings.Join(order, ", ") + " "
+	query += fmt.Sprintf("LIMIT $%d ", argsCount)
+	args = append(args, page.Limit+1)
+	return query, args
+}
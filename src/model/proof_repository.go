@@ -0,0 +1,209 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/calindra/rollups-server/src/merkle"
+	"github.com/calindra/rollups-server/src/migrations"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/jmoiron/sqlx"
+)
+
+type ProofRepository struct {
+	Db *sqlx.DB
+}
+
+type proofRow struct {
+	InputIndex                       uint64  `db:"input_index"`
+	OutputIndex                      uint64  `db:"output_index"`
+	Output                           string  `db:"output"`
+	OutputHash                       string  `db:"output_hash"`
+	OutputHashesRootHash             string  `db:"output_hashes_root_hash"`
+	OutputHashInOutputHashesSiblings string  `db:"output_hash_in_output_hashes_siblings"`
+	FirstInputIndex                  *uint64 `db:"first_input_index"`
+	LastInputIndex                   *uint64 `db:"last_input_index"`
+	InputIndexWithinEpoch            *uint64 `db:"input_index_within_epoch"`
+	OutputsEpochRootHash             *string `db:"outputs_epoch_root_hash"`
+	MachineStateHash                 *string `db:"machine_state_hash"`
+	OutputHashesInEpochSiblings      *string `db:"output_hashes_in_epoch_siblings"`
+}
+
+// Create the tables by applying the pending schema migrations.
+func (r *ProofRepository) CreateTables() error {
+	_, err := migrations.Migrate(context.Background(), r.Db, false)
+	return err
+}
+
+// Save the part of the proof that only depends on the input.
+// The epoch part is filled by ProveEpoch.
+func (r *ProofRepository) Create(ctx context.Context, proof Proof) error {
+	insertSql := `INSERT INTO proofs (
+		input_index,
+		output_index,
+		output,
+		output_hash,
+		output_hashes_root_hash,
+		output_hash_in_output_hashes_siblings) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := executor(ctx, r.Db).ExecContext(
+		ctx,
+		insertSql,
+		proof.InputIndex,
+		proof.OutputIndex,
+		hexutil.Encode(proof.Output),
+		proof.OutputHash.Hex(),
+		proof.OutputHashesRootHash.Hex(),
+		encodeHashes(proof.OutputHashInOutputHashesSiblings),
+	)
+	if err != nil {
+		return fmt.Errorf("create proof: %w", err)
+	}
+	return nil
+}
+
+// Complete the proofs of the outputs of the inputs in the epoch.
+// The roots are the output hashes roots of the inputs from firstInputIndex to lastInputIndex.
+// Return the root of the outputs of the epoch.
+func (r *ProofRepository) ProveEpoch(
+	ctx context.Context,
+	firstInputIndex uint64,
+	lastInputIndex uint64,
+	roots []common.Hash,
+) (common.Hash, error) {
+	if uint64(len(roots)) != lastInputIndex-firstInputIndex+1 {
+		return common.Hash{}, fmt.Errorf("prove epoch: expected %d roots, got %d",
+			lastInputIndex-firstInputIndex+1, len(roots))
+	}
+	tree, err := merkle.NewTree(roots, merkle.InputsHeight)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("prove epoch: %w", err)
+	}
+	// the machine state is not available without a Cartesi machine
+	machineStateHash := common.Hash{}
+	updateSql := `UPDATE proofs SET
+		first_input_index = $1,
+		last_input_index = $2,
+		input_index_within_epoch = $3,
+		outputs_epoch_root_hash = $4,
+		machine_state_hash = $5,
+		output_hashes_in_epoch_siblings = $6
+		WHERE input_index = $7`
+	for i := range roots {
+		_, err := executor(ctx, r.Db).ExecContext(
+			ctx,
+			updateSql,
+			firstInputIndex,
+			lastInputIndex,
+			i,
+			tree.Root().Hex(),
+			machineStateHash.Hex(),
+			encodeHashes(tree.Siblings(uint64(i))),
+			firstInputIndex+uint64(i),
+		)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("prove epoch: %w", err)
+		}
+	}
+	return tree.Root(), nil
+}
+
+// Delete the proofs of the inputs from the given index on.
+func (r *ProofRepository) DeleteFrom(ctx context.Context, inputIndex uint64) error {
+	_, err := executor(ctx, r.Db).ExecContext(ctx,
+		`DELETE FROM proofs WHERE input_index >= $1`, inputIndex)
+	if err != nil {
+		return fmt.Errorf("delete proofs: %w", err)
+	}
+	return nil
+}
+
+// Clear the epoch part of the proofs of the inputs from the given index on.
+func (r *ProofRepository) ClearEpochFrom(ctx context.Context, inputIndex uint64) error {
+	updateSql := `UPDATE proofs SET
+		first_input_index = NULL,
+		last_input_index = NULL,
+		input_index_within_epoch = NULL,
+		outputs_epoch_root_hash = NULL,
+		machine_state_hash = NULL,
+		output_hashes_in_epoch_siblings = NULL
+		WHERE input_index >= $1`
+	_, err := executor(ctx, r.Db).ExecContext(ctx, updateSql, inputIndex)
+	if err != nil {
+		return fmt.Errorf("clear proofs epoch: %w", err)
+	}
+	return nil
+}
+
+// Find the proof of the output.
+// Return nil if the output does not exist or if its epoch was not proved yet.
+func (r *ProofRepository) FindByInputAndOutputIndex(
+	ctx context.Context, inputIndex uint64, outputIndex uint64,
+) (*Proof, error) {
+	query := `SELECT * FROM proofs WHERE input_index = $1 and output_index = $2
+		and outputs_epoch_root_hash IS NOT NULL`
+	var row proofRow
+	err := sqlx.GetContext(ctx, executor(ctx, r.Db), &row, query, inputIndex, outputIndex)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return convertToProof(row)
+}
+
+func convertToProof(row proofRow) (*Proof, error) {
+	output, err := hexutil.Decode(row.Output)
+	if err != nil {
+		return nil, fmt.Errorf("proof output: %w", err)
+	}
+	outputSiblings, err := decodeHashes(row.OutputHashInOutputHashesSiblings)
+	if err != nil {
+		return nil, fmt.Errorf("proof output siblings: %w", err)
+	}
+	epochSiblings, err := decodeHashes(*row.OutputHashesInEpochSiblings)
+	if err != nil {
+		return nil, fmt.Errorf("proof epoch siblings: %w", err)
+	}
+	return &Proof{
+		InputIndex:                       row.InputIndex,
+		OutputIndex:                      row.OutputIndex,
+		Output:                           output,
+		OutputHash:                       common.HexToHash(row.OutputHash),
+		FirstInputIndex:                  *row.FirstInputIndex,
+		LastInputIndex:                   *row.LastInputIndex,
+		InputIndexWithinEpoch:            *row.InputIndexWithinEpoch,
+		OutputHashesRootHash:             common.HexToHash(row.OutputHashesRootHash),
+		OutputsEpochRootHash:             common.HexToHash(*row.OutputsEpochRootHash),
+		MachineStateHash:                 common.HexToHash(*row.MachineStateHash),
+		OutputHashInOutputHashesSiblings: outputSiblings,
+		OutputHashesInEpochSiblings:      epochSiblings,
+	}, nil
+}
+
+// Encode the hashes as a single hex string.
+func encodeHashes(hashes []common.Hash) string {
+	data := make([]byte, 0, len(hashes)*common.HashLength)
+	for _, hash := range hashes {
+		data = append(data, hash[:]...)
+	}
+	return hexutil.Encode(data)
+}
+
+func decodeHashes(encoded string) ([]common.Hash, error) {
+	data, err := hexutil.Decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%common.HashLength != 0 {
+		return nil, fmt.Errorf("invalid length %d", len(data))
+	}
+	hashes := make([]common.Hash, len(data)/common.HashLength)
+	for i := range hashes {
+		hashes[i] = common.BytesToHash(data[i*common.HashLength : (i+1)*common.HashLength])
+	}
+	return hashes, nil
+}
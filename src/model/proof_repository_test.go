@@ -0,0 +1,104 @@
+package model
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/calindra/rollups-server/src/merkle"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProofRepositorySuite struct {
+	suite.Suite
+	repository *ProofRepository
+	driver     string
+	database   *testDatabase
+}
+
+func (s *ProofRepositorySuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	var err error
+	s.database, err = openTestDatabase(s.driver)
+	s.Require().NoError(err)
+	s.repository = &ProofRepository{Db: s.database.Db}
+	s.Require().NoError(s.repository.CreateTables())
+}
+
+func (s *ProofRepositorySuite) TearDownTest() {
+	s.database.Close()
+}
+
+func TestProofRepositorySuite(t *testing.T) {
+	runWithDrivers(t, func(driver string) *ProofRepositorySuite {
+		return &ProofRepositorySuite{driver: driver}
+	})
+}
+
+// Save the proofs of the outputs of the input and return the root of their hashes.
+func (s *ProofRepositorySuite) createProofs(inputIndex uint64, outputs ...[]byte) common.Hash {
+	hashes := make([]common.Hash, len(outputs))
+	for i, output := range outputs {
+		hashes[i] = crypto.Keccak256Hash(output)
+	}
+	tree, err := merkle.NewTree(hashes, merkle.OutputsHeight)
+	s.Require().NoError(err)
+	for i, output := range outputs {
+		err := s.repository.Create(context.Background(), Proof{
+			InputIndex:                       inputIndex,
+			OutputIndex:                      uint64(i),
+			Output:                           output,
+			OutputHash:                       hashes[i],
+			OutputHashesRootHash:             tree.Root(),
+			OutputHashInOutputHashesSiblings: tree.Siblings(uint64(i)),
+		})
+		s.Require().NoError(err)
+	}
+	return tree.Root()
+}
+
+func (s *ProofRepositorySuite) TestProofBeforeEpoch() {
+	ctx := context.Background()
+	s.createProofs(0, []byte{0xaa})
+	proof, err := s.repository.FindByInputAndOutputIndex(ctx, 0, 0)
+	s.NoError(err)
+	s.Nil(proof)
+}
+
+func (s *ProofRepositorySuite) TestProveEpoch() {
+	ctx := context.Background()
+	roots := []common.Hash{
+		s.createProofs(3, []byte{0xaa}, []byte{0xbb}),
+		s.createProofs(4),
+		s.createProofs(5, []byte{0xcc}),
+	}
+	epochRoot, err := s.repository.ProveEpoch(ctx, 3, 5, roots)
+	s.Require().NoError(err)
+
+	proof, err := s.repository.FindByInputAndOutputIndex(ctx, 5, 0)
+	s.Require().NoError(err)
+	s.Require().NotNil(proof)
+	s.Equal([]byte{0xcc}, proof.Output)
+	s.Equal(uint64(3), proof.FirstInputIndex)
+	s.Equal(uint64(5), proof.LastInputIndex)
+	s.Equal(uint64(2), proof.InputIndexWithinEpoch)
+	s.Equal(epochRoot, proof.OutputsEpochRootHash)
+	s.Equal(common.Hash{}, proof.MachineStateHash)
+	outputsRoot := merkle.RootFromSiblings(proof.OutputHash, proof.OutputIndex, proof.OutputHashInOutputHashesSiblings)
+	s.Equal(roots[2], outputsRoot)
+	s.Equal(epochRoot, merkle.RootFromSiblings(outputsRoot, proof.InputIndexWithinEpoch, proof.OutputHashesInEpochSiblings))
+
+	proof, err = s.repository.FindByInputAndOutputIndex(ctx, 3, 1)
+	s.Require().NoError(err)
+	s.Require().NotNil(proof)
+	s.Equal(uint64(0), proof.InputIndexWithinEpoch)
+	s.Equal(epochRoot, proof.OutputsEpochRootHash)
+}
+
+func (s *ProofRepositorySuite) TestProveEpochWithWrongRoots() {
+	_, err := s.repository.ProveEpoch(context.Background(), 0, 2, []common.Hash{{}})
+	s.ErrorContains(err, "expected 3 roots, got 1")
+}
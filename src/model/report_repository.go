@@ -1,10 +1,11 @@
 package model
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
-	"strings"
 
+	"github.com/calindra/rollups-server/src/migrations"
 	"github.com/calindra/rollups-server/src/util"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jmoiron/sqlx"
@@ -14,12 +15,9 @@ type ReportRepository struct {
 	Db *sqlx.DB
 }
 
+// Create the tables by applying the pending schema migrations.
 func (r *ReportRepository) CreateTables() error {
-	schema := `CREATE TABLE IF NOT EXISTS reports (
-		output_index	integer,
-		payload 		text,
-		input_index 	integer);`
-	_, err := r.Db.Exec(schema)
+	_, err := migrations.Migrate(context.Background(), r.Db, false)
 	if err == nil {
 		slog.Debug("Reports table created")
 	} else {
@@ -28,17 +26,21 @@ func (r *ReportRepository) CreateTables() error {
 	return err
 }
 
-func (r *ReportRepository) Create(report Report) (Report, error) {
+func (r *ReportRepository) Create(ctx context.Context, report Report) (Report, error) {
 	insertSql := `INSERT INTO reports (
 		output_index,
 		payload,
 		input_index) VALUES ($1, $2, $3)`
-	r.Db.MustExec(
+	_, err := executor(ctx, r.Db).ExecContext(
+		ctx,
 		insertSql,
 		report.Index,
 		common.Bytes2Hex(report.Payload),
 		report.InputIndex,
 	)
+	if err != nil {
+		return report, err
+	}
 	return report, nil
 }
 
@@ -130,10 +132,10 @@ func (c *ReportRepository) FindAll(
 	after *string,
 	before *string,
 	filter []*ConvenienceFilter,
+	options ...util.PageOption,
 ) (*util.PageResult[Report], error) {
-	total, err := c.Count(filter)
+	page, err := util.ComputePage(first, last, after, before, options...)
 	if err != nil {
-		slog.Error("database error", "err", err)
 		return nil, err
 	}
 	query := `SELECT input_index, output_index, payload FROM reports `
@@ -142,19 +144,10 @@ func (c *ReportRepository) FindAll(
 		slog.Error("database error", "err", err)
 		return nil, err
 	}
-	query += where
-	query += `ORDER BY input_index ASC, output_index ASC `
-	offset, limit, err := util.ComputePage(first, last, after, before, int(total))
-	if err != nil {
-		return nil, err
-	}
-	query += fmt.Sprintf(`LIMIT $%d `, argsCount)
-	args = append(args, limit)
-	argsCount += 1
-	query += fmt.Sprintf(`OFFSET $%d `, argsCount)
-	args = append(args, offset)
+	pageQuery, args := compilePage(page, where, args, argsCount, "input_index", "output_index")
+	query += pageQuery
 
-	slog.Debug("Query", "query", query, "args", args, "total", total)
+	slog.Debug("Query", "query", query, "args", args)
 	stmt, err := c.Db.Preparex(query)
 	if err != nil {
 		return nil, err
@@ -165,6 +158,7 @@ func (c *ReportRepository) FindAll(
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	for rows.Next() {
 		var payload string
 		var inputIndex int
@@ -180,45 +174,38 @@ func (c *ReportRepository) FindAll(
 		reports = append(reports, *report)
 	}
 
-	pageResult := &util.PageResult[Report]{
-		Rows:   reports,
-		Total:  total,
-		Offset: uint64(offset),
+	result := util.NewPageResult(page, reports, reportCursor)
+	err = result.SetTotal(page, func() (uint64, error) {
+		return c.Count(filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Delete the reports of the inputs from the given index on.
+func (r *ReportRepository) DeleteFrom(ctx context.Context, inputIndex uint64) error {
+	_, err := executor(ctx, r.Db).ExecContext(ctx,
+		`DELETE FROM reports WHERE input_index >= $1`, inputIndex)
+	if err != nil {
+		return fmt.Errorf("delete reports: %w", err)
 	}
-	return pageResult, nil
+	return nil
+}
+
+func reportCursor(report Report) util.Cursor {
+	return util.Cursor{InputIndex: uint64(report.InputIndex), OutputIndex: uint64(report.Index)}
+}
+
+// Filterable columns of the reports table.
+var reportColumns = filterColumns{
+	INPUT_INDEX:  integerColumn("input_index"),
+	OUTPUT_INDEX: integerColumn("output_index"),
 }
 
 func transformToReportQuery(
 	filter []*ConvenienceFilter,
 ) (string, []interface{}, int, error) {
-	query := ""
-	if len(filter) > 0 {
-		query += "WHERE "
-	}
-	args := []interface{}{}
-	where := []string{}
-	count := 1
-	for _, filter := range filter {
-		if *filter.Field == "OutputIndex" {
-			if filter.Eq != nil {
-				where = append(where, fmt.Sprintf("output_index = $%d ", count))
-				args = append(args, *filter.Eq)
-				count += 1
-			} else {
-				return "", nil, 0, fmt.Errorf("operation not implemented")
-			}
-		} else if *filter.Field == INPUT_INDEX {
-			if filter.Eq != nil {
-				where = append(where, fmt.Sprintf("input_index = $%d ", count))
-				args = append(args, *filter.Eq)
-				count += 1
-			} else {
-				return "", nil, 0, fmt.Errorf("operation not implemented")
-			}
-		} else {
-			return "", nil, 0, fmt.Errorf("unexpected field %s", *filter.Field)
-		}
-	}
-	query += strings.Join(where, " and ")
-	return query, args, count, nil
+	return compileFilter(filter, reportColumns)
 }
@@ -1,12 +1,12 @@
 package model
 
 import (
+	"context"
 	"log/slog"
 	"testing"
 
 	"github.com/calindra/rollups-server/src/util"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/suite"
 )
@@ -18,20 +18,30 @@ import (
 type ReportRepositorySuite struct {
 	suite.Suite
 	reportRepository *ReportRepository
+	driver           string
+	database         *testDatabase
 }
 
 func (s *ReportRepositorySuite) SetupTest() {
 	util.ConfigureLog(slog.LevelDebug)
-	db := sqlx.MustConnect("sqlite3", ":memory:")
+	var err error
+	s.database, err = openTestDatabase(s.driver)
+	s.Require().NoError(err)
 	s.reportRepository = &ReportRepository{
-		Db: db,
+		Db: s.database.Db,
 	}
-	err := s.reportRepository.CreateTables()
+	err = s.reportRepository.CreateTables()
 	s.NoError(err)
 }
 
+func (s *ReportRepositorySuite) TearDownTest() {
+	s.database.Close()
+}
+
 func TestReportRepositorySuite(t *testing.T) {
-	suite.Run(t, new(ReportRepositorySuite))
+	runWithDrivers(t, func(driver string) *ReportRepositorySuite {
+		return &ReportRepositorySuite{driver: driver}
+	})
 }
 
 func (s *ReportRepositorySuite) TestCreateTables() {
@@ -40,7 +50,7 @@ func (s *ReportRepositorySuite) TestCreateTables() {
 }
 
 func (s *ReportRepositorySuite) TestCreateReport() {
-	_, err := s.reportRepository.Create(Report{
+	_, err := s.reportRepository.Create(context.Background(), Report{
 		Index:      1,
 		InputIndex: 2,
 		Payload:    common.Hex2Bytes("1122"),
@@ -49,7 +59,7 @@ func (s *ReportRepositorySuite) TestCreateReport() {
 }
 
 func (s *ReportRepositorySuite) TestCreateReportAndFind() {
-	_, err := s.reportRepository.Create(Report{
+	_, err := s.reportRepository.Create(context.Background(), Report{
 		InputIndex: 1,
 		Index:      2,
 		Payload:    common.Hex2Bytes("1122"),
@@ -75,7 +85,7 @@ func (s *ReportRepositorySuite) TestReportNotFound() {
 func (s *ReportRepositorySuite) TestCreateReportAndFindAll() {
 	for i := 0; i < 3; i++ {
 		for j := 0; j < 4; j++ {
-			_, err := s.reportRepository.Create(Report{
+			_, err := s.reportRepository.Create(context.Background(), Report{
 				InputIndex: i,
 				Index:      j,
 				Payload:    common.Hex2Bytes("1122"),
@@ -85,7 +95,10 @@ func (s *ReportRepositorySuite) TestCreateReportAndFindAll() {
 	}
 	reports, err := s.reportRepository.FindAll(nil, nil, nil, nil, nil)
 	s.NoError(err)
-	s.Equal(12, int(reports.Total))
+	s.Len(reports.Rows, 12)
+	total, err := s.reportRepository.Count(nil)
+	s.NoError(err)
+	s.Equal(12, int(total))
 	s.Equal(0, reports.Rows[0].InputIndex)
 	s.Equal(2, reports.Rows[len(reports.Rows)-1].InputIndex)
 
@@ -100,7 +113,10 @@ func (s *ReportRepositorySuite) TestCreateReportAndFindAll() {
 	}
 	reports, err = s.reportRepository.FindAll(nil, nil, nil, nil, filter)
 	s.NoError(err)
-	s.Equal(4, int(reports.Total))
+	s.Len(reports.Rows, 4)
+	total, err = s.reportRepository.Count(filter)
+	s.NoError(err)
+	s.Equal(4, int(total))
 	s.Equal(1, reports.Rows[0].InputIndex)
 	s.Equal(0, reports.Rows[0].Index)
 	s.Equal(1, reports.Rows[len(reports.Rows)-1].InputIndex)
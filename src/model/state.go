@@ -7,9 +7,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math/big"
 
+	"github.com/calindra/rollups-server/src/merkle"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const VOUCHER_SELECTOR = "ef615e2f"
@@ -19,10 +22,10 @@ const NOTICE_SELECTOR = "c258d6e5"
 type rollupsState interface {
 
 	// Finish the current state, saving the result to the model.
-	Finish(status CompletionStatus)
+	Finish(status CompletionStatus) error
 
 	// Add voucher to current state.
-	AddVoucher(destination common.Address, payload []byte) (int, error)
+	AddVoucher(destination common.Address, value *big.Int, payload []byte) (int, error)
 
 	// Add notice to current state.
 	AddNotice(payload []byte) (int, error)
@@ -39,10 +42,17 @@ type Decoder interface {
 	HandleOutput(
 		ctx context.Context,
 		destination common.Address,
+		value *big.Int,
 		payload string,
 		inputIndex uint64,
 		outputIndex uint64,
 	) error
+
+	// Handle a new advance input; the deposits from the portals are decoded here.
+	HandleInput(ctx context.Context, input AdvanceInput) error
+
+	// Remove the outputs and deposits of the inputs from the given index on.
+	RewindOutputs(ctx context.Context, inputIndex uint64) error
 }
 
 //
@@ -56,11 +66,12 @@ func NewRollupsStateIdle() *RollupsStateIdle {
 	return &RollupsStateIdle{}
 }
 
-func (s *RollupsStateIdle) Finish(status CompletionStatus) {
+func (s *RollupsStateIdle) Finish(status CompletionStatus) error {
 	// Do nothing
+	return nil
 }
 
-func (s *RollupsStateIdle) AddVoucher(destination common.Address, payload []byte) (int, error) {
+func (s *RollupsStateIdle) AddVoucher(destination common.Address, value *big.Int, payload []byte) (int, error) {
 	return 0, fmt.Errorf("cannot add voucher in idle state")
 }
 
@@ -81,14 +92,18 @@ func (s *RollupsStateIdle) RegisterException(payload []byte) error {
 //
 
 // In the advance state, the model accumulates the outputs from an advance.
+// Vouchers and notices share the output indices, like in the Cartesi machine.
 type rollupsStateAdvance struct {
 	input            *AdvanceInput
 	vouchers         []Voucher
 	notices          []Notice
 	reports          []Report
+	outputs          [][]byte
 	decoder          Decoder
 	reportRepository *ReportRepository
 	inputRepository  *InputRepository
+	proofRepository  *ProofRepository
+	epochRepository  *EpochRepository
 }
 
 func NewRollupsStateAdvance(
@@ -96,6 +111,8 @@ func NewRollupsStateAdvance(
 	decoder Decoder,
 	reportRepository *ReportRepository,
 	inputRepository *InputRepository,
+	proofRepository *ProofRepository,
+	epochRepository *EpochRepository,
 ) *rollupsStateAdvance {
 	slog.Info("rollups-server: processing advance", "index", input.Index)
 	return &rollupsStateAdvance{
@@ -103,105 +120,206 @@ func NewRollupsStateAdvance(
 		decoder:          decoder,
 		reportRepository: reportRepository,
 		inputRepository:  inputRepository,
+		proofRepository:  proofRepository,
+		epochRepository:  epochRepository,
 	}
 }
 
-func sendAllInputVouchersToDecoder(decoder Decoder, inputIndex uint64, vouchers []Voucher) {
+func sendAllInputVouchersToDecoder(ctx context.Context, decoder Decoder, inputIndex uint64, vouchers []Voucher) error {
 	if decoder == nil {
 		slog.Warn("Missing OutputDecoder to send vouchers")
-		return
+		return nil
 	}
-	ctx := context.Background()
 	for _, v := range vouchers {
 		adapted := fmt.Sprintf("0x%s%s", VOUCHER_SELECTOR, common.Bytes2Hex(v.Payload))
 
 		err := decoder.HandleOutput(
 			ctx,
 			v.Destination,
+			v.Value,
 			adapted,
 			inputIndex,
 			uint64(v.Index),
 		)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("decode voucher %d: %w", v.Index, err)
 		}
 	}
+	return nil
 }
 
-func sendAllInputNoticesToDecoder(decoder Decoder, inputIndex uint64, notices []Notice) {
+func sendAllInputNoticesToDecoder(ctx context.Context, decoder Decoder, inputIndex uint64, notices []Notice) error {
 	if decoder == nil {
 		slog.Warn("Missing OutputDecoder to send notices")
-		return
+		return nil
 	}
-	ctx := context.Background()
 	for _, v := range notices {
 		adapted := fmt.Sprintf("0x%s%s", NOTICE_SELECTOR, common.Bytes2Hex(v.Payload))
 
 		err := decoder.HandleOutput(
 			ctx,
 			common.Address{},
+			nil,
 			adapted,
 			inputIndex,
 			uint64(v.Index),
 		)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("decode notice %d: %w", v.Index, err)
 		}
 	}
+	return nil
 }
 
-func saveAllReports(reportRepository *ReportRepository, reports []Report) {
+func saveAllReports(ctx context.Context, reportRepository *ReportRepository, reports []Report) error {
 	if reportRepository == nil {
 		slog.Warn("Missing reportRepository to save reports")
-		return
+		return nil
 	}
 	if reportRepository.Db == nil {
 		slog.Warn("Missing reportRepository.Db to save reports")
-		return
+		return nil
 	}
 	for _, r := range reports {
-		_, err := reportRepository.Create(r)
+		_, err := reportRepository.Create(ctx, r)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("save report %d: %w", r.Index, err)
 		}
 	}
+	return nil
 }
 
-func (s *rollupsStateAdvance) Finish(status CompletionStatus) {
-	s.input.Status = status
-	if status == CompletionStatusAccepted {
-		s.input.Vouchers = s.vouchers
-		s.input.Notices = s.notices
-		if s.decoder != nil {
-			sendAllInputVouchersToDecoder(s.decoder, uint64(s.input.Index), s.vouchers)
-			sendAllInputNoticesToDecoder(s.decoder, uint64(s.input.Index), s.notices)
+// Build the tree of the output hashes of the input and save the proofs of its outputs.
+// The proofs are completed when the epoch of the input is proved.
+func (s *rollupsStateAdvance) proveOutputs(ctx context.Context, outputs [][]byte) error {
+	hashes := make([]common.Hash, len(outputs))
+	for i, output := range outputs {
+		hashes[i] = crypto.Keccak256Hash(output)
+	}
+	tree, err := merkle.NewTree(hashes, merkle.OutputsHeight)
+	if err != nil {
+		return fmt.Errorf("output hashes tree: %w", err)
+	}
+	inputIndex := uint64(s.input.Index)
+	for i, output := range outputs {
+		err := s.proofRepository.Create(ctx, Proof{
+			InputIndex:                       inputIndex,
+			OutputIndex:                      uint64(i),
+			Output:                           output,
+			OutputHash:                       hashes[i],
+			OutputHashesRootHash:             tree.Root(),
+			OutputHashInOutputHashesSiblings: tree.Siblings(uint64(i)),
+		})
+		if err != nil {
+			return err
 		}
 	}
-	// s.input.Reports = s.reports
-	saveAllReports(s.reportRepository, s.reports)
-	_, err := s.inputRepository.Update(*s.input)
+	s.input.OutputHashesRootHash = tree.Root()
+	return nil
+}
+
+// Update the finished input and prove its epoch if it was the last input to be processed.
+func (s *rollupsStateAdvance) updateInput(ctx context.Context) error {
+	_, err := s.inputRepository.Update(ctx, *s.input)
+	if err != nil {
+		return fmt.Errorf("update input: %w", err)
+	}
+	proved, err := proveEpoch(ctx, s.epochRepository, s.inputRepository, s.proofRepository, s.input.EpochIndex)
+	if err != nil {
+		return fmt.Errorf("prove epoch: %w", err)
+	}
+	if proved {
+		slog.Info("rollups-server: proved epoch", "index", s.input.EpochIndex)
+	}
+	return nil
+}
+
+// Run fn in a database transaction attached to its context.
+// The outputs, reports and status of the input are either all saved or none of them.
+func (s *rollupsStateAdvance) inTransaction(fn func(ctx context.Context) error) error {
+	return inTransaction(s.inputRepository.Db, fn)
+}
+
+func (s *rollupsStateAdvance) Finish(status CompletionStatus) error {
+	s.input.Status = status
+	err := s.inTransaction(func(ctx context.Context) error {
+		if status == CompletionStatusAccepted {
+			s.input.Vouchers = s.vouchers
+			s.input.Notices = s.notices
+			if s.decoder != nil {
+				err := sendAllInputVouchersToDecoder(ctx, s.decoder, uint64(s.input.Index), s.vouchers)
+				if err != nil {
+					return err
+				}
+				err = sendAllInputNoticesToDecoder(ctx, s.decoder, uint64(s.input.Index), s.notices)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		// the outputs of rejected inputs are discarded
+		outputs := s.outputs
+		if status != CompletionStatusAccepted {
+			outputs = nil
+		}
+		err := s.proveOutputs(ctx, outputs)
+		if err != nil {
+			return err
+		}
+		// s.input.Reports = s.reports
+		err = saveAllReports(ctx, s.reportRepository, s.reports)
+		if err != nil {
+			return err
+		}
+		return s.updateInput(ctx)
+	})
 	if err != nil {
-		panic(err)
+		return err
 	}
 	slog.Info("rollups-server: finished advance")
+	return nil
+}
+
+// Add the encoded output and return its index within the input.
+func (s *rollupsStateAdvance) addOutput(output []byte) (int, error) {
+	if len(s.outputs) >= 1<<merkle.OutputsHeight {
+		return 0, fmt.Errorf("too many outputs for the input")
+	}
+	s.outputs = append(s.outputs, output)
+	return len(s.outputs) - 1, nil
 }
 
-func (s *rollupsStateAdvance) AddVoucher(destination common.Address, payload []byte) (int, error) {
-	index := len(s.vouchers)
+func (s *rollupsStateAdvance) AddVoucher(destination common.Address, value *big.Int, payload []byte) (int, error) {
+	output, err := EncodeVoucherOutput(destination, value, payload)
+	if err != nil {
+		return 0, err
+	}
+	index, err := s.addOutput(output)
+	if err != nil {
+		return 0, err
+	}
 	voucher := Voucher{
 		Index:       index,
 		InputIndex:  s.input.Index,
 		Destination: destination,
+		Value:       value,
 		Payload:     payload,
 	}
 	s.vouchers = append(s.vouchers, voucher)
 	slog.Info("rollups-server: added voucher", "index", index, "destination", destination,
-		"payload", hexutil.Encode(payload))
+		"value", value, "payload", hexutil.Encode(payload))
 	return index, nil
 }
 
 func (s *rollupsStateAdvance) AddNotice(payload []byte) (int, error) {
-	index := len(s.notices)
+	output, err := EncodeNoticeOutput(payload)
+	if err != nil {
+		return 0, err
+	}
+	index, err := s.addOutput(output)
+	if err != nil {
+		return 0, err
+	}
 	notice := Notice{
 		Index:      index,
 		InputIndex: s.input.Index,
@@ -228,11 +346,20 @@ func (s *rollupsStateAdvance) RegisterException(payload []byte) error {
 	s.input.Status = CompletionStatusException
 	s.input.Reports = s.reports
 	s.input.Exception = payload
-	_, err := s.inputRepository.Update(*s.input)
+	err := s.inTransaction(func(ctx context.Context) error {
+		err := s.proveOutputs(ctx, nil)
+		if err != nil {
+			return err
+		}
+		err = saveAllReports(ctx, s.reportRepository, s.reports)
+		if err != nil {
+			return err
+		}
+		return s.updateInput(ctx)
+	})
 	if err != nil {
-		panic(err)
+		return err
 	}
-	saveAllReports(s.reportRepository, s.reports)
 	slog.Info("rollups-server: finished advance with exception")
 	return nil
 }
@@ -245,28 +372,40 @@ func (s *rollupsStateAdvance) RegisterException(payload []byte) error {
 type rollupsStateInspect struct {
 	input                  *InspectInput
 	reports                []Report
-	getProcessedInputCount func() int
+	inspectRepository      *InspectRepository
+	getProcessedInputCount func() (int, error)
 }
 
 func NewRollupsStateInspect(
 	input *InspectInput,
-	getProcessedInputCount func() int,
+	inspectRepository *InspectRepository,
+	getProcessedInputCount func() (int, error),
 ) *rollupsStateInspect {
 	slog.Info("rollups-server: processing inspect", "index", input.Index)
 	return &rollupsStateInspect{
 		input:                  input,
+		inspectRepository:      inspectRepository,
 		getProcessedInputCount: getProcessedInputCount,
 	}
 }
 
-func (s *rollupsStateInspect) Finish(status CompletionStatus) {
+func (s *rollupsStateInspect) Finish(status CompletionStatus) error {
+	processedInputCount, err := s.getProcessedInputCount()
+	if err != nil {
+		return fmt.Errorf("get processed input count: %w", err)
+	}
 	s.input.Status = status
-	s.input.ProcessedInputCount = s.getProcessedInputCount()
+	s.input.ProcessedInputCount = processedInputCount
 	s.input.Reports = s.reports
+	_, err = s.inspectRepository.Update(*s.input)
+	if err != nil {
+		return fmt.Errorf("update inspect: %w", err)
+	}
 	slog.Info("rollups-server: finished inspect")
+	return nil
 }
 
-func (s *rollupsStateInspect) AddVoucher(destination common.Address, payload []byte) (int, error) {
+func (s *rollupsStateInspect) AddVoucher(destination common.Address, value *big.Int, payload []byte) (int, error) {
 	return 0, fmt.Errorf("cannot add voucher in inspect state")
 }
 
@@ -287,10 +426,18 @@ func (s *rollupsStateInspect) AddReport(payload []byte) error {
 }
 
 func (s *rollupsStateInspect) RegisterException(payload []byte) error {
+	processedInputCount, err := s.getProcessedInputCount()
+	if err != nil {
+		return fmt.Errorf("get processed input count: %w", err)
+	}
 	s.input.Status = CompletionStatusException
-	s.input.ProcessedInputCount = s.getProcessedInputCount()
+	s.input.ProcessedInputCount = processedInputCount
 	s.input.Reports = s.reports
 	s.input.Exception = payload
+	_, err = s.inspectRepository.Update(*s.input)
+	if err != nil {
+		return fmt.Errorf("update inspect: %w", err)
+	}
 	slog.Info("rollups-server: finished inspect with exception")
 	return nil
 }
@@ -0,0 +1,294 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+// Decoder that stores the outputs like the OutputDecoder and fails on the notices while failing is set.
+// It fails on the inputs while failingInputs is set and counts the handled inputs otherwise.
+type storingDecoder struct {
+	vouchers      *VoucherRepository
+	notices       *NoticeRepository
+	failing       bool
+	failingInputs bool
+	inputs        int
+}
+
+func (d *storingDecoder) HandleOutput(
+	ctx context.Context,
+	destination common.Address,
+	value *big.Int,
+	payload string,
+	inputIndex uint64,
+	outputIndex uint64,
+) error {
+	if payload[2:10] == VOUCHER_SELECTOR {
+		_, err := d.vouchers.CreateVoucher(ctx, &ConvenienceVoucher{
+			Destination: destination,
+			Value:       value,
+			Payload:     util.RemoveSelector(payload),
+			InputIndex:  inputIndex,
+			OutputIndex: outputIndex,
+		})
+		return err
+	}
+	if d.failing {
+		return errors.New("disk I/O error")
+	}
+	_, err := d.notices.Create(ctx, &ConvenienceNotice{
+		Payload:     util.RemoveSelector(payload),
+		InputIndex:  inputIndex,
+		OutputIndex: outputIndex,
+	})
+	return err
+}
+
+func (d *storingDecoder) HandleInput(ctx context.Context, input AdvanceInput) error {
+	if d.failingInputs {
+		return errors.New("disk I/O error")
+	}
+	d.inputs++
+	return nil
+}
+
+func (d *storingDecoder) RewindOutputs(ctx context.Context, inputIndex uint64) error {
+	err := d.vouchers.DeleteFrom(ctx, inputIndex)
+	if err != nil {
+		return err
+	}
+	return d.notices.DeleteFrom(ctx, inputIndex)
+}
+
+type StateSuite struct {
+	suite.Suite
+	model    *AppModel
+	decoder  *storingDecoder
+	driver   string
+	database *testDatabase
+}
+
+func (s *StateSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	var err error
+	s.database, err = openTestDatabase(s.driver)
+	s.Require().NoError(err)
+	db := s.database.Db
+	s.decoder = &storingDecoder{
+		vouchers: &VoucherRepository{Db: *db},
+		notices:  &NoticeRepository{Db: *db},
+	}
+	s.NoError(s.decoder.vouchers.CreateTables())
+	s.NoError(s.decoder.notices.CreateTables())
+	s.model = NewAppModel(s.decoder, db)
+}
+
+func (s *StateSuite) TearDownTest() {
+	s.database.Close()
+}
+
+func TestStateSuite(t *testing.T) {
+	runWithDrivers(t, func(driver string) *StateSuite {
+		return &StateSuite{driver: driver}
+	})
+}
+
+func (s *StateSuite) TestFinishRollsBackOnFailure() {
+	ctx := context.Background()
+	err := s.model.AddAdvanceInput(common.Address{}, common.Hex2Bytes("1122"), 1, time.Now(), 0)
+	s.NoError(err)
+	input, err := s.model.FinishAndGetNext(true)
+	s.NoError(err)
+	s.NotNil(input)
+	_, err = s.model.AddVoucher(common.Address{}, big.NewInt(1), common.Hex2Bytes("aa"))
+	s.NoError(err)
+	_, err = s.model.AddNotice(common.Hex2Bytes("bb"))
+	s.NoError(err)
+	s.NoError(s.model.AddReport(common.Hex2Bytes("cc")))
+
+	s.decoder.failing = true
+	_, err = s.model.FinishAndGetNext(true)
+	s.Error(err)
+	vouchers, err := s.decoder.vouchers.Count(ctx, nil)
+	s.NoError(err)
+	s.Equal(0, int(vouchers))
+	reports, err := s.model.ReportRepository.Count(nil)
+	s.NoError(err)
+	s.Equal(0, int(reports))
+	stored, err := s.model.InputRepository.FindByIndex(context.Background(), 0)
+	s.NoError(err)
+	s.Equal(CompletionStatusUnprocessed, stored.Status)
+
+	s.decoder.failing = false
+	_, err = s.model.FinishAndGetNext(true)
+	s.NoError(err)
+	vouchers, err = s.decoder.vouchers.Count(ctx, nil)
+	s.NoError(err)
+	s.Equal(1, int(vouchers))
+	notices, err := s.decoder.notices.Count(ctx, nil)
+	s.NoError(err)
+	s.Equal(1, int(notices))
+	reports, err = s.model.ReportRepository.Count(nil)
+	s.NoError(err)
+	s.Equal(1, int(reports))
+	stored, err = s.model.InputRepository.FindByIndex(context.Background(), 0)
+	s.NoError(err)
+	s.Equal(CompletionStatusAccepted, stored.Status)
+}
+
+func (s *StateSuite) TestAddInputRollsBackOnFailure() {
+	input := AdvanceInput{Index: 0, Payload: common.Hex2Bytes("1122"), BlockNumber: 1,
+		BlockTimestamp: time.Now()}
+	s.decoder.failingInputs = true
+	err := s.model.AddEvmAdvanceInput(input)
+	s.Error(err)
+	stored, err := s.model.InputRepository.FindByIndex(context.Background(), 0)
+	s.NoError(err)
+	s.Nil(stored)
+
+	s.decoder.failingInputs = false
+	err = s.model.AddEvmAdvanceInput(input)
+	s.NoError(err)
+	stored, err = s.model.InputRepository.FindByIndex(context.Background(), 0)
+	s.NoError(err)
+	s.NotNil(stored)
+	s.Equal(1, s.decoder.inputs)
+}
+
+func (s *StateSuite) TestAddInputRollsBackEpochOnFailure() {
+	s.model.EpochDuration = 0
+	s.model.EpochBlockCount = 10
+	err := s.model.AddAdvanceInput(common.Address{}, common.Hex2Bytes("11"), 1, time.Now(), 0)
+	s.Require().NoError(err)
+
+	// the input closes the epoch, but the failure keeps it open
+	s.decoder.failingInputs = true
+	err = s.model.AddAdvanceInput(common.Address{}, common.Hex2Bytes("22"), 11, time.Now(), 1)
+	s.Error(err)
+	epochs, err := s.model.GetEpochs()
+	s.Require().NoError(err)
+	s.Require().Len(epochs, 1)
+	s.Equal(EpochStatusOpen, epochs[0].Status)
+
+	s.decoder.failingInputs = false
+	err = s.model.AddAdvanceInput(common.Address{}, common.Hex2Bytes("22"), 11, time.Now(), 1)
+	s.Require().NoError(err)
+	epochs, err = s.model.GetEpochs()
+	s.Require().NoError(err)
+	s.Require().Len(epochs, 2)
+	s.Equal(EpochStatusClosed, epochs[0].Status)
+	s.Equal(EpochStatusOpen, epochs[1].Status)
+}
+
+func (s *StateSuite) TestNotifyCompletedInputs() {
+	_, err := s.model.AddInspectInput(common.Hex2Bytes("1122"))
+	s.NoError(err)
+	input, err := s.model.FinishAndGetNext(true)
+	s.NoError(err)
+	s.IsType(InspectInput{}, input)
+
+	completed := s.model.CompletedInputs()
+	select {
+	case <-completed:
+		s.Fail("notified before the inspect finished")
+	default:
+	}
+	s.NoError(s.model.RegisterException(common.Hex2Bytes("ff")))
+	select {
+	case <-completed:
+	default:
+		s.Fail("not notified after the inspect finished")
+	}
+}
+
+func (s *StateSuite) TestRewindInputs() {
+	ctx := context.Background()
+	s.model.EpochDuration = 0
+	s.model.EpochBlockCount = 10
+	for i, blockNumber := range []uint64{1, 5, 12, 14} {
+		err := s.model.AddAdvanceInput(common.Address{}, common.Hex2Bytes("1122"), blockNumber, time.Now(), i)
+		s.Require().NoError(err)
+		_, err = s.model.FinishAndGetNext(true)
+		s.Require().NoError(err)
+		_, err = s.model.AddVoucher(common.Address{}, big.NewInt(1), common.Hex2Bytes("aa"))
+		s.Require().NoError(err)
+		s.Require().NoError(s.model.AddReport(common.Hex2Bytes("cc")))
+	}
+	_, err := s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	epoch, err := s.model.GetEpoch(0)
+	s.Require().NoError(err)
+	s.Equal(EpochStatusProved, epoch.Status)
+	s.NoError(s.model.SetCheckpoint("inputter", Block{Number: 20, Hash: common.HexToHash("0xabcd")}))
+
+	// the second epoch keeps its first input
+	removed, err := s.model.RewindInputs(13)
+	s.NoError(err)
+	s.Equal(1, removed)
+	epoch, err = s.model.GetEpoch(1)
+	s.Require().NoError(err)
+	s.Equal(EpochStatusOpen, epoch.Status)
+	vouchers, err := s.decoder.vouchers.Count(ctx, nil)
+	s.NoError(err)
+	s.Equal(3, int(vouchers))
+	checkpoint, err := s.model.GetCheckpoint("inputter")
+	s.NoError(err)
+	s.Equal(&Block{Number: 13}, checkpoint)
+
+	// the first epoch is opened again and loses its proofs
+	removed, err = s.model.RewindInputs(4)
+	s.NoError(err)
+	s.Equal(2, removed)
+	epochs, err := s.model.GetEpochs()
+	s.NoError(err)
+	s.Require().Len(epochs, 1)
+	s.Equal(EpochStatusOpen, epochs[0].Status)
+	proof, err := s.model.GetProof(0, 0)
+	s.NoError(err)
+	s.Nil(proof)
+	inputs, err := s.model.InputRepository.Count(context.Background(), nil)
+	s.NoError(err)
+	s.Equal(1, int(inputs))
+	reports, err := s.model.ReportRepository.Count(nil)
+	s.NoError(err)
+	s.Equal(1, int(reports))
+	vouchers, err = s.decoder.vouchers.Count(ctx, nil)
+	s.NoError(err)
+	s.Equal(1, int(vouchers))
+
+	// the inputs of the new chain are added with the same indices
+	err = s.model.AddAdvanceInput(common.Address{}, common.Hex2Bytes("3344"), 6, time.Now(), 1)
+	s.NoError(err)
+	input, err := s.model.InputRepository.FindByIndex(context.Background(), 1)
+	s.NoError(err)
+	s.Equal(common.Hex2Bytes("3344"), input.Payload)
+	s.Equal(uint64(0), input.EpochIndex)
+}
+
+func (s *StateSuite) TestRewindClaimedEpoch() {
+	s.model.EpochDuration = 0
+	err := s.model.AddAdvanceInput(common.Address{}, common.Hex2Bytes("1122"), 1, time.Now(), 0)
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.CloseEpoch()
+	s.Require().NoError(err)
+	s.Require().NoError(s.model.SetEpochClaimed(0, common.HexToHash("0xabcd")))
+
+	_, err = s.model.RewindInputs(0)
+	s.ErrorIs(err, ErrClaimedEpoch)
+	inputs, err := s.model.InputRepository.Count(context.Background(), nil)
+	s.NoError(err)
+	s.Equal(1, int(inputs))
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type transactionKey struct{}
+
+// Attach a database transaction to the context.
+// The repositories run their statements in this transaction when it is present.
+func WithTransaction(ctx context.Context, tx *sqlx.Tx) context.Context {
+	return context.WithValue(ctx, transactionKey{}, tx)
+}
+
+// Get the transaction attached to the context, if any.
+func GetTransaction(ctx context.Context) *sqlx.Tx {
+	tx, _ := ctx.Value(transactionKey{}).(*sqlx.Tx)
+	return tx
+}
+
+// Return the transaction from the context or the database when there is none.
+func executor(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx := GetTransaction(ctx); tx != nil {
+		return tx
+	}
+	return db
+}
+
+// Run fn in a database transaction attached to its context.
+// The transaction is committed when fn succeeds and rolled back otherwise.
+func inTransaction(db *sqlx.DB, fn func(ctx context.Context) error) error {
+	ctx := context.Background()
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	err = fn(WithTransaction(ctx, tx))
+	if err != nil {
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
@@ -4,20 +4,37 @@
 package model
 
 import (
+	"encoding/json"
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const INPUT_INDEX = "InputIndex"
+const OUTPUT_INDEX = "OutputIndex"
 const EXECUTED = "Executed"
 const DESTINATION = "Destination"
+const STATUS = "Status"
+const MSG_SENDER = "MsgSender"
+const BLOCK_NUMBER = "BlockNumber"
+const KIND = "Kind"
+const TOKEN = "Token"
+const DEPOSITOR = "Depositor"
+const CONTRACT = "Contract"
+const BENEFICIARY = "Beneficiary"
+const METHOD_SIGNATURE = "MethodSignature"
+const ERCX = "ERCX"
+const AMOUNT = "Amount"
+const LABEL = "Label"
 
 // Rollups voucher type.
 type Voucher struct {
 	Index       int
 	InputIndex  int
 	Destination common.Address
+	Value       *big.Int
 	Payload     []byte
 }
 
@@ -67,12 +84,85 @@ type AdvanceInput struct {
 	MsgSender      common.Address
 	Payload        []byte
 	BlockNumber    uint64
+	BlockHash      common.Hash
 	BlockTimestamp time.Time
-	PrevRandao     uint64
-	Vouchers       []Voucher
-	Notices        []Notice
-	Reports        []Report
-	Exception      []byte
+	PrevRandao     *big.Int
+	ChainId        uint64
+	AppContract    common.Address
+	// Hash of the L1 transaction and index of the log that added the input to the input box.
+	TransactionHash common.Hash
+	LogIndex        uint64
+
+	Vouchers  []Voucher
+	Notices   []Notice
+	Reports   []Report
+	Exception []byte
+
+	// Root of the tree of the output hashes, which is set when the input is finished.
+	OutputHashesRootHash common.Hash
+	EpochIndex           uint64
+}
+
+// Status of an epoch.
+type EpochStatus int
+
+const (
+	// The epoch receives new inputs.
+	EpochStatusOpen EpochStatus = iota
+	// The epoch does not receive new inputs, but some of its inputs were not processed yet.
+	EpochStatusClosed
+	// The inputs of the epoch were processed and the proofs of its outputs are complete.
+	EpochStatusProved
+	// The claim of the epoch was accepted by the consensus contract.
+	EpochStatusClaimed
+)
+
+// Range of inputs whose outputs are proved together.
+type Epoch struct {
+	Index      uint64
+	Status     EpochStatus
+	FirstBlock uint64
+	OpenedAt   time.Time
+
+	// Set when the epoch is closed.
+	FirstInputIndex uint64
+	LastInputIndex  uint64
+	ClosedAt        time.Time
+
+	// Set when the epoch is proved.
+	OutputsEpochRootHash common.Hash
+	MachineStateHash     common.Hash
+
+	// Set when the epoch is claimed.
+	ClaimTransactionHash common.Hash
+}
+
+// Hash that the consensus contract receives as the claim of the epoch.
+func (e Epoch) EpochHash() common.Hash {
+	return crypto.Keccak256Hash(e.OutputsEpochRootHash[:], e.MachineStateHash[:])
+}
+
+// Block of the base layer.
+type Block struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// Proof that an output was emitted by an input of a closed epoch.
+// The fields match the OutputValidityProof of the application contract.
+type Proof struct {
+	InputIndex                       uint64
+	OutputIndex                      uint64
+	Output                           []byte
+	OutputHash                       common.Hash
+	FirstInputIndex                  uint64
+	LastInputIndex                   uint64
+	InputIndexWithinEpoch            uint64
+	OutputHashesRootHash             common.Hash
+	OutputsEpochRootHash             common.Hash
+	MachineStateHash                 common.Hash
+	OutputHashInOutputHashesSiblings []common.Hash
+	OutputHashesInEpochSiblings      []common.Hash
 }
 
 // Rollups inspect input type.
@@ -102,28 +192,102 @@ type ConvenienceFilter struct {
 // Voucher metadata type
 type ConvenienceVoucher struct {
 	Destination common.Address `db:"destination"`
+	Value       *big.Int       `db:"value"`
 	Payload     string         `db:"payload"`
 	InputIndex  uint64         `db:"input_index"`
 	OutputIndex uint64         `db:"output_index"`
 	Executed    bool           `db:"executed"`
 
+	// Set when the voucher is executed on chain.
+	ExecutedTxHash common.Hash
+	ExecutedBlock  uint64
+
+	// Set when the voucher is simulated against the chain state.
+	Simulation *VoucherSimulation
+
+	// Decoded from the payload when the voucher is a standard withdrawal call.
+	// Contract is the token contract; it is zero for the Ether withdrawals.
+	Contract    common.Address
+	Beneficiary common.Address
+	// Amount of Ether or tokens; not set for the ERC-721 transfers.
+	Amount *big.Int
+	// Id of the ERC-721 or ERC-1155 token.
+	TokenId *big.Int
+	// Signature of the called method, such as transfer(address,uint256).
+	MethodSignature string
+	// Standard of the withdrawn asset; one of the ERCX constants or empty.
+	ERCX string
+	// Short description of the withdrawal, such as "ERC-20 transfer".
+	Label string
+
+	// Decoded from the payload with the ABI of the destination in the ABI registry.
+	// The arguments are a JSON object indexed by the argument names.
+	DecodedFunction string
+	DecodedArgs     json.RawMessage
+
 	// Proof we can fetch from the original GraphQL
 
 	// future improvements
-	// Contract        common.Address
-	// Beneficiary     common.Address
-	// Label           string
-	// Amount          uint64
 	// ExecutedAt      uint64
-	// ExecutedBlock   uint64
-	// InputIndex      int
-	// OutputIndex     int
-	// MethodSignature string
-	// ERCX            string
+}
+
+// Standards of the assets withdrawn by the vouchers.
+const (
+	ERCXEther = "Ether"
+	ERCX20    = "ERC20"
+	ERCX721   = "ERC721"
+	ERCX1155  = "ERC1155"
+)
+
+// Result of calling the voucher destination from the application with eth_call.
+type VoucherSimulation struct {
+	Success bool
+	// Gas estimate of the call; only set when it succeeds.
+	GasEstimate uint64
+	// Data returned by the call when it reverts.
+	RevertData []byte
+	// Error message of the node when the call fails.
+	Error string
 }
 
 type ConvenienceNotice struct {
 	Payload     string `db:"payload"`
 	InputIndex  uint64 `db:"input_index"`
 	OutputIndex uint64 `db:"output_index"`
+
+	// Decoded from the payload with a notice schema of the ABI registry.
+	// The fields are a JSON object indexed by the field names.
+	DecodedSchema string
+	DecodedFields json.RawMessage
+}
+
+// Kind of the portal that sent a deposit.
+type DepositKind string
+
+const (
+	DepositKindEther         DepositKind = "Ether"
+	DepositKindERC20         DepositKind = "ERC20"
+	DepositKindERC721        DepositKind = "ERC721"
+	DepositKindERC1155Single DepositKind = "ERC1155Single"
+	DepositKindERC1155Batch  DepositKind = "ERC1155Batch"
+)
+
+// Deposit decoded from an advance input sent by one of the application portals.
+type ConvenienceDeposit struct {
+	InputIndex uint64
+	Kind       DepositKind
+	Portal     common.Address
+	// Token contract; zero for Ether deposits.
+	Token     common.Address
+	Depositor common.Address
+	// Amount of Ether or ERC-20 tokens.
+	Amount *big.Int
+	// Ids of the ERC-721 or ERC-1155 tokens.
+	TokenIds []*big.Int
+	// Amounts of each ERC-1155 token, in the same order as the ids.
+	Amounts []*big.Int
+	// Data sent to the token contract; only set for the ERC-721 and ERC-1155 deposits.
+	BaseLayerData []byte
+	// Data sent to the application.
+	ExecLayerData []byte
 }
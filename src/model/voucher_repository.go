@@ -3,14 +3,15 @@ package model
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
-	"fmt"
 	"log/slog"
-	"strconv"
-	"strings"
+	"math/big"
 
+	"github.com/calindra/rollups-server/src/migrations"
 	"github.com/calindra/rollups-server/src/util"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -21,24 +22,37 @@ type VoucherRepository struct {
 }
 
 type voucherRow struct {
-	Destination string `db:"destination"`
-	Payload     string `db:"payload"`
-	InputIndex  uint64 `db:"input_index"`
-	OutputIndex uint64 `db:"output_index"`
-	Executed    bool   `db:"executed"`
+	Destination string  `db:"destination"`
+	Value       *string `db:"value"`
+	Payload     string  `db:"payload"`
+	InputIndex  uint64  `db:"input_index"`
+	OutputIndex uint64  `db:"output_index"`
+	Executed    bool    `db:"executed"`
+
+	ExecutedTxHash *string `db:"executed_tx_hash"`
+	ExecutedBlock  *uint64 `db:"executed_block"`
+
+	SimulationSuccess     *bool   `db:"simulation_success"`
+	SimulationGasEstimate *uint64 `db:"simulation_gas_estimate"`
+	SimulationRevertData  *string `db:"simulation_revert_data"`
+	SimulationError       *string `db:"simulation_error"`
+
+	Contract        *string `db:"contract"`
+	Beneficiary     *string `db:"beneficiary"`
+	Amount          *string `db:"amount"`
+	TokenId         *string `db:"token_id"`
+	MethodSignature *string `db:"method_signature"`
+	ERCX            *string `db:"ercx"`
+	Label           *string `db:"label"`
+	AmountKey       *string `db:"amount_key"`
+
+	DecodedFunction *string `db:"decoded_function"`
+	DecodedArgs     *string `db:"decoded_args"`
 }
 
+// Create the tables by applying the pending schema migrations.
 func (c *VoucherRepository) CreateTables() error {
-	schema := `CREATE TABLE IF NOT EXISTS vouchers (
-		destination text,
-		payload 	text,
-		executed	BOOLEAN,
-		input_index  integer,
-		output_index integer,
-		PRIMARY KEY (input_index, output_index));`
-
-	// execute a query on the server
-	_, err := c.Db.Exec(schema)
+	_, err := migrations.Migrate(context.Background(), &c.Db, false)
 	return err
 }
 
@@ -47,18 +61,40 @@ func (c *VoucherRepository) CreateVoucher(
 ) (*ConvenienceVoucher, error) {
 	insertVoucher := `INSERT INTO vouchers (
 		destination,
+		value,
 		payload,
 		executed,
 		input_index,
-		output_index) VALUES ($1, $2, $3, $4, $5)`
-	_, err := c.Db.ExecContext(
+		output_index,
+		contract,
+		beneficiary,
+		amount,
+		token_id,
+		method_signature,
+		ercx,
+		decoded_function,
+		decoded_args,
+		label,
+		amount_key) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+	_, err := executor(ctx, &c.Db).ExecContext(
 		ctx,
 		insertVoucher,
 		voucher.Destination.Hex(),
+		encodeValue(voucher.Value),
 		voucher.Payload,
 		voucher.Executed,
 		voucher.InputIndex,
 		voucher.OutputIndex,
+		encodeAddress(voucher.Contract),
+		encodeAddress(voucher.Beneficiary),
+		encodeValue(voucher.Amount),
+		encodeValue(voucher.TokenId),
+		encodeText(voucher.MethodSignature),
+		encodeText(voucher.ERCX),
+		encodeText(voucher.DecodedFunction),
+		encodeText(string(voucher.DecodedArgs)),
+		encodeText(voucher.Label),
+		encodeAmountKey(voucher.Amount),
 	)
 	if err != nil {
 		return nil, err
@@ -69,18 +105,45 @@ func (c *VoucherRepository) CreateVoucher(
 func (c *VoucherRepository) UpdateVoucher(
 	ctx context.Context, voucher *ConvenienceVoucher,
 ) (*ConvenienceVoucher, error) {
+	// The simulation is cleared, so the updated voucher is simulated again
 	updateVoucher := `UPDATE vouchers SET 
 		destination = $1,
-		payload = $2,
-		executed = $3
-		WHERE input_index = $4 and output_index = $5`
+		value = $2,
+		payload = $3,
+		executed = $4,
+		simulation_success = NULL,
+		simulation_gas_estimate = NULL,
+		simulation_revert_data = NULL,
+		simulation_error = NULL,
+		contract = $5,
+		beneficiary = $6,
+		amount = $7,
+		token_id = $8,
+		method_signature = $9,
+		ercx = $10,
+		decoded_function = $11,
+		decoded_args = $12,
+		label = $13,
+		amount_key = $14
+		WHERE input_index = $15 and output_index = $16`
 
-	_, err := c.Db.ExecContext(
+	_, err := executor(ctx, &c.Db).ExecContext(
 		ctx,
 		updateVoucher,
 		voucher.Destination.Hex(),
+		encodeValue(voucher.Value),
 		voucher.Payload,
 		voucher.Executed,
+		encodeAddress(voucher.Contract),
+		encodeAddress(voucher.Beneficiary),
+		encodeValue(voucher.Amount),
+		encodeValue(voucher.TokenId),
+		encodeText(voucher.MethodSignature),
+		encodeText(voucher.ERCX),
+		encodeText(voucher.DecodedFunction),
+		encodeText(string(voucher.DecodedArgs)),
+		encodeText(voucher.Label),
+		encodeAmountKey(voucher.Amount),
 		voucher.InputIndex,
 		voucher.OutputIndex,
 	)
@@ -95,7 +158,7 @@ func (c *VoucherRepository) VoucherCount(
 	ctx context.Context,
 ) (uint64, error) {
 	var count int
-	err := c.Db.GetContext(ctx, &count, "SELECT count(*) FROM vouchers")
+	err := sqlx.GetContext(ctx, executor(ctx, &c.Db), &count, "SELECT count(*) FROM vouchers")
 	if err != nil {
 		return 0, nil
 	}
@@ -108,19 +171,14 @@ func (c *VoucherRepository) FindVoucherByInputAndOutputIndex(
 
 	query := `SELECT * FROM vouchers WHERE input_index = $1 and output_index = $2 LIMIT 1`
 
-	stmt, err := c.Db.Preparex(query)
-	if err != nil {
-		return nil, err
-	}
 	var row voucherRow
-	err = stmt.GetContext(ctx, &row, inputIndex, outputIndex)
+	err := sqlx.GetContext(ctx, executor(ctx, &c.Db), &row, query, inputIndex, outputIndex)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	defer stmt.Close()
 
 	p := convertToConvenienceVoucher(row)
 
@@ -132,13 +190,101 @@ func (c *VoucherRepository) UpdateExecuted(
 	executedValue bool,
 ) error {
 	query := `UPDATE vouchers SET executed = $1 WHERE input_index = $2 and output_index = $3`
-	_, err := c.Db.ExecContext(ctx, query, executedValue, inputIndex, outputIndex)
+	_, err := executor(ctx, &c.Db).ExecContext(ctx, query, executedValue, inputIndex, outputIndex)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// Mark the voucher as executed by the transaction in the block.
+// Return false when the voucher does not exist.
+func (c *VoucherRepository) MarkExecuted(
+	ctx context.Context, inputIndex uint64, outputIndex uint64,
+	txHash common.Hash, blockNumber uint64,
+) (bool, error) {
+	query := `UPDATE vouchers SET
+		executed = $1,
+		executed_tx_hash = $2,
+		executed_block = $3
+		WHERE input_index = $4 and output_index = $5`
+	res, err := executor(ctx, &c.Db).ExecContext(
+		ctx, query, true, txHash.Hex(), blockNumber, inputIndex, outputIndex,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Store the call of the voucher decoded with the ABI registry.
+func (c *VoucherRepository) SetDecodedCall(
+	ctx context.Context, inputIndex uint64, outputIndex uint64,
+	function string, args json.RawMessage,
+) error {
+	query := `UPDATE vouchers SET
+		decoded_function = $1,
+		decoded_args = $2
+		WHERE input_index = $3 and output_index = $4`
+	_, err := executor(ctx, &c.Db).ExecContext(
+		ctx, query, encodeText(function), encodeText(string(args)), inputIndex, outputIndex,
+	)
+	return err
+}
+
+// Delete the vouchers of the inputs from the given index on.
+func (c *VoucherRepository) DeleteFrom(ctx context.Context, inputIndex uint64) error {
+	_, err := executor(ctx, &c.Db).ExecContext(ctx,
+		`DELETE FROM vouchers WHERE input_index >= $1`, inputIndex)
+	return err
+}
+
+// Find the vouchers that were not simulated yet in output order.
+func (c *VoucherRepository) FindVouchersToSimulate(
+	ctx context.Context, limit int,
+) ([]ConvenienceVoucher, error) {
+	query := `SELECT * FROM vouchers WHERE simulation_success IS NULL
+		ORDER BY input_index ASC, output_index ASC LIMIT $1`
+	var rows []voucherRow
+	err := sqlx.SelectContext(ctx, executor(ctx, &c.Db), &rows, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	vouchers := make([]ConvenienceVoucher, len(rows))
+	for i, row := range rows {
+		vouchers[i] = convertToConvenienceVoucher(row)
+	}
+	return vouchers, nil
+}
+
+// Store the result of the voucher simulation.
+func (c *VoucherRepository) SetSimulation(
+	ctx context.Context, inputIndex uint64, outputIndex uint64,
+	simulation VoucherSimulation,
+) error {
+	query := `UPDATE vouchers SET
+		simulation_success = $1,
+		simulation_gas_estimate = $2,
+		simulation_revert_data = $3,
+		simulation_error = $4
+		WHERE input_index = $5 and output_index = $6`
+	_, err := executor(ctx, &c.Db).ExecContext(
+		ctx,
+		query,
+		simulation.Success,
+		simulation.GasEstimate,
+		hexutil.Encode(simulation.RevertData),
+		simulation.Error,
+		inputIndex,
+		outputIndex,
+	)
+	return err
+}
+
 func (c *VoucherRepository) Count(
 	ctx context.Context,
 	filter []*ConvenienceFilter,
@@ -150,13 +296,8 @@ func (c *VoucherRepository) Count(
 	}
 	query += where
 	slog.Debug("Query", "query", query, "args", args)
-	stmt, err := c.Db.Preparex(query)
-	if err != nil {
-		return 0, err
-	}
-	defer stmt.Close()
 	var count uint64
-	err = stmt.GetContext(ctx, &count, args...)
+	err = sqlx.GetContext(ctx, executor(ctx, &c.Db), &count, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -170,8 +311,9 @@ func (c *VoucherRepository) FindAllVouchers(
 	after *string,
 	before *string,
 	filter []*ConvenienceFilter,
+	options ...util.PageOption,
 ) (*util.PageResult[ConvenienceVoucher], error) {
-	total, err := c.Count(ctx, filter)
+	page, err := util.ComputePage(first, last, after, before, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -180,45 +322,69 @@ func (c *VoucherRepository) FindAllVouchers(
 	if err != nil {
 		return nil, err
 	}
-	query += where
-
-	query += ` ORDER BY input_index ASC, output_index ASC `
-	offset, limit, err := util.ComputePage(first, last, after, before, int(total))
+	pageQuery, args := compilePage(page, where, args, argsCount, "input_index", "output_index")
+	query += pageQuery
 
+	slog.Debug("Query", "query", query, "args", args)
+	var rows []voucherRow
+	err = sqlx.SelectContext(ctx, executor(ctx, &c.Db), &rows, query, args...)
 	if err != nil {
 		return nil, err
 	}
 
-	query += `LIMIT $` + strconv.Itoa(argsCount) + ` `
-	args = append(args, limit)
-	argsCount = argsCount + 1
-	query += `OFFSET $` + strconv.Itoa(argsCount) + ` `
-	args = append(args, offset)
+	vouchers := make([]ConvenienceVoucher, len(rows))
 
-	slog.Debug("Query", "query", query, "args", args, "total", total)
-	stmt, err := c.Db.Preparex(query)
-	if err != nil {
-		return nil, err
+	for i, row := range rows {
+		vouchers[i] = convertToConvenienceVoucher(row)
 	}
-	defer stmt.Close()
-	var rows []voucherRow
-	err = stmt.SelectContext(ctx, &rows, args...)
+
+	result := util.NewPageResult(page, vouchers, voucherCursor)
+	err = result.SetTotal(page, func() (uint64, error) {
+		return c.Count(ctx, filter)
+	})
 	if err != nil {
 		return nil, err
 	}
+	return result, nil
+}
 
-	vouchers := make([]ConvenienceVoucher, len(rows))
+// Encode the voucher value in decimal; nil is stored as null.
+func encodeValue(value *big.Int) *string {
+	if value == nil {
+		return nil
+	}
+	encoded := value.String()
+	return &encoded
+}
 
-	for i, row := range rows {
-		vouchers[i] = convertToConvenienceVoucher(row)
+// Encode the amount as its key, which sorts as text in the order of the amounts.
+func encodeAmountKey(amount *big.Int) *string {
+	if amount == nil {
+		return nil
 	}
+	key := amountKey(amount)
+	return &key
+}
 
-	pageResult := &util.PageResult[ConvenienceVoucher]{
-		Rows:   vouchers,
-		Total:  total,
-		Offset: uint64(offset),
+// Encode the address in the checksum format; the zero address is stored as null.
+func encodeAddress(address common.Address) *string {
+	if address == (common.Address{}) {
+		return nil
 	}
-	return pageResult, nil
+	encoded := address.Hex()
+	return &encoded
+}
+
+// Store the empty text as null.
+func encodeText(text string) *string {
+	if text == "" {
+		return nil
+	}
+	return &text
+}
+
+func voucherCursor(voucher ConvenienceVoucher) util.Cursor {
+	return util.Cursor{InputIndex: voucher.InputIndex, OutputIndex: voucher.OutputIndex}
 }
 
 func convertToConvenienceVoucher(row voucherRow) ConvenienceVoucher {
@@ -231,59 +397,81 @@ func convertToConvenienceVoucher(row voucherRow) ConvenienceVoucher {
 		OutputIndex: row.OutputIndex,
 		Executed:    row.Executed,
 	}
+	if row.ExecutedTxHash != nil {
+		voucher.ExecutedTxHash = common.HexToHash(*row.ExecutedTxHash)
+	}
+	if row.ExecutedBlock != nil {
+		voucher.ExecutedBlock = *row.ExecutedBlock
+	}
+	if row.Value != nil {
+		voucher.Value, _ = new(big.Int).SetString(*row.Value, 10)
+	}
+	if row.Contract != nil {
+		voucher.Contract = common.HexToAddress(*row.Contract)
+	}
+	if row.Beneficiary != nil {
+		voucher.Beneficiary = common.HexToAddress(*row.Beneficiary)
+	}
+	if row.Amount != nil {
+		voucher.Amount, _ = new(big.Int).SetString(*row.Amount, 10)
+	}
+	if row.TokenId != nil {
+		voucher.TokenId, _ = new(big.Int).SetString(*row.TokenId, 10)
+	}
+	if row.MethodSignature != nil {
+		voucher.MethodSignature = *row.MethodSignature
+	}
+	if row.ERCX != nil {
+		voucher.ERCX = *row.ERCX
+	}
+	if row.Label != nil {
+		voucher.Label = *row.Label
+	}
+	if row.DecodedFunction != nil {
+		voucher.DecodedFunction = *row.DecodedFunction
+	}
+	if row.DecodedArgs != nil {
+		voucher.DecodedArgs = json.RawMessage(*row.DecodedArgs)
+	}
+	if row.SimulationSuccess != nil {
+		voucher.Simulation = &VoucherSimulation{
+			Success: *row.SimulationSuccess,
+		}
+		if row.SimulationGasEstimate != nil {
+			voucher.Simulation.GasEstimate = *row.SimulationGasEstimate
+		}
+		if row.SimulationRevertData != nil {
+			voucher.Simulation.RevertData = common.FromHex(*row.SimulationRevertData)
+		}
+		if row.SimulationError != nil {
+			voucher.Simulation.Error = *row.SimulationError
+		}
+	}
 
 	return voucher
 }
 
+// Filterable columns of the vouchers table.
+var voucherColumns = filterColumns{
+	EXECUTED:         booleanColumn("executed"),
+	DESTINATION:      addressColumn("destination"),
+	INPUT_INDEX:      integerColumn("input_index"),
+	OUTPUT_INDEX:     integerColumn("output_index"),
+	CONTRACT:         addressColumn("contract"),
+	BENEFICIARY:      addressColumn("beneficiary"),
+	METHOD_SIGNATURE: textColumn("method_signature"),
+	ERCX:             textColumn("ercx"),
+	LABEL:            textColumn("label"),
+	AMOUNT:           amountColumn("amount_key"),
+}
+
 func transformToQuery(
 	filter []*ConvenienceFilter,
 ) (string, []interface{}, int, error) {
-	query := ""
-	if len(filter) > 0 {
-		query += "WHERE "
-	}
-	args := []interface{}{}
-	where := []string{}
-	count := 1
-	for _, filter := range filter {
-		if *filter.Field == EXECUTED {
-			if *filter.Eq == "true" {
-				where = append(where, fmt.Sprintf("executed = $%d ", count))
-				args = append(args, true)
-				count += 1
-			} else if *filter.Eq == FALSE {
-				where = append(where, fmt.Sprintf("executed = $%d ", count))
-				args = append(args, false)
-				count += 1
-			} else {
-				return "", nil, 0, fmt.Errorf(
-					"unexpected executed value %s", *filter.Eq,
-				)
-			}
-		} else if *filter.Field == DESTINATION {
-			if filter.Eq != nil {
-				where = append(where, fmt.Sprintf("destination = $%d ", count))
-				if !common.IsHexAddress(*filter.Eq) {
-					return "", nil, 0, fmt.Errorf("wrong address value")
-				}
-				args = append(args, *filter.Eq)
-				count += 1
-			} else {
-				return "", nil, 0, fmt.Errorf("operation not implemented")
-			}
-		} else if *filter.Field == INPUT_INDEX {
-			if filter.Eq != nil {
-				where = append(where, fmt.Sprintf("input_index = $%d ", count))
-				args = append(args, *filter.Eq)
-				count += 1
-			} else {
-				return "", nil, 0, fmt.Errorf("operation not implemented")
-			}
-		} else {
-			return "", nil, 0, fmt.Errorf("unexpected field %s", *filter.Field)
-		}
+	query, args, count, err := compileFilter(filter, voucherColumns)
+	if err != nil {
+		return "", nil, 0, err
 	}
-	query += strings.Join(where, " and ")
 	slog.Debug("Query", "query", query, "args", args)
 	return query, args, count, nil
 }
@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math/big"
 	"testing"
 
 	"github.com/calindra/rollups-server/src/util"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/suite"
 )
@@ -16,20 +16,30 @@ import (
 type VoucherRepositorySuite struct {
 	suite.Suite
 	repository *VoucherRepository
+	driver     string
+	database   *testDatabase
 }
 
 func (s *VoucherRepositorySuite) SetupTest() {
 	util.ConfigureLog(slog.LevelDebug)
-	db := sqlx.MustConnect("sqlite3", ":memory:")
+	var err error
+	s.database, err = openTestDatabase(s.driver)
+	s.Require().NoError(err)
 	s.repository = &VoucherRepository{
-		Db: *db,
+		Db: *s.database.Db,
 	}
-	err := s.repository.CreateTables()
+	err = s.repository.CreateTables()
 	s.NoError(err)
 }
 
+func (s *VoucherRepositorySuite) TearDownTest() {
+	s.database.Close()
+}
+
 func TestConvenienceRepositorySuite(t *testing.T) {
-	suite.Run(t, new(VoucherRepositorySuite))
+	runWithDrivers(t, func(driver string) *VoucherRepositorySuite {
+		return &VoucherRepositorySuite{driver: driver}
+	})
 }
 
 func (s *VoucherRepositorySuite) TestCreateVoucher() {
@@ -84,6 +94,68 @@ func (s *VoucherRepositorySuite) TestFindVoucherExecuted() {
 	s.Equal(true, voucher.Executed)
 }
 
+func (s *VoucherRepositorySuite) TestMarkExecuted() {
+	ctx := context.Background()
+	_, err := s.repository.CreateVoucher(ctx, &ConvenienceVoucher{
+		Destination: common.HexToAddress("0x26A61aF89053c847B4bd5084E2caFe7211874a29"),
+		Payload:     "0x0011",
+		InputIndex:  1,
+		OutputIndex: 2,
+	})
+	s.NoError(err)
+	txHash := common.HexToHash("0xabcd")
+	found, err := s.repository.MarkExecuted(ctx, 1, 2, txHash, 30)
+	s.NoError(err)
+	s.True(found)
+	found, err = s.repository.MarkExecuted(ctx, 1, 3, txHash, 30)
+	s.NoError(err)
+	s.False(found)
+	voucher, err := s.repository.FindVoucherByInputAndOutputIndex(ctx, 1, 2)
+	s.NoError(err)
+	s.True(voucher.Executed)
+	s.Equal(txHash, voucher.ExecutedTxHash)
+	s.Equal(uint64(30), voucher.ExecutedBlock)
+}
+
+func (s *VoucherRepositorySuite) TestSimulation() {
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := s.repository.CreateVoucher(ctx, &ConvenienceVoucher{
+			Destination: common.HexToAddress("0x26A61aF89053c847B4bd5084E2caFe7211874a29"),
+			Value:       big.NewInt(1_000_000_000_000_000_000),
+			Payload:     "0x0011",
+			InputIndex:  1,
+			OutputIndex: uint64(i),
+		})
+		s.NoError(err)
+	}
+	vouchers, err := s.repository.FindVouchersToSimulate(ctx, 10)
+	s.NoError(err)
+	s.Require().Len(vouchers, 2)
+	s.Nil(vouchers[0].Simulation)
+	s.Equal(big.NewInt(1_000_000_000_000_000_000), vouchers[0].Value)
+
+	err = s.repository.SetSimulation(ctx, 1, 0, VoucherSimulation{Success: true, GasEstimate: 21000})
+	s.NoError(err)
+	err = s.repository.SetSimulation(ctx, 1, 1, VoucherSimulation{
+		RevertData: []byte{0xe4, 0x50},
+		Error:      "execution reverted",
+	})
+	s.NoError(err)
+	vouchers, err = s.repository.FindVouchersToSimulate(ctx, 10)
+	s.NoError(err)
+	s.Empty(vouchers)
+
+	voucher, err := s.repository.FindVoucherByInputAndOutputIndex(ctx, 1, 0)
+	s.NoError(err)
+	s.Equal(&VoucherSimulation{Success: true, GasEstimate: 21000, RevertData: []byte{}}, voucher.Simulation)
+	voucher, err = s.repository.FindVoucherByInputAndOutputIndex(ctx, 1, 1)
+	s.NoError(err)
+	s.False(voucher.Simulation.Success)
+	s.Equal([]byte{0xe4, 0x50}, voucher.Simulation.RevertData)
+	s.Equal("execution reverted", voucher.Simulation.Error)
+}
+
 func (s *VoucherRepositorySuite) TestCountVoucher() {
 	ctx := context.Background()
 	_, err := s.repository.CreateVoucher(ctx, &ConvenienceVoucher{
@@ -154,7 +226,7 @@ func (s *VoucherRepositorySuite) TestPagination() {
 	s.Equal(0, int(vouchers.Rows[0].InputIndex))
 	s.Equal(9, int(vouchers.Rows[len(vouchers.Rows)-1].InputIndex))
 
-	after := util.EncodeCursor(10)
+	after := util.EncodeCursor(util.Cursor{InputIndex: 10})
 	vouchers, err = s.repository.FindAllVouchers(ctx, &first, nil, &after, nil, filters)
 	s.NoError(err)
 	s.Equal(10, len(vouchers.Rows))
@@ -168,7 +240,7 @@ func (s *VoucherRepositorySuite) TestPagination() {
 	s.Equal(20, int(vouchers.Rows[0].InputIndex))
 	s.Equal(29, int(vouchers.Rows[len(vouchers.Rows)-1].InputIndex))
 
-	before := util.EncodeCursor(20)
+	before := util.EncodeCursor(util.Cursor{InputIndex: 20})
 	vouchers, err = s.repository.FindAllVouchers(ctx, nil, &last, nil, &before, filters)
 	s.NoError(err)
 	s.Equal(10, len(vouchers.Rows))
@@ -176,6 +248,101 @@ func (s *VoucherRepositorySuite) TestPagination() {
 	s.Equal(19, int(vouchers.Rows[len(vouchers.Rows)-1].InputIndex))
 }
 
+func (s *VoucherRepositorySuite) TestPaginationWithTotal() {
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		_, err := s.repository.CreateVoucher(ctx, &ConvenienceVoucher{
+			Destination: common.HexToAddress("0x26A61aF89053c847B4bd5084E2caFe7211874a29"),
+			Payload:     "0x0011",
+			InputIndex:  uint64(i),
+			Executed:    i%2 == 0,
+		})
+		s.NoError(err)
+	}
+	field := "Executed"
+	value := "true"
+	filters := []*ConvenienceFilter{{Field: &field, Eq: &value}}
+	first := 1
+
+	vouchers, err := s.repository.FindAllVouchers(ctx, &first, nil, nil, nil, filters)
+	s.NoError(err)
+	s.Len(vouchers.Rows, 1)
+	s.Nil(vouchers.Total)
+
+	vouchers, err = s.repository.FindAllVouchers(
+		ctx, &first, nil, nil, nil, filters, util.WithTotal())
+	s.NoError(err)
+	s.Len(vouchers.Rows, 1)
+	s.Require().NotNil(vouchers.Total)
+	s.Equal(uint64(3), *vouchers.Total)
+}
+
+func (s *VoucherRepositorySuite) TestVoucherPageCursors() {
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 2; j++ {
+			_, err := s.repository.CreateVoucher(ctx, &ConvenienceVoucher{
+				Destination: common.HexToAddress("0x26A61aF89053c847B4bd5084E2caFe7211874a29"),
+				Payload:     "0x0011",
+				InputIndex:  uint64(i),
+				OutputIndex: uint64(j),
+			})
+			s.NoError(err)
+		}
+	}
+
+	first := 4
+	vouchers, err := s.repository.FindAllVouchers(ctx, &first, nil, nil, nil, nil)
+	s.NoError(err)
+	s.Len(vouchers.Rows, 4)
+	s.True(vouchers.HasNextPage)
+	s.False(vouchers.HasPreviousPage)
+	s.Equal(util.EncodeCursor(util.Cursor{InputIndex: 0, OutputIndex: 0}), *vouchers.StartCursor)
+	s.Equal(util.EncodeCursor(util.Cursor{InputIndex: 1, OutputIndex: 1}), *vouchers.EndCursor)
+
+	// new outputs do not shift the following pages
+	_, err = s.repository.CreateVoucher(ctx, &ConvenienceVoucher{
+		Destination: common.HexToAddress("0x26A61aF89053c847B4bd5084E2caFe7211874a29"),
+		Payload:     "0x0011",
+		InputIndex:  0,
+		OutputIndex: 2,
+	})
+	s.NoError(err)
+
+	vouchers, err = s.repository.FindAllVouchers(ctx, &first, nil, vouchers.EndCursor, nil, nil)
+	s.NoError(err)
+	s.Len(vouchers.Rows, 2)
+	s.Equal(2, int(vouchers.Rows[0].InputIndex))
+	s.Equal(0, int(vouchers.Rows[0].OutputIndex))
+	s.Equal(2, int(vouchers.Rows[1].InputIndex))
+	s.Equal(1, int(vouchers.Rows[1].OutputIndex))
+	s.False(vouchers.HasNextPage)
+	s.True(vouchers.HasPreviousPage)
+
+	last := 2
+	vouchers, err = s.repository.FindAllVouchers(ctx, nil, &last, nil, vouchers.StartCursor, nil)
+	s.NoError(err)
+	s.Len(vouchers.Rows, 2)
+	s.Equal(1, int(vouchers.Rows[0].InputIndex))
+	s.Equal(0, int(vouchers.Rows[0].OutputIndex))
+	s.Equal(1, int(vouchers.Rows[1].InputIndex))
+	s.Equal(1, int(vouchers.Rows[1].OutputIndex))
+	s.True(vouchers.HasNextPage)
+	s.True(vouchers.HasPreviousPage)
+
+	empty := 0
+	vouchers, err = s.repository.FindAllVouchers(ctx, &empty, nil, nil, nil, nil)
+	s.NoError(err)
+	s.Len(vouchers.Rows, 0)
+	s.True(vouchers.HasNextPage)
+	s.Nil(vouchers.StartCursor)
+	s.Nil(vouchers.EndCursor)
+
+	invalid := "invalid"
+	_, err = s.repository.FindAllVouchers(ctx, nil, nil, &invalid, nil, nil)
+	s.ErrorIs(err, util.ErrInvalidCursor)
+}
+
 func (s *VoucherRepositorySuite) TestWrongAddress() {
 	ctx := context.Background()
 	_, err := s.repository.CreateVoucher(ctx, &ConvenienceVoucher{
@@ -201,3 +368,119 @@ func (s *VoucherRepositorySuite) TestWrongAddress() {
 	}
 	s.Equal("wrong address value", err.Error())
 }
+
+func (s *VoucherRepositorySuite) TestFilterWithdrawals() {
+	ctx := context.Background()
+	token := common.HexToAddress("0xc6e7DF5E7b4f2A278906862b61205850344D4e7d")
+	beneficiary := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	vouchers := []ConvenienceVoucher{{
+		Destination:     token,
+		Payload:         "0xa9059cbb",
+		InputIndex:      1,
+		Contract:        token,
+		Beneficiary:     beneficiary,
+		Amount:          big.NewInt(7),
+		MethodSignature: "transfer(address,uint256)",
+		ERCX:            ERCX20,
+		Label:           "ERC-20 transfer",
+	}, {
+		Destination: beneficiary,
+		Value:       big.NewInt(100),
+		Payload:     "0x",
+		InputIndex:  2,
+		Beneficiary: beneficiary,
+		Amount:      big.NewInt(100),
+		ERCX:        ERCXEther,
+		Label:       "Ether withdrawal",
+	}, {
+		Destination: token,
+		Payload:     "0x0011",
+		InputIndex:  3,
+	}}
+	for _, voucher := range vouchers {
+		_, err := s.repository.CreateVoucher(ctx, &voucher)
+		s.Require().NoError(err)
+	}
+
+	voucher, err := s.repository.FindVoucherByInputAndOutputIndex(ctx, 1, 0)
+	s.Require().NoError(err)
+	s.Equal(vouchers[0], *voucher)
+	voucher, err = s.repository.FindVoucherByInputAndOutputIndex(ctx, 3, 0)
+	s.Require().NoError(err)
+	s.Equal(common.Address{}, voucher.Beneficiary)
+	s.Nil(voucher.Amount)
+	s.Empty(voucher.ERCX)
+
+	ercxField := ERCX
+	ercx := ERCX20
+	beneficiaryField := BENEFICIARY
+	beneficiaryValue := beneficiary.Hex()
+	executedField := EXECUTED
+	executed := FALSE
+	result, err := s.repository.FindAllVouchers(ctx, nil, nil, nil, nil, []*ConvenienceFilter{
+		{Field: &ercxField, Eq: &ercx},
+		{Field: &beneficiaryField, Eq: &beneficiaryValue},
+		{Field: &executedField, Eq: &executed},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(1, len(result.Rows))
+	s.Equal(uint64(1), result.Rows[0].InputIndex)
+
+	total, err := s.repository.Count(ctx, []*ConvenienceFilter{
+		{Field: &beneficiaryField, Eq: &beneficiaryValue},
+	})
+	s.NoError(err)
+	s.Equal(2, int(total))
+
+	// the amounts are compared as numbers, so 7 is less than 100
+	amountField := AMOUNT
+	minimum := "50"
+	result, err = s.repository.FindAllVouchers(ctx, nil, nil, nil, nil, []*ConvenienceFilter{
+		{Field: &amountField, Gt: &minimum},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(1, len(result.Rows))
+	s.Equal(uint64(2), result.Rows[0].InputIndex)
+
+	labelField := LABEL
+	label := "ERC-20 transfer"
+	maximum := "7"
+	result, err = s.repository.FindAllVouchers(ctx, nil, nil, nil, nil, []*ConvenienceFilter{
+		{Field: &labelField, Eq: &label},
+		{Field: &amountField, Lte: &maximum},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(1, len(result.Rows))
+	s.Equal(uint64(1), result.Rows[0].InputIndex)
+	s.Equal(label, result.Rows[0].Label)
+
+	negative := "-1"
+	_, err = s.repository.FindAllVouchers(ctx, nil, nil, nil, nil, []*ConvenienceFilter{
+		{Field: &amountField, Gt: &negative},
+	})
+	s.ErrorIs(err, ErrInvalidFilter)
+}
+
+func (s *VoucherRepositorySuite) TestSetDecodedCall() {
+	ctx := context.Background()
+	_, err := s.repository.CreateVoucher(ctx, &ConvenienceVoucher{
+		Destination:     common.HexToAddress("0x01"),
+		Payload:         "0x44df8e70",
+		InputIndex:      1,
+		OutputIndex:     2,
+		DecodedFunction: "burn",
+		DecodedArgs:     []byte(`{}`),
+	})
+	s.Require().NoError(err)
+	voucher, err := s.repository.FindVoucherByInputAndOutputIndex(ctx, 1, 2)
+	s.Require().NoError(err)
+	s.Equal("burn", voucher.DecodedFunction)
+	s.JSONEq(`{}`, string(voucher.DecodedArgs))
+
+	err = s.repository.SetDecodedCall(ctx, 1, 2, "mint", []byte(`{"amount":"7"}`))
+	s.Require().NoError(err)
+	voucher, err = s.repository.FindVoucherByInputAndOutputIndex(ctx, 1, 2)
+	s.Require().NoError(err)
+	s.Equal("mint", voucher.DecodedFunction)
+	s.JSONEq(`{"amount":"7"}`, string(voucher.DecodedArgs))
+}
@@ -0,0 +1,68 @@
+// This package contains the bindings for the output proofs OpenAPI spec.
+package proof
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen -config=oapi.yaml ../../api/proofs.yaml
+
+import (
+	"net/http"
+
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/labstack/echo/v4"
+)
+
+// Register the proofs API to echo
+func Register(e *echo.Echo, model *mdl.AppModel) {
+	var proofAPI ServerInterface = &ProofAPI{model}
+	RegisterHandlers(e, proofAPI)
+}
+
+// Shared struct for request handlers.
+type ProofAPI struct {
+	model *mdl.AppModel
+}
+
+// Handle GET requests to /proofs/{inputIndex}/{outputIndex}.
+func (a *ProofAPI) GetProof(c echo.Context, inputIndex uint64, outputIndex uint64) error {
+	proof, err := a.model.GetProof(inputIndex, outputIndex)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	if proof == nil {
+		return c.String(http.StatusNotFound, "proof not found")
+	}
+	resp := convertProof(*proof)
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Convert model proof to API type.
+func convertProof(proof mdl.Proof) Proof {
+	return Proof{
+		InputIndex:  proof.InputIndex,
+		OutputIndex: proof.OutputIndex,
+		Output:      hexutil.Encode(proof.Output),
+		OutputHash:  proof.OutputHash.Hex(),
+		Validity: OutputValidityProof{
+			InputRange: InputRange{
+				FirstIndex: proof.FirstInputIndex,
+				LastIndex:  proof.LastInputIndex,
+			},
+			InputIndexWithinEpoch:            proof.InputIndexWithinEpoch,
+			OutputIndexWithinInput:           proof.OutputIndex,
+			OutputHashesRootHash:             proof.OutputHashesRootHash.Hex(),
+			OutputsEpochRootHash:             proof.OutputsEpochRootHash.Hex(),
+			MachineStateHash:                 proof.MachineStateHash.Hex(),
+			OutputHashInOutputHashesSiblings: convertHashes(proof.OutputHashInOutputHashesSiblings),
+			OutputHashesInEpochSiblings:      convertHashes(proof.OutputHashesInEpochSiblings),
+		},
+	}
+}
+
+func convertHashes(hashes []common.Hash) []Hash {
+	converted := make([]Hash, len(hashes))
+	for i, hash := range hashes {
+		converted[i] = hash.Hex()
+	}
+	return converted
+}
@@ -0,0 +1,156 @@
+package proof
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/calindra/rollups-server/src/merkle"
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+const testTimeout = 5 * time.Second
+
+type ProofSuite struct {
+	suite.Suite
+	model   *mdl.AppModel
+	server  *httptest.Server
+	client  *ClientWithResponses
+	tempDir string
+}
+
+func (s *ProofSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	tempDir, err := os.MkdirTemp("", "")
+	s.NoError(err)
+	s.tempDir = tempDir
+	db := sqlx.MustConnect("sqlite3", path.Join(tempDir, "proof.sqlite3"))
+	s.model = mdl.NewAppModel(nil, db)
+	e := echo.New()
+	Register(e, s.model)
+	s.server = httptest.NewServer(e)
+	client, err := NewClientWithResponses(s.server.URL)
+	s.NoError(err)
+	s.client = client
+}
+
+func (s *ProofSuite) TearDownTest() {
+	s.server.Close()
+	os.RemoveAll(s.tempDir)
+}
+
+func TestProofSuite(t *testing.T) {
+	suite.Run(t, new(ProofSuite))
+}
+
+// Recompute the epoch root from the output and the siblings in the proof.
+func epochRoot(s *ProofSuite, proof *Proof) string {
+	output, err := hexutil.Decode(proof.Output)
+	s.Require().NoError(err)
+	outputHash := crypto.Keccak256Hash(output)
+	s.Equal(outputHash.Hex(), proof.OutputHash)
+	validity := proof.Validity
+	outputsRoot := merkle.RootFromSiblings(
+		outputHash, validity.OutputIndexWithinInput, hashes(s, validity.OutputHashInOutputHashesSiblings))
+	s.Equal(validity.OutputHashesRootHash, outputsRoot.Hex())
+	return merkle.RootFromSiblings(
+		outputsRoot, validity.InputIndexWithinEpoch, hashes(s, validity.OutputHashesInEpochSiblings)).Hex()
+}
+
+func hashes(s *ProofSuite, encoded []Hash) []common.Hash {
+	decoded := make([]common.Hash, len(encoded))
+	for i, hash := range encoded {
+		bytes, err := hexutil.Decode(hash)
+		s.Require().NoError(err)
+		decoded[i] = common.BytesToHash(bytes)
+	}
+	return decoded
+}
+
+func (s *ProofSuite) TestGetProof() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	err := s.model.AddAdvanceInput(common.Address{}, []byte{0xde, 0xad}, 1, time.Now(), 0)
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	destination := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	index, err := s.model.AddVoucher(destination, big.NewInt(1), []byte{0xbe, 0xef})
+	s.Require().NoError(err)
+	s.Equal(0, index)
+	index, err = s.model.AddNotice([]byte{0xca, 0xfe})
+	s.Require().NoError(err)
+	s.Equal(1, index)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+
+	// the proofs are only available after the epoch is closed
+	resp, err := s.client.GetProofWithResponse(ctx, 0, 0)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+	epoch, err := s.model.CloseEpoch()
+	s.Require().NoError(err)
+	s.Require().NotNil(epoch)
+	s.Equal(mdl.EpochStatusProved, epoch.Status)
+
+	voucher, err := s.client.GetProofWithResponse(ctx, 0, 0)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, voucher.StatusCode())
+	expected, err := mdl.EncodeVoucherOutput(destination, big.NewInt(1), []byte{0xbe, 0xef})
+	s.Require().NoError(err)
+	s.Equal(hexutil.Encode(expected), voucher.JSON200.Output)
+	s.Equal(InputRange{FirstIndex: 0, LastIndex: 0}, voucher.JSON200.Validity.InputRange)
+	s.Len(voucher.JSON200.Validity.OutputHashInOutputHashesSiblings, merkle.OutputsHeight)
+	s.Len(voucher.JSON200.Validity.OutputHashesInEpochSiblings, merkle.InputsHeight)
+
+	notice, err := s.client.GetProofWithResponse(ctx, 0, 1)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, notice.StatusCode())
+	expected, err = mdl.EncodeNoticeOutput([]byte{0xca, 0xfe})
+	s.Require().NoError(err)
+	s.Equal(hexutil.Encode(expected), notice.JSON200.Output)
+
+	s.Equal(voucher.JSON200.Validity.OutputsEpochRootHash, epochRoot(s, voucher.JSON200))
+	s.Equal(notice.JSON200.Validity.OutputsEpochRootHash, epochRoot(s, notice.JSON200))
+}
+
+func (s *ProofSuite) TestProofNotFound() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	resp, err := s.client.GetProofWithResponse(ctx, 0, 0)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+}
+
+func (s *ProofSuite) TestRejectedInputHasNoProofs() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	err := s.model.AddAdvanceInput(common.Address{}, []byte{0xde, 0xad}, 1, time.Now(), 0)
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.AddNotice([]byte{0xca, 0xfe})
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(false)
+	s.Require().NoError(err)
+	_, err = s.model.CloseEpoch()
+	s.Require().NoError(err)
+
+	resp, err := s.client.GetProofWithResponse(ctx, 0, 0)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+}
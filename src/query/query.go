@@ -0,0 +1,382 @@
+// This package contains the bindings for the query REST API OpenAPI spec.
+package query
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen -config=oapi.yaml ../../api/queries.yaml
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/services"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/labstack/echo/v4"
+)
+
+// Register the query API to echo.
+func Register(e *echo.Echo, model *mdl.AppModel, service *services.ConvenienceService) {
+	var queryAPI ServerInterface = &QueryAPI{model, service}
+	RegisterHandlers(e, queryAPI)
+}
+
+// Shared struct for request handlers.
+type QueryAPI struct {
+	model   *mdl.AppModel
+	service *services.ConvenienceService
+}
+
+// Handle GET requests to /inputs.
+func (a *QueryAPI) GetInputs(c echo.Context, params GetInputsParams) error {
+	filter := convertFilters(params.Filter)
+	page, err := a.model.InputRepository.FindAll(
+		c.Request().Context(), params.First, params.Last, params.After, params.Before, filter,
+		pageOptions(params.Total)...)
+	if err != nil {
+		return queryError(c, err)
+	}
+	rows, err := a.convertInputs(c.Request().Context(), page.Rows)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	resp := InputPage{Rows: rows, PageInfo: convertPageInfo(page)}
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Handle GET requests to /inputs/{index}.
+func (a *QueryAPI) GetInput(c echo.Context, index uint64) error {
+	input, err := a.model.InputRepository.FindByIndex(c.Request().Context(), int(index))
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	if input == nil {
+		return c.String(http.StatusNotFound, "input not found")
+	}
+	rows, err := a.convertInputs(c.Request().Context(), []mdl.AdvanceInput{*input})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, &rows[0])
+}
+
+// Handle GET requests to /inputs/{inputIndex}/vouchers/{outputIndex}.
+func (a *QueryAPI) GetVoucher(c echo.Context, inputIndex uint64, outputIndex uint64) error {
+	voucher, err := a.service.FindVoucherByInputAndOutputIndex(
+		c.Request().Context(), inputIndex, outputIndex)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, err.Error())
+	}
+	if voucher == nil {
+		return c.String(http.StatusNotFound, "voucher not found")
+	}
+	resp := convertVoucher(*voucher)
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Handle GET requests to /vouchers.
+func (a *QueryAPI) GetVouchers(c echo.Context, params GetVouchersParams) error {
+	page, err := a.service.FindAllVouchers(c.Request().Context(),
+		params.First, params.Last, params.After, params.Before, convertFilters(params.Filter),
+		pageOptions(params.Total)...)
+	if err != nil {
+		return queryError(c, err)
+	}
+	resp := VoucherPage{Rows: convertVouchers(page.Rows), PageInfo: convertPageInfo(page)}
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Handle GET requests to /notices.
+func (a *QueryAPI) GetNotices(c echo.Context, params GetNoticesParams) error {
+	page, err := a.service.FindAllNotices(c.Request().Context(),
+		params.First, params.Last, params.After, params.Before, convertFilters(params.Filter),
+		pageOptions(params.Total)...)
+	if err != nil {
+		return queryError(c, err)
+	}
+	resp := NoticePage{Rows: convertNotices(page.Rows), PageInfo: convertPageInfo(page)}
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Handle GET requests to /reports.
+func (a *QueryAPI) GetReports(c echo.Context, params GetReportsParams) error {
+	page, err := a.model.ReportRepository.FindAll(
+		params.First, params.Last, params.After, params.Before, convertFilters(params.Filter),
+		pageOptions(params.Total)...)
+	if err != nil {
+		return queryError(c, err)
+	}
+	resp := ReportPage{Rows: convertReports(page.Rows), PageInfo: convertPageInfo(page)}
+	return c.JSON(http.StatusOK, &resp)
+}
+
+// Count the total of the page when it is requested.
+func pageOptions(total *bool) []util.PageOption {
+	if total != nil && *total {
+		return []util.PageOption{util.WithTotal()}
+	}
+	return nil
+}
+
+// Respond with a bad request when the pagination parameters or the filter are invalid.
+func queryError(c echo.Context, err error) error {
+	if errors.Is(err, mdl.ErrInvalidFilter) ||
+		errors.Is(err, util.ErrInvalidCursor) ||
+		errors.Is(err, util.ErrMixedPagination) ||
+		errors.Is(err, util.ErrInvalidLimit) {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	return c.String(http.StatusInternalServerError, err.Error())
+}
+
+// Outputs of a kind of a page of inputs, grouped by the input index.
+type inputOutputs[T any] struct {
+	rows map[uint64][]T
+	// The outputs are read up to the page limit; when it is hit, the inputs from this index on
+	// may have more outputs than the listed ones.
+	truncatedFrom *uint64
+}
+
+func groupOutputs[T any](page *util.PageResult[T], inputIndex func(T) uint64) inputOutputs[T] {
+	outputs := inputOutputs[T]{rows: make(map[uint64][]T)}
+	for _, row := range page.Rows {
+		index := inputIndex(row)
+		outputs.rows[index] = append(outputs.rows[index], row)
+	}
+	if page.HasNextPage && len(page.Rows) > 0 {
+		last := inputIndex(page.Rows[len(page.Rows)-1])
+		outputs.truncatedFrom = &last
+	}
+	return outputs
+}
+
+// Get the outputs of the input and whether it may have more.
+func (o inputOutputs[T]) get(inputIndex uint64) ([]T, bool) {
+	return o.rows[inputIndex], o.truncatedFrom != nil && inputIndex >= *o.truncatedFrom
+}
+
+// Convert the model inputs to the API type, with their outputs.
+// The outputs of each kind are read for all the inputs in a single query.
+func (a *QueryAPI) convertInputs(ctx context.Context, inputs []mdl.AdvanceInput) ([]Input, error) {
+	converted := make([]Input, len(inputs))
+	if len(inputs) == 0 {
+		return converted, nil
+	}
+	field := mdl.INPUT_INDEX
+	indices := make([]string, len(inputs))
+	for i, input := range inputs {
+		indices[i] = fmt.Sprint(input.Index)
+	}
+	filter := []*mdl.ConvenienceFilter{{Field: &field, In: convertValues(indices)}}
+	vouchers, err := a.service.FindAllVouchers(ctx, nil, nil, nil, nil, filter)
+	if err != nil {
+		return nil, err
+	}
+	notices, err := a.service.FindAllNotices(ctx, nil, nil, nil, nil, filter)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := a.model.ReportRepository.FindAll(nil, nil, nil, nil, filter)
+	if err != nil {
+		return nil, err
+	}
+	voucherOutputs := groupOutputs(vouchers, func(v mdl.ConvenienceVoucher) uint64 {
+		return v.InputIndex
+	})
+	noticeOutputs := groupOutputs(notices, func(n mdl.ConvenienceNotice) uint64 {
+		return n.InputIndex
+	})
+	reportOutputs := groupOutputs(reports, func(r mdl.Report) uint64 {
+		return uint64(r.InputIndex)
+	})
+	for i, input := range inputs {
+		index := uint64(input.Index)
+		inputVouchers, moreVouchers := voucherOutputs.get(index)
+		inputNotices, moreNotices := noticeOutputs.get(index)
+		inputReports, moreReports := reportOutputs.get(index)
+		converted[i] = Input{
+			Index:           index,
+			Status:          convertStatus(input.Status),
+			MsgSender:       input.MsgSender.Hex(),
+			Payload:         hexutil.Encode(input.Payload),
+			BlockNumber:     input.BlockNumber,
+			BlockTimestamp:  input.BlockTimestamp.Unix(),
+			EpochIndex:      input.EpochIndex,
+			Vouchers:        convertVouchers(inputVouchers),
+			Notices:         convertNotices(inputNotices),
+			Reports:         convertReports(inputReports),
+			HasMoreVouchers: moreVouchers,
+			HasMoreNotices:  moreNotices,
+			HasMoreReports:  moreReports,
+		}
+		if len(input.Exception) > 0 {
+			exception := hexutil.Encode(input.Exception)
+			converted[i].Exception = &exception
+		}
+	}
+	return converted, nil
+}
+
+func convertStatus(status mdl.CompletionStatus) InputStatus {
+	switch status {
+	case mdl.CompletionStatusAccepted:
+		return Accepted
+	case mdl.CompletionStatusRejected:
+		return Rejected
+	case mdl.CompletionStatusException:
+		return Exception
+	default:
+		return Unprocessed
+	}
+}
+
+func convertVouchers(vouchers []mdl.ConvenienceVoucher) []Voucher {
+	converted := make([]Voucher, len(vouchers))
+	for i, voucher := range vouchers {
+		converted[i] = convertVoucher(voucher)
+	}
+	return converted
+}
+
+// Convert the model voucher to the API type, with its decoded forms.
+func convertVoucher(voucher mdl.ConvenienceVoucher) Voucher {
+	converted := Voucher{
+		InputIndex:  voucher.InputIndex,
+		OutputIndex: voucher.OutputIndex,
+		Destination: voucher.Destination.Hex(),
+		Value:       "0",
+		Payload:     voucher.Payload,
+		Executed:    voucher.Executed,
+	}
+	if voucher.Value != nil {
+		converted.Value = voucher.Value.String()
+	}
+	if voucher.ExecutedTxHash != (common.Hash{}) {
+		hash := voucher.ExecutedTxHash.Hex()
+		converted.ExecutedTransactionHash = &hash
+	}
+	if voucher.ERCX != "" {
+		converted.Withdrawal = convertWithdrawal(voucher)
+	}
+	if voucher.DecodedFunction != "" {
+		converted.DecodedFunction = &voucher.DecodedFunction
+		converted.DecodedArgs = convertDecoded(voucher.DecodedArgs)
+	}
+	return converted
+}
+
+func convertWithdrawal(voucher mdl.ConvenienceVoucher) *Withdrawal {
+	withdrawal := &Withdrawal{
+		Ercx:        WithdrawalErcx(voucher.ERCX),
+		Beneficiary: voucher.Beneficiary.Hex(),
+	}
+	if voucher.Contract != (common.Address{}) {
+		contract := voucher.Contract.Hex()
+		withdrawal.Contract = &contract
+	}
+	if voucher.Amount != nil {
+		amount := voucher.Amount.String()
+		withdrawal.Amount = &amount
+	}
+	if voucher.TokenId != nil {
+		tokenId := voucher.TokenId.String()
+		withdrawal.TokenId = &tokenId
+	}
+	if voucher.MethodSignature != "" {
+		withdrawal.MethodSignature = &voucher.MethodSignature
+	}
+	if voucher.Label != "" {
+		withdrawal.Label = &voucher.Label
+	}
+	return withdrawal
+}
+
+func convertNotices(notices []mdl.ConvenienceNotice) []Notice {
+	converted := make([]Notice, len(notices))
+	for i, notice := range notices {
+		converted[i] = Notice{
+			InputIndex:  notice.InputIndex,
+			OutputIndex: notice.OutputIndex,
+			Payload:     notice.Payload,
+		}
+		if notice.DecodedSchema != "" {
+			converted[i].DecodedSchema = &notice.DecodedSchema
+			converted[i].DecodedFields = convertDecoded(notice.DecodedFields)
+		}
+	}
+	return converted
+}
+
+func convertReports(reports []mdl.Report) []Report {
+	converted := make([]Report, len(reports))
+	for i, report := range reports {
+		converted[i] = Report{
+			InputIndex: uint64(report.InputIndex),
+			Index:      uint64(report.Index),
+			Payload:    hexutil.Encode(report.Payload),
+		}
+	}
+	return converted
+}
+
+// Convert the decoded JSON object; return nil if it is not an object.
+func convertDecoded(data []byte) *map[string]any {
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded == nil {
+		return nil
+	}
+	return &decoded
+}
+
+func convertPageInfo[T any](page *util.PageResult[T]) PageInfo {
+	return PageInfo{
+		StartCursor:     page.StartCursor,
+		EndCursor:       page.EndCursor,
+		HasNextPage:     page.HasNextPage,
+		HasPreviousPage: page.HasPreviousPage,
+		Total:           page.Total,
+	}
+}
+
+func convertFilters(filters *[]ConvenientFilter) []*mdl.ConvenienceFilter {
+	if filters == nil {
+		return nil
+	}
+	converted := make([]*mdl.ConvenienceFilter, len(*filters))
+	for i, filter := range *filters {
+		converted[i] = convertFilter(filter)
+	}
+	return converted
+}
+
+func convertFilter(filter ConvenientFilter) *mdl.ConvenienceFilter {
+	converted := &mdl.ConvenienceFilter{
+		Field: filter.Field,
+		Eq:    filter.Eq,
+		Ne:    filter.Ne,
+		Gt:    filter.Gt,
+		Gte:   filter.Gte,
+		Lt:    filter.Lt,
+		Lte:   filter.Lte,
+		And:   convertFilters(filter.And),
+		Or:    convertFilters(filter.Or),
+	}
+	if filter.In != nil {
+		converted.In = convertValues(*filter.In)
+	}
+	if filter.Nin != nil {
+		converted.Nin = convertValues(*filter.Nin)
+	}
+	return converted
+}
+
+func convertValues(values []string) []*string {
+	converted := make([]*string, len(values))
+	for i := range values {
+		converted[i] = &values[i]
+	}
+	return converted
+}
@@ -0,0 +1,305 @@
+package query
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/calindra/rollups-server/src/container"
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+const testTimeout = 5 * time.Second
+
+var (
+	testSender      = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	testDestination = common.HexToAddress("0xfafa")
+	testTime        = time.Unix(1700000000, 0)
+)
+
+type QuerySuite struct {
+	suite.Suite
+	model   *mdl.AppModel
+	server  *httptest.Server
+	client  *ClientWithResponses
+	tempDir string
+}
+
+func (s *QuerySuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	tempDir, err := os.MkdirTemp("", "")
+	s.NoError(err)
+	s.tempDir = tempDir
+	db := sqlx.MustConnect("sqlite3", path.Join(tempDir, "query.sqlite3"))
+	c := container.NewContainer(*db)
+	s.model = mdl.NewAppModel(c.GetOutputDecoder(), db)
+	e := echo.New()
+	Register(e, s.model, c.GetConvenienceService())
+	s.server = httptest.NewServer(e)
+	client, err := NewClientWithResponses(s.server.URL)
+	s.NoError(err)
+	s.client = client
+}
+
+func (s *QuerySuite) TearDownTest() {
+	s.server.Close()
+	os.RemoveAll(s.tempDir)
+}
+
+func TestQuerySuite(t *testing.T) {
+	suite.Run(t, new(QuerySuite))
+}
+
+// Add two processed inputs and an unprocessed input.
+// The first input has a voucher, a notice and a report; the second has a notice.
+func (s *QuerySuite) addInputs() {
+	for i := 0; i < 3; i++ {
+		err := s.model.AddAdvanceInput(testSender, []byte{byte(i)}, uint64(10+i), testTime, i)
+		s.Require().NoError(err)
+	}
+	_, err := s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.AddVoucher(testDestination, big.NewInt(5), []byte{0xbe, 0xef})
+	s.Require().NoError(err)
+	_, err = s.model.AddNotice([]byte{0xaa})
+	s.Require().NoError(err)
+	s.Require().NoError(s.model.AddReport([]byte{0xcc}))
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.AddNotice([]byte{0xbb})
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+}
+
+func (s *QuerySuite) TestGetInputs() {
+	s.addInputs()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	first := 2
+	resp, err := s.client.GetInputsWithResponse(ctx, &GetInputsParams{First: &first})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), string(resp.Body))
+	page := resp.JSON200
+	s.Require().Len(page.Rows, 2)
+	s.True(page.PageInfo.HasNextPage)
+	s.False(page.PageInfo.HasPreviousPage)
+
+	input := page.Rows[0]
+	s.Equal(uint64(0), input.Index)
+	s.Equal(Accepted, input.Status)
+	s.Equal(testSender.Hex(), input.MsgSender)
+	s.Equal("0x00", input.Payload)
+	s.Equal(uint64(10), input.BlockNumber)
+	s.Equal(testTime.Unix(), input.BlockTimestamp)
+	s.Require().Len(input.Vouchers, 1)
+	s.Equal(testDestination.Hex(), input.Vouchers[0].Destination)
+	s.Equal("0xbeef", input.Vouchers[0].Payload)
+	s.Equal("5", input.Vouchers[0].Value)
+	s.Require().Len(input.Notices, 1)
+	s.Equal(uint64(1), input.Notices[0].OutputIndex)
+	s.Require().Len(input.Reports, 1)
+	s.Equal("0xcc", input.Reports[0].Payload)
+
+	resp, err = s.client.GetInputsWithResponse(ctx, &GetInputsParams{After: page.PageInfo.EndCursor})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), string(resp.Body))
+	s.Require().Len(resp.JSON200.Rows, 1)
+	s.Equal(uint64(2), resp.JSON200.Rows[0].Index)
+	s.Equal(Unprocessed, resp.JSON200.Rows[0].Status)
+	s.Empty(resp.JSON200.Rows[0].Vouchers)
+}
+
+func (s *QuerySuite) TestGetInputsTruncatedOutputs() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		err := s.model.AddAdvanceInput(testSender, []byte{byte(i)}, uint64(10+i), testTime, i)
+		s.Require().NoError(err)
+	}
+	_, err := s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.AddNotice([]byte{0xaa})
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	for i := 0; i <= util.DefaultPaginationLimit; i++ {
+		s.Require().NoError(s.model.AddReport([]byte{0xcc}))
+	}
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	s.Require().NoError(s.model.AddReport([]byte{0xdd}))
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+
+	resp, err := s.client.GetInputsWithResponse(ctx, &GetInputsParams{})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), string(resp.Body))
+	rows := resp.JSON200.Rows
+	s.Require().Len(rows, 3)
+	s.Len(rows[0].Notices, 1)
+	s.Empty(rows[0].Reports)
+	s.False(rows[0].HasMoreNotices)
+	s.False(rows[0].HasMoreReports)
+	s.Len(rows[1].Reports, util.DefaultPaginationLimit)
+	s.True(rows[1].HasMoreReports)
+	s.False(rows[1].HasMoreVouchers)
+	// the reports of the next input were not read
+	s.Empty(rows[2].Reports)
+	s.True(rows[2].HasMoreReports)
+
+	input, err := s.client.GetInputWithResponse(ctx, 2)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, input.StatusCode(), string(input.Body))
+	s.Require().Len(input.JSON200.Reports, 1)
+	s.Equal("0xdd", input.JSON200.Reports[0].Payload)
+	s.False(input.JSON200.HasMoreReports)
+}
+
+func (s *QuerySuite) TestGetInput() {
+	s.addInputs()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	resp, err := s.client.GetInputWithResponse(ctx, 1)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), string(resp.Body))
+	s.Equal(uint64(1), resp.JSON200.Index)
+	s.Empty(resp.JSON200.Vouchers)
+	s.Require().Len(resp.JSON200.Notices, 1)
+	s.Equal("0xbb", resp.JSON200.Notices[0].Payload)
+
+	resp, err = s.client.GetInputWithResponse(ctx, 9)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+	s.Equal("input not found", string(resp.Body))
+}
+
+func (s *QuerySuite) TestGetVoucher() {
+	s.addInputs()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	resp, err := s.client.GetVoucherWithResponse(ctx, 0, 0)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), string(resp.Body))
+	voucher := resp.JSON200
+	s.Equal(testDestination.Hex(), voucher.Destination)
+	s.False(voucher.Executed)
+	s.Nil(voucher.Withdrawal)
+	s.Nil(voucher.DecodedFunction)
+
+	resp, err = s.client.GetVoucherWithResponse(ctx, 0, 1)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode())
+	s.Equal("voucher not found", string(resp.Body))
+}
+
+func (s *QuerySuite) TestGetVouchersFilter() {
+	s.addInputs()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	field := "Destination"
+	value := testDestination.Hex()
+	filter := Filter{{Field: &field, Eq: &value}}
+	resp, err := s.client.GetVouchersWithResponse(ctx, &GetVouchersParams{Filter: &filter})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), string(resp.Body))
+	s.Require().Len(resp.JSON200.Rows, 1)
+	s.Equal(uint64(0), resp.JSON200.Rows[0].InputIndex)
+
+	field = "Executed"
+	value = "true"
+	resp, err = s.client.GetVouchersWithResponse(ctx, &GetVouchersParams{Filter: &filter})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), string(resp.Body))
+	s.Empty(resp.JSON200.Rows)
+
+	field = "Unknown"
+	resp, err = s.client.GetVouchersWithResponse(ctx, &GetVouchersParams{Filter: &filter})
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode(), string(resp.Body))
+}
+
+func (s *QuerySuite) TestGetNoticesPagination() {
+	s.addInputs()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	last := 1
+	resp, err := s.client.GetNoticesWithResponse(ctx, &GetNoticesParams{Last: &last})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), string(resp.Body))
+	s.Require().Len(resp.JSON200.Rows, 1)
+	s.Equal(uint64(1), resp.JSON200.Rows[0].InputIndex)
+	s.True(resp.JSON200.PageInfo.HasPreviousPage)
+
+	params := &GetNoticesParams{Last: &last, Before: resp.JSON200.PageInfo.StartCursor}
+	resp, err = s.client.GetNoticesWithResponse(ctx, params)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), string(resp.Body))
+	s.Require().Len(resp.JSON200.Rows, 1)
+	s.Equal(uint64(0), resp.JSON200.Rows[0].InputIndex)
+	s.Equal("0xaa", resp.JSON200.Rows[0].Payload)
+	s.False(resp.JSON200.PageInfo.HasPreviousPage)
+
+	invalid := "invalid"
+	resp, err = s.client.GetNoticesWithResponse(ctx, &GetNoticesParams{After: &invalid})
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode(), string(resp.Body))
+
+	resp, err = s.client.GetNoticesWithResponse(ctx, &GetNoticesParams{First: &last, Last: &last})
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode(), string(resp.Body))
+}
+
+func (s *QuerySuite) TestGetReports() {
+	s.addInputs()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	resp, err := s.client.GetReportsWithResponse(ctx, &GetReportsParams{})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), string(resp.Body))
+	s.Require().Len(resp.JSON200.Rows, 1)
+	report := resp.JSON200.Rows[0]
+	s.Equal(uint64(0), report.InputIndex)
+	s.Equal(uint64(0), report.Index)
+	s.Equal("0xcc", report.Payload)
+	s.False(resp.JSON200.PageInfo.HasNextPage)
+}
+
+func (s *QuerySuite) TestGetNoticesTotal() {
+	s.addInputs()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	first := 1
+	resp, err := s.client.GetNoticesWithResponse(ctx, &GetNoticesParams{First: &first})
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), string(resp.Body))
+	s.Nil(resp.JSON200.PageInfo.Total)
+
+	total := true
+	params := &GetNoticesParams{First: &first, Total: &total}
+	resp, err = s.client.GetNoticesWithResponse(ctx, params)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode(), string(resp.Body))
+	s.Len(resp.JSON200.Rows, 1)
+	s.Require().NotNil(resp.JSON200.PageInfo.Total)
+	s.Equal(uint64(2), *resp.JSON200.PageInfo.Total)
+}
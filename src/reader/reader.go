@@ -0,0 +1,289 @@
+// This package contains the GraphQL API compatible with the Cartesi rollups reader.
+package reader
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/services"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// Register the GraphQL API to echo.
+func Register(e *echo.Echo, model *mdl.AppModel, service *services.ConvenienceService) {
+	resolver := &queryResolver{model, service}
+	parsed := graphql.MustParseSchema(schema, resolver, graphql.UseStringDescriptions())
+	e.POST("/graphql", echo.WrapHandler(&relay.Handler{Schema: parsed}))
+}
+
+// Resolver of the root query, which is shared by the other resolvers.
+type queryResolver struct {
+	model   *mdl.AppModel
+	service *services.ConvenienceService
+}
+
+// Pagination arguments of the connections.
+type pageArgs struct {
+	First  *int32
+	Last   *int32
+	After  *string
+	Before *string
+}
+
+// Convert the limits to the type of the repositories.
+func (a pageArgs) limits() (*int, *int) {
+	return toInt(a.First), toInt(a.Last)
+}
+
+func toInt(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	converted := int(*value)
+	return &converted
+}
+
+type inputFilter struct {
+	IndexLowerThan   *int32
+	IndexGreaterThan *int32
+}
+
+// Filter of the vouchers and notices, which is converted to a model.ConvenienceFilter.
+type convenientFilter struct {
+	Field *string
+	Eq    *string
+	Ne    *string
+	Gt    *string
+	Gte   *string
+	Lt    *string
+	Lte   *string
+	In    *[]*string
+	Nin   *[]*string
+	And   *[]*convenientFilter
+	Or    *[]*convenientFilter
+}
+
+func (r *queryResolver) Input(ctx context.Context, args struct{ Index int32 }) (*inputResolver, error) {
+	return r.findInput(ctx, int(args.Index))
+}
+
+func (r *queryResolver) Voucher(
+	ctx context.Context, args struct{ VoucherIndex, InputIndex int32 },
+) (*voucherResolver, error) {
+	return r.findVoucher(ctx, uint64(args.InputIndex), uint64(args.VoucherIndex))
+}
+
+func (r *queryResolver) Notice(
+	ctx context.Context, args struct{ NoticeIndex, InputIndex int32 },
+) (*noticeResolver, error) {
+	return r.findNotice(ctx, uint64(args.InputIndex), uint64(args.NoticeIndex))
+}
+
+func (r *queryResolver) Report(args struct{ ReportIndex, InputIndex int32 }) (*reportResolver, error) {
+	return r.findReport(uint64(args.InputIndex), uint64(args.ReportIndex))
+}
+
+func (r *queryResolver) Inputs(ctx context.Context, args struct {
+	pageArgs
+	Where *inputFilter
+}) (*connectionResolver[*inputResolver], error) {
+	var filter []*mdl.ConvenienceFilter
+	if args.Where != nil {
+		field := mdl.INDEX_FIELD
+		if args.Where.IndexLowerThan != nil {
+			value := fmt.Sprint(*args.Where.IndexLowerThan)
+			filter = append(filter, &mdl.ConvenienceFilter{Field: &field, Lt: &value})
+		}
+		if args.Where.IndexGreaterThan != nil {
+			value := fmt.Sprint(*args.Where.IndexGreaterThan)
+			filter = append(filter, &mdl.ConvenienceFilter{Field: &field, Gt: &value})
+		}
+	}
+	return r.findInputs(ctx, args.pageArgs, filter)
+}
+
+func (r *queryResolver) Vouchers(ctx context.Context, args struct {
+	pageArgs
+	Filter *[]*convenientFilter
+}) (*connectionResolver[*voucherResolver], error) {
+	return r.findVouchers(ctx, args.pageArgs, convertFilters(args.Filter))
+}
+
+func (r *queryResolver) Notices(ctx context.Context, args struct {
+	pageArgs
+	Filter *[]*convenientFilter
+}) (*connectionResolver[*noticeResolver], error) {
+	return r.findNotices(ctx, args.pageArgs, convertFilters(args.Filter))
+}
+
+func (r *queryResolver) Reports(args pageArgs) (*connectionResolver[*reportResolver], error) {
+	return r.findReports(args, nil)
+}
+
+func (r *queryResolver) findInput(ctx context.Context, index int) (*inputResolver, error) {
+	input, err := r.model.InputRepository.FindByIndex(ctx, index)
+	if err != nil {
+		return nil, err
+	}
+	if input == nil {
+		return nil, fmt.Errorf("input not found")
+	}
+	return &inputResolver{r, *input}, nil
+}
+
+func (r *queryResolver) findVoucher(
+	ctx context.Context, inputIndex uint64, outputIndex uint64,
+) (*voucherResolver, error) {
+	voucher, err := r.service.FindVoucherByInputAndOutputIndex(ctx, inputIndex, outputIndex)
+	if err != nil {
+		return nil, err
+	}
+	if voucher == nil {
+		return nil, fmt.Errorf("voucher not found")
+	}
+	return &voucherResolver{r, *voucher}, nil
+}
+
+func (r *queryResolver) findNotice(
+	ctx context.Context, inputIndex uint64, outputIndex uint64,
+) (*noticeResolver, error) {
+	notice, err := r.service.FindNoticeByInputAndOutputIndex(ctx, inputIndex, outputIndex)
+	if err != nil {
+		return nil, err
+	}
+	if notice == nil {
+		return nil, fmt.Errorf("notice not found")
+	}
+	return &noticeResolver{r, *notice}, nil
+}
+
+func (r *queryResolver) findReport(inputIndex uint64, index uint64) (*reportResolver, error) {
+	report, err := r.model.ReportRepository.FindByInputAndOutputIndex(inputIndex, index)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, fmt.Errorf("report not found")
+	}
+	return &reportResolver{r, *report}, nil
+}
+
+func (r *queryResolver) findInputs(
+	ctx context.Context, args pageArgs, filter []*mdl.ConvenienceFilter,
+) (*connectionResolver[*inputResolver], error) {
+	first, last := args.limits()
+	page, err := r.model.InputRepository.FindAll(ctx, first, last, args.After, args.Before, filter)
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]*edgeResolver[*inputResolver], len(page.Rows))
+	for i, input := range page.Rows {
+		edges[i] = newEdge(&inputResolver{r, input}, uint64(input.Index), 0)
+	}
+	count := func(ctx context.Context) (uint64, error) {
+		return r.model.InputRepository.Count(ctx, filter)
+	}
+	return newConnection(edges, page, count), nil
+}
+
+func (r *queryResolver) findVouchers(
+	ctx context.Context, args pageArgs, filter []*mdl.ConvenienceFilter,
+) (*connectionResolver[*voucherResolver], error) {
+	first, last := args.limits()
+	page, err := r.service.FindAllVouchers(ctx, first, last, args.After, args.Before, filter)
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]*edgeResolver[*voucherResolver], len(page.Rows))
+	for i, voucher := range page.Rows {
+		edges[i] = newEdge(&voucherResolver{r, voucher}, voucher.InputIndex, voucher.OutputIndex)
+	}
+	count := func(ctx context.Context) (uint64, error) {
+		return r.service.CountVouchers(ctx, filter)
+	}
+	return newConnection(edges, page, count), nil
+}
+
+func (r *queryResolver) findNotices(
+	ctx context.Context, args pageArgs, filter []*mdl.ConvenienceFilter,
+) (*connectionResolver[*noticeResolver], error) {
+	first, last := args.limits()
+	page, err := r.service.FindAllNotices(ctx, first, last, args.After, args.Before, filter)
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]*edgeResolver[*noticeResolver], len(page.Rows))
+	for i, notice := range page.Rows {
+		edges[i] = newEdge(&noticeResolver{r, notice}, notice.InputIndex, notice.OutputIndex)
+	}
+	count := func(ctx context.Context) (uint64, error) {
+		return r.service.CountNotices(ctx, filter)
+	}
+	return newConnection(edges, page, count), nil
+}
+
+func (r *queryResolver) findReports(
+	args pageArgs, filter []*mdl.ConvenienceFilter,
+) (*connectionResolver[*reportResolver], error) {
+	first, last := args.limits()
+	page, err := r.model.ReportRepository.FindAll(first, last, args.After, args.Before, filter)
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]*edgeResolver[*reportResolver], len(page.Rows))
+	for i, report := range page.Rows {
+		edges[i] = newEdge(&reportResolver{r, report}, uint64(report.InputIndex), uint64(report.Index))
+	}
+	count := func(ctx context.Context) (uint64, error) {
+		return r.model.ReportRepository.Count(filter)
+	}
+	return newConnection(edges, page, count), nil
+}
+
+// Get the filter that selects the outputs of the input.
+func inputIndexFilter(index int) []*mdl.ConvenienceFilter {
+	field := mdl.INPUT_INDEX
+	value := fmt.Sprint(index)
+	return []*mdl.ConvenienceFilter{{Field: &field, Eq: &value}}
+}
+
+func convertFilters(filters *[]*convenientFilter) []*mdl.ConvenienceFilter {
+	if filters == nil {
+		return nil
+	}
+	converted := make([]*mdl.ConvenienceFilter, len(*filters))
+	for i, filter := range *filters {
+		if filter != nil {
+			converted[i] = convertFilter(*filter)
+		}
+	}
+	return converted
+}
+
+func convertFilter(filter convenientFilter) *mdl.ConvenienceFilter {
+	converted := &mdl.ConvenienceFilter{
+		Field: filter.Field,
+		Eq:    filter.Eq,
+		Ne:    filter.Ne,
+		Gt:    filter.Gt,
+		Gte:   filter.Gte,
+		Lt:    filter.Lt,
+		Lte:   filter.Lte,
+		And:   convertFilters(filter.And),
+		Or:    convertFilters(filter.Or),
+	}
+	if filter.In != nil {
+		converted.In = *filter.In
+	}
+	if filter.Nin != nil {
+		converted.Nin = *filter.Nin
+	}
+	return converted
+}
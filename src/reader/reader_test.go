@@ -0,0 +1,332 @@
+package reader
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/calindra/rollups-server/src/container"
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	testSender      = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	testDestination = common.HexToAddress("0xfafa")
+	testTime        = time.Unix(1700000000, 0)
+)
+
+type ReaderSuite struct {
+	suite.Suite
+	model   *mdl.AppModel
+	server  *httptest.Server
+	tempDir string
+}
+
+func (s *ReaderSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	tempDir, err := os.MkdirTemp("", "")
+	s.NoError(err)
+	s.tempDir = tempDir
+	db := sqlx.MustConnect("sqlite3", path.Join(tempDir, "reader.sqlite3"))
+	c := container.NewContainer(*db)
+	s.model = mdl.NewAppModel(c.GetOutputDecoder(), db)
+	e := echo.New()
+	Register(e, s.model, c.GetConvenienceService())
+	s.server = httptest.NewServer(e)
+}
+
+func (s *ReaderSuite) TearDownTest() {
+	s.server.Close()
+	os.RemoveAll(s.tempDir)
+}
+
+func TestReaderSuite(t *testing.T) {
+	suite.Run(t, new(ReaderSuite))
+}
+
+// Add two processed inputs and an unprocessed input.
+// The first input has a voucher, a notice and a report; the second has a notice.
+func (s *ReaderSuite) addInputs() {
+	for i := 0; i < 3; i++ {
+		err := s.model.AddAdvanceInput(testSender, []byte{byte(i)}, uint64(10+i), testTime, i)
+		s.Require().NoError(err)
+	}
+	_, err := s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.AddVoucher(testDestination, big.NewInt(5), []byte{0xbe, 0xef})
+	s.Require().NoError(err)
+	_, err = s.model.AddNotice([]byte{0xaa})
+	s.Require().NoError(err)
+	s.Require().NoError(s.model.AddReport([]byte{0xcc}))
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+	_, err = s.model.AddNotice([]byte{0xbb})
+	s.Require().NoError(err)
+	_, err = s.model.FinishAndGetNext(true)
+	s.Require().NoError(err)
+}
+
+// Send the query and decode the data of the response into result.
+// Return the messages of the errors in the response.
+func (s *ReaderSuite) query(query string, result any) []string {
+	body, err := json.Marshal(map[string]any{"query": query})
+	s.Require().NoError(err)
+	resp, err := http.Post(s.server.URL+"/graphql", "application/json", bytes.NewReader(body))
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var response struct {
+		Data   json.RawMessage
+		Errors []struct{ Message string }
+	}
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&response))
+	var messages []string
+	for _, err := range response.Errors {
+		messages = append(messages, err.Message)
+	}
+	if len(messages) == 0 && result != nil {
+		s.Require().NoError(json.Unmarshal(response.Data, result))
+	}
+	return messages
+}
+
+func (s *ReaderSuite) TestInputs() {
+	s.addInputs()
+	var result struct {
+		Inputs struct {
+			TotalCount int
+			Edges      []struct {
+				Cursor string
+				Node   struct {
+					Index       int
+					Status      string
+					MsgSender   string
+					Timestamp   string
+					BlockNumber string
+					Payload     string
+					Vouchers    struct {
+						TotalCount int
+						Edges      []struct {
+							Node struct {
+								Index       int
+								Destination string
+								Payload     string
+								Value       string
+							}
+						}
+					}
+					Notices struct{ TotalCount int }
+					Reports struct {
+						Edges []struct{ Node struct{ Payload string } }
+					}
+				}
+			}
+			PageInfo struct {
+				EndCursor   string
+				HasNextPage bool
+			}
+		}
+	}
+	errs := s.query(`{
+		inputs(first: 2) {
+			totalCount
+			edges {
+				cursor
+				node {
+					index status msgSender timestamp blockNumber payload
+					vouchers { totalCount edges { node { index destination payload value } } }
+					notices { totalCount }
+					reports { edges { node { payload } } }
+				}
+			}
+			pageInfo { endCursor hasNextPage }
+		}
+	}`, &result)
+	s.Require().Empty(errs)
+	inputs := result.Inputs
+	s.Equal(3, inputs.TotalCount)
+	s.Require().Len(inputs.Edges, 2)
+	s.True(inputs.PageInfo.HasNextPage)
+	s.Equal(inputs.Edges[1].Cursor, inputs.PageInfo.EndCursor)
+
+	input := inputs.Edges[0].Node
+	s.Equal(0, input.Index)
+	s.Equal("Accepted", input.Status)
+	s.Equal(testSender.Hex(), input.MsgSender)
+	s.Equal("1700000000", input.Timestamp)
+	s.Equal("10", input.BlockNumber)
+	s.Equal("0x00", input.Payload)
+	s.Equal(1, input.Vouchers.TotalCount)
+	voucher := input.Vouchers.Edges[0].Node
+	s.Equal(0, voucher.Index)
+	s.Equal(testDestination.Hex(), voucher.Destination)
+	s.Equal("0xbeef", voucher.Payload)
+	s.Equal("5", voucher.Value)
+	s.Equal(1, input.Notices.TotalCount)
+	s.Require().Len(input.Reports.Edges, 1)
+	s.Equal("0xcc", input.Reports.Edges[0].Node.Payload)
+	s.Equal(1, inputs.Edges[1].Node.Index)
+
+	errs = s.query(`{ inputs(where: { indexGreaterThan: 0, indexLowerThan: 2 }) {
+		totalCount edges { node { index status } } } }`, &result)
+	s.Require().Empty(errs)
+	s.Equal(1, result.Inputs.TotalCount)
+	s.Equal(1, result.Inputs.Edges[0].Node.Index)
+}
+
+func (s *ReaderSuite) TestOutputProof() {
+	s.addInputs()
+	_, err := s.model.FinishAndGetNext(false)
+	s.Require().NoError(err)
+	_, err = s.model.CloseEpoch()
+	s.Require().NoError(err)
+	proof, err := s.model.GetProof(0, 1)
+	s.Require().NoError(err)
+	s.Require().NotNil(proof)
+
+	var result struct {
+		Notice struct {
+			Index   int
+			Payload string
+			Input   struct{ Index int }
+			Proof   struct {
+				Context  string
+				Validity struct {
+					InputRange                       struct{ FirstIndex, LastIndex int }
+					InputIndexWithinEpoch            int
+					OutputIndexWithinInput           int
+					OutputHashesRootHash             string
+					OutputsEpochRootHash             string
+					MachineStateHash                 string
+					OutputHashInOutputHashesSiblings []string
+					OutputHashesInEpochSiblings      []string
+				}
+			}
+		}
+	}
+	errs := s.query(`{
+		notice(noticeIndex: 1, inputIndex: 0) {
+			index payload input { index }
+			proof {
+				context
+				validity {
+					inputRange { firstIndex lastIndex }
+					inputIndexWithinEpoch outputIndexWithinInput
+					outputHashesRootHash outputsEpochRootHash machineStateHash
+					outputHashInOutputHashesSiblings outputHashesInEpochSiblings
+				}
+			}
+		}
+	}`, &result)
+	s.Require().Empty(errs)
+	notice := result.Notice
+	s.Equal(1, notice.Index)
+	s.Equal("0xaa", notice.Payload)
+	s.Equal(0, notice.Input.Index)
+	validity := notice.Proof.Validity
+	s.Equal("0x", notice.Proof.Context)
+	s.Equal(int(proof.FirstInputIndex), validity.InputRange.FirstIndex)
+	s.Equal(int(proof.LastInputIndex), validity.InputRange.LastIndex)
+	s.Equal(1, validity.OutputIndexWithinInput)
+	s.Equal(proof.OutputHashesRootHash.Hex(), validity.OutputHashesRootHash)
+	s.Equal(proof.OutputsEpochRootHash.Hex(), validity.OutputsEpochRootHash)
+	s.Equal(proof.MachineStateHash.Hex(), validity.MachineStateHash)
+	s.Equal(convertHashes(proof.OutputHashInOutputHashesSiblings),
+		validity.OutputHashInOutputHashesSiblings)
+	s.Equal(convertHashes(proof.OutputHashesInEpochSiblings), validity.OutputHashesInEpochSiblings)
+}
+
+func (s *ReaderSuite) TestUnprovedOutput() {
+	s.addInputs()
+	var result struct {
+		Voucher struct {
+			Proof    *struct{ Context string }
+			Executed bool
+		}
+	}
+	errs := s.query(`{ voucher(voucherIndex: 0, inputIndex: 0) { proof { context } executed } }`, &result)
+	s.Require().Empty(errs)
+	s.Nil(result.Voucher.Proof)
+	s.False(result.Voucher.Executed)
+}
+
+func (s *ReaderSuite) TestNotFound() {
+	s.addInputs()
+	s.Equal([]string{"input not found"}, s.query(`{ input(index: 9) { index } }`, nil))
+	s.Equal([]string{"voucher not found"},
+		s.query(`{ voucher(voucherIndex: 1, inputIndex: 0) { index } }`, nil))
+	s.Equal([]string{"notice not found"},
+		s.query(`{ input(index: 0) { notice(index: 0) { index } } }`, nil))
+	s.Equal([]string{"report not found"},
+		s.query(`{ report(reportIndex: 0, inputIndex: 1) { index } }`, nil))
+}
+
+func (s *ReaderSuite) TestNoticePagination() {
+	s.addInputs()
+	type notices struct {
+		Notices struct {
+			Edges []struct {
+				Node struct {
+					Index int
+					Input struct{ Index int }
+				}
+			}
+			PageInfo struct {
+				StartCursor     string
+				HasPreviousPage bool
+			}
+		}
+	}
+	var result notices
+	errs := s.query(`{ notices(last: 1) {
+		edges { node { index input { index } } } pageInfo { startCursor hasPreviousPage } } }`, &result)
+	s.Require().Empty(errs)
+	s.Require().Len(result.Notices.Edges, 1)
+	s.Equal(1, result.Notices.Edges[0].Node.Input.Index)
+	s.True(result.Notices.PageInfo.HasPreviousPage)
+
+	var previous notices
+	errs = s.query(`{ notices(last: 1, before: "`+result.Notices.PageInfo.StartCursor+`") {
+		edges { node { index input { index } } } pageInfo { startCursor hasPreviousPage } } }`, &previous)
+	s.Require().Empty(errs)
+	s.Require().Len(previous.Notices.Edges, 1)
+	s.Equal(0, previous.Notices.Edges[0].Node.Input.Index)
+	s.Equal(1, previous.Notices.Edges[0].Node.Index)
+	s.False(previous.Notices.PageInfo.HasPreviousPage)
+
+	s.NotEmpty(s.query(`{ notices(first: 1, last: 1) { totalCount } }`, nil))
+}
+
+func (s *ReaderSuite) TestVoucherFilter() {
+	s.addInputs()
+	var result struct {
+		Vouchers struct {
+			TotalCount int
+			Edges      []struct{ Node struct{ Destination string } }
+		}
+	}
+	errs := s.query(`{ vouchers(filter: [{ field: "Destination", eq: "`+testDestination.Hex()+`" }]) {
+		totalCount edges { node { destination } } } }`, &result)
+	s.Require().Empty(errs)
+	s.Equal(1, result.Vouchers.TotalCount)
+	s.Equal(testDestination.Hex(), result.Vouchers.Edges[0].Node.Destination)
+
+	errs = s.query(`{ vouchers(filter: [{ field: "Executed", eq: "true" }]) { totalCount } }`, &result)
+	s.Require().Empty(errs)
+	s.Equal(0, result.Vouchers.TotalCount)
+
+	s.NotEmpty(s.query(`{ vouchers(filter: [{ field: "Unknown", eq: "1" }]) { totalCount } }`, nil))
+}
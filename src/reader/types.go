@@ -0,0 +1,409 @@
+package reader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Integer of the BigInt scalar, which is serialized as a decimal string.
+type bigInt struct {
+	*big.Int
+}
+
+func newBigInt(value *big.Int) *bigInt {
+	if value == nil {
+		return nil
+	}
+	return &bigInt{value}
+}
+
+func (bigInt) ImplementsGraphQLType(name string) bool {
+	return name == "BigInt"
+}
+
+func (b *bigInt) UnmarshalGraphQL(input any) error {
+	switch value := input.(type) {
+	case string:
+		parsed, ok := new(big.Int).SetString(value, 10)
+		if !ok {
+			return fmt.Errorf("invalid BigInt %q", value)
+		}
+		b.Int = parsed
+	case int32:
+		b.Int = big.NewInt(int64(value))
+	default:
+		return fmt.Errorf("invalid BigInt %v", input)
+	}
+	return nil
+}
+
+func (b bigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// Names of the completion statuses in the schema.
+var completionStatuses = map[mdl.CompletionStatus]string{
+	mdl.CompletionStatusUnprocessed: "Unprocessed",
+	mdl.CompletionStatusAccepted:    "Accepted",
+	mdl.CompletionStatusRejected:    "Rejected",
+	mdl.CompletionStatusException:   "Exception",
+}
+
+type inputResolver struct {
+	r     *queryResolver
+	input mdl.AdvanceInput
+}
+
+func (i *inputResolver) Index() int32 {
+	return int32(i.input.Index)
+}
+
+func (i *inputResolver) Status() string {
+	return completionStatuses[i.input.Status]
+}
+
+func (i *inputResolver) Timestamp() bigInt {
+	return bigInt{big.NewInt(i.input.BlockTimestamp.Unix())}
+}
+
+func (i *inputResolver) MsgSender() string {
+	return i.input.MsgSender.Hex()
+}
+
+func (i *inputResolver) BlockNumber() bigInt {
+	return bigInt{new(big.Int).SetUint64(i.input.BlockNumber)}
+}
+
+func (i *inputResolver) Payload() string {
+	return hexutil.Encode(i.input.Payload)
+}
+
+func (i *inputResolver) Voucher(ctx context.Context, args struct{ Index int32 }) (*voucherResolver, error) {
+	return i.r.findVoucher(ctx, uint64(i.input.Index), uint64(args.Index))
+}
+
+func (i *inputResolver) Notice(ctx context.Context, args struct{ Index int32 }) (*noticeResolver, error) {
+	return i.r.findNotice(ctx, uint64(i.input.Index), uint64(args.Index))
+}
+
+func (i *inputResolver) Report(args struct{ Index int32 }) (*reportResolver, error) {
+	return i.r.findReport(uint64(i.input.Index), uint64(args.Index))
+}
+
+func (i *inputResolver) Vouchers(
+	ctx context.Context, args pageArgs,
+) (*connectionResolver[*voucherResolver], error) {
+	return i.r.findVouchers(ctx, args, inputIndexFilter(i.input.Index))
+}
+
+func (i *inputResolver) Notices(
+	ctx context.Context, args pageArgs,
+) (*connectionResolver[*noticeResolver], error) {
+	return i.r.findNotices(ctx, args, inputIndexFilter(i.input.Index))
+}
+
+func (i *inputResolver) Reports(args pageArgs) (*connectionResolver[*reportResolver], error) {
+	return i.r.findReports(args, inputIndexFilter(i.input.Index))
+}
+
+type voucherResolver struct {
+	r       *queryResolver
+	voucher mdl.ConvenienceVoucher
+}
+
+func (v *voucherResolver) Index() int32 {
+	return int32(v.voucher.OutputIndex)
+}
+
+func (v *voucherResolver) Input(ctx context.Context) (*inputResolver, error) {
+	return v.r.findInput(ctx, int(v.voucher.InputIndex))
+}
+
+func (v *voucherResolver) Destination() string {
+	return v.voucher.Destination.Hex()
+}
+
+func (v *voucherResolver) Payload() string {
+	return v.voucher.Payload
+}
+
+func (v *voucherResolver) Proof() (*proofResolver, error) {
+	return v.r.findProof(v.voucher.InputIndex, v.voucher.OutputIndex)
+}
+
+func (v *voucherResolver) Value() bigInt {
+	if v.voucher.Value == nil {
+		return bigInt{new(big.Int)}
+	}
+	return bigInt{v.voucher.Value}
+}
+
+func (v *voucherResolver) Executed() bool {
+	return v.voucher.Executed
+}
+
+func (v *voucherResolver) TransactionHash() *string {
+	if v.voucher.ExecutedTxHash == (common.Hash{}) {
+		return nil
+	}
+	hash := v.voucher.ExecutedTxHash.Hex()
+	return &hash
+}
+
+func (v *voucherResolver) Contract() *string {
+	return optionalAddress(v.voucher.Contract)
+}
+
+func (v *voucherResolver) Beneficiary() *string {
+	return optionalAddress(v.voucher.Beneficiary)
+}
+
+func (v *voucherResolver) Amount() *bigInt {
+	return newBigInt(v.voucher.Amount)
+}
+
+func (v *voucherResolver) TokenId() *bigInt {
+	return newBigInt(v.voucher.TokenId)
+}
+
+func (v *voucherResolver) MethodSignature() *string {
+	return optionalString(v.voucher.MethodSignature)
+}
+
+func (v *voucherResolver) Ercx() *string {
+	return optionalString(v.voucher.ERCX)
+}
+
+func (v *voucherResolver) Label() *string {
+	return optionalString(v.voucher.Label)
+}
+
+func (v *voucherResolver) DecodedFunction() *string {
+	return optionalString(v.voucher.DecodedFunction)
+}
+
+func (v *voucherResolver) DecodedArgs() *string {
+	return optionalString(string(v.voucher.DecodedArgs))
+}
+
+type noticeResolver struct {
+	r      *queryResolver
+	notice mdl.ConvenienceNotice
+}
+
+func (n *noticeResolver) Index() int32 {
+	return int32(n.notice.OutputIndex)
+}
+
+func (n *noticeResolver) Input(ctx context.Context) (*inputResolver, error) {
+	return n.r.findInput(ctx, int(n.notice.InputIndex))
+}
+
+func (n *noticeResolver) Payload() string {
+	return n.notice.Payload
+}
+
+func (n *noticeResolver) Proof() (*proofResolver, error) {
+	return n.r.findProof(n.notice.InputIndex, n.notice.OutputIndex)
+}
+
+func (n *noticeResolver) DecodedSchema() *string {
+	return optionalString(n.notice.DecodedSchema)
+}
+
+func (n *noticeResolver) DecodedFields() *string {
+	return optionalString(string(n.notice.DecodedFields))
+}
+
+type reportResolver struct {
+	r      *queryResolver
+	report mdl.Report
+}
+
+func (p *reportResolver) Index() int32 {
+	return int32(p.report.Index)
+}
+
+func (p *reportResolver) Input(ctx context.Context) (*inputResolver, error) {
+	return p.r.findInput(ctx, p.report.InputIndex)
+}
+
+func (p *reportResolver) Payload() string {
+	return hexutil.Encode(p.report.Payload)
+}
+
+// Get the proof of the output; return nil if the epoch of the input is not proved yet.
+func (r *queryResolver) findProof(inputIndex uint64, outputIndex uint64) (*proofResolver, error) {
+	proof, err := r.model.GetProof(inputIndex, outputIndex)
+	if err != nil || proof == nil {
+		return nil, err
+	}
+	return &proofResolver{*proof}, nil
+}
+
+type proofResolver struct {
+	proof mdl.Proof
+}
+
+func (p *proofResolver) Validity() *validityResolver {
+	return &validityResolver{p.proof}
+}
+
+func (p *proofResolver) Context() string {
+	return "0x"
+}
+
+type validityResolver struct {
+	proof mdl.Proof
+}
+
+func (v *validityResolver) InputRange() *inputRangeResolver {
+	return &inputRangeResolver{v.proof.FirstInputIndex, v.proof.LastInputIndex}
+}
+
+func (v *validityResolver) InputIndexWithinEpoch() int32 {
+	return int32(v.proof.InputIndexWithinEpoch)
+}
+
+func (v *validityResolver) OutputIndexWithinInput() int32 {
+	return int32(v.proof.OutputIndex)
+}
+
+func (v *validityResolver) OutputHashesRootHash() string {
+	return v.proof.OutputHashesRootHash.Hex()
+}
+
+func (v *validityResolver) OutputsEpochRootHash() string {
+	return v.proof.OutputsEpochRootHash.Hex()
+}
+
+func (v *validityResolver) MachineStateHash() string {
+	return v.proof.MachineStateHash.Hex()
+}
+
+func (v *validityResolver) OutputHashInOutputHashesSiblings() []string {
+	return convertHashes(v.proof.OutputHashInOutputHashesSiblings)
+}
+
+func (v *validityResolver) OutputHashesInEpochSiblings() []string {
+	return convertHashes(v.proof.OutputHashesInEpochSiblings)
+}
+
+type inputRangeResolver struct {
+	firstIndex uint64
+	lastIndex  uint64
+}
+
+func (i *inputRangeResolver) FirstIndex() int32 {
+	return int32(i.firstIndex)
+}
+
+func (i *inputRangeResolver) LastIndex() int32 {
+	return int32(i.lastIndex)
+}
+
+// Connection of the nodes of a page.
+// The total count is only queried when it is requested.
+type connectionResolver[T any] struct {
+	edges    []*edgeResolver[T]
+	pageInfo *pageInfoResolver
+	count    func(ctx context.Context) (uint64, error)
+}
+
+func newConnection[T any, R any](
+	edges []*edgeResolver[T],
+	page *util.PageResult[R],
+	count func(ctx context.Context) (uint64, error),
+) *connectionResolver[T] {
+	pageInfo := &pageInfoResolver{
+		startCursor:     page.StartCursor,
+		endCursor:       page.EndCursor,
+		hasNextPage:     page.HasNextPage,
+		hasPreviousPage: page.HasPreviousPage,
+	}
+	return &connectionResolver[T]{edges, pageInfo, count}
+}
+
+func (c *connectionResolver[T]) TotalCount(ctx context.Context) (int32, error) {
+	count, err := c.count(ctx)
+	return int32(count), err
+}
+
+func (c *connectionResolver[T]) Edges() []*edgeResolver[T] {
+	return c.edges
+}
+
+func (c *connectionResolver[T]) PageInfo() *pageInfoResolver {
+	return c.pageInfo
+}
+
+type edgeResolver[T any] struct {
+	node   T
+	cursor string
+}
+
+// Create the edge of the node at the position of the input and output indices.
+func newEdge[T any](node T, inputIndex uint64, outputIndex uint64) *edgeResolver[T] {
+	cursor := util.EncodeCursor(util.Cursor{InputIndex: inputIndex, OutputIndex: outputIndex})
+	return &edgeResolver[T]{node, cursor}
+}
+
+func (e *edgeResolver[T]) Node() T {
+	return e.node
+}
+
+func (e *edgeResolver[T]) Cursor() string {
+	return e.cursor
+}
+
+type pageInfoResolver struct {
+	startCursor     *string
+	endCursor       *string
+	hasNextPage     bool
+	hasPreviousPage bool
+}
+
+func (p *pageInfoResolver) StartCursor() *string {
+	return p.startCursor
+}
+
+func (p *pageInfoResolver) EndCursor() *string {
+	return p.endCursor
+}
+
+func (p *pageInfoResolver) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+func (p *pageInfoResolver) HasPreviousPage() bool {
+	return p.hasPreviousPage
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func optionalAddress(address common.Address) *string {
+	if address == (common.Address{}) {
+		return nil
+	}
+	return optionalString(address.Hex())
+}
+
+func convertHashes(hashes []common.Hash) []string {
+	converted := make([]string, len(hashes))
+	for i, hash := range hashes {
+		converted[i] = hash.Hex()
+	}
+	return converted
+}
@@ -113,11 +113,19 @@ type Metadata struct {
 	// InputIndex Input index starting from genesis.
 	InputIndex uint64 `json:"input_index"`
 
+	// LogIndex Index of the InputAdded log in its block.
+	LogIndex *uint64 `json:"log_index,omitempty"`
+
 	// MsgSender 20-byte address of the account that submitted the input.
 	MsgSender string `json:"msg_sender"`
 
-	// PrevRandao The latest RANDAO mix of the post beacon state of the previous block.
-	PrevRandao uint64 `json:"prev_randao"`
+	// PrevRandao The latest RANDAO mix of the post beacon state of the previous block,
+	// as a 32-byte uint256 in hex.
+	// Breaking change in 0.9.0: it was an integer before.
+	PrevRandao string `json:"prev_randao"`
+
+	// TransactionHash 32-byte hash of the transaction that added the input to the input box.
+	TransactionHash *string `json:"transaction_hash,omitempty"`
 }
 
 // Notice defines model for Notice.
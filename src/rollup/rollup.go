@@ -7,7 +7,9 @@ package rollup
 //go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen -config=oapi.yaml ../../api/rollup.yaml
 
 import (
+	"context"
 	"log/slog"
+	"math/big"
 	"net/http"
 
 	"strings"
@@ -19,23 +21,27 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-const FinishRetries = 50
-const FinishPollInterval = time.Millisecond * 100
+// Default time a /finish request waits for a new input before returning 202.
+const DefaultFinishTimeout = 10 * time.Second
 
 // Register the rollup API to echo
-func Register(e *echo.Echo, model *mdl.AppModel, sequencer Sequencer) {
-	var rollupAPI ServerInterface = &RollupAPI{model, sequencer}
+func Register(e *echo.Echo, model *mdl.AppModel, sequencer Sequencer, finishTimeout time.Duration) {
+	if finishTimeout <= 0 {
+		finishTimeout = DefaultFinishTimeout
+	}
+	var rollupAPI ServerInterface = &RollupAPI{model, sequencer, finishTimeout}
 	RegisterHandlers(e, rollupAPI)
 }
 
 // Shared struct for request handlers.
 type RollupAPI struct {
-	model     *mdl.AppModel
-	sequencer Sequencer
+	model         *mdl.AppModel
+	sequencer     Sequencer
+	finishTimeout time.Duration
 }
 
 type Sequencer interface {
-	FinishAndGetNext(accept bool) mdl.Input
+	FinishAndGetNext(accept bool) (mdl.Input, error)
 }
 
 // Gio implements ServerInterface.
@@ -92,20 +98,31 @@ func (r *RollupAPI) Finish(c echo.Context) error {
 	if r.sequencer == nil {
 		return c.String(http.StatusInternalServerError, "sequencer not available")
 	}
-	for i := 0; i < FinishRetries; i++ {
-		input := r.sequencer.FinishAndGetNext(accepted)
+	ctx := c.Request().Context()
+	longPoll, cancel := context.WithTimeout(ctx, r.finishTimeout)
+	defer cancel()
+	for {
+		// get the notification channel before looking for inputs,
+		// so an input added in between wakes us up right away
+		newInputs := r.model.NewInputs()
+		input, err := r.sequencer.FinishAndGetNext(accepted)
+		if err != nil {
+			slog.Error("rollups-server: failed to finish", "error", err)
+			return c.String(http.StatusInternalServerError, err.Error())
+		}
 		if input != nil {
 			resp := convertInput(input)
 			return c.JSON(http.StatusOK, &resp)
 		}
-		ctx := c.Request().Context()
 		select {
-		case <-ctx.Done():
-			return c.String(http.StatusInternalServerError, ctx.Err().Error())
-		case <-time.After(FinishPollInterval):
+		case <-longPoll.Done():
+			if ctx.Err() != nil {
+				return c.String(http.StatusInternalServerError, ctx.Err().Error())
+			}
+			return c.String(http.StatusAccepted, "no rollup request available")
+		case <-newInputs:
 		}
 	}
-	return c.String(http.StatusAccepted, "no rollup request available")
 }
 
 // Handle requests to /voucher.
@@ -128,13 +145,22 @@ func (r *RollupAPI) AddVoucher(c echo.Context) error {
 	if len(destination) != common.AddressLength {
 		return c.String(http.StatusBadRequest, "invalid address length")
 	}
+	// older DApps do not send the value, which defaults to zero
+	value := new(big.Int)
+	if request.Value != "" {
+		valueBytes, err := hexutil.Decode(request.Value)
+		if err != nil || len(valueBytes) != common.HashLength {
+			return c.String(http.StatusBadRequest, "invalid hex value")
+		}
+		value.SetBytes(valueBytes)
+	}
 	payload, err := hexutil.Decode(request.Payload)
 	if err != nil {
 		return c.String(http.StatusBadRequest, "invalid hex payload")
 	}
 
 	// talk to model
-	index, err := r.model.AddVoucher(common.Address(destination), payload)
+	index, err := r.model.AddVoucher(common.Address(destination), value, payload)
 	if err != nil {
 		return c.String(http.StatusForbidden, err.Error())
 	}
@@ -236,15 +262,29 @@ func convertInput(input mdl.Input) RollupRequest {
 	var resp RollupRequest
 	switch input := input.(type) {
 	case mdl.AdvanceInput:
+		var prevRandao common.Hash
+		if input.PrevRandao != nil {
+			prevRandao = common.BigToHash(input.PrevRandao)
+		}
 		advance := Advance{
 			Metadata: Metadata{
+				ChainId:        input.ChainId,
+				AppContract:    hexutil.Encode(input.AppContract[:]),
 				BlockNumber:    input.BlockNumber,
 				InputIndex:     uint64(input.Index),
 				MsgSender:      hexutil.Encode(input.MsgSender[:]),
 				BlockTimestamp: uint64(input.BlockTimestamp.Unix()),
+				PrevRandao:     prevRandao.Hex(),
 			},
 			Payload: hexutil.Encode(input.Payload),
 		}
+		// the location is unknown for the inputs added before it was stored
+		if input.TransactionHash != (common.Hash{}) {
+			transactionHash := input.TransactionHash.Hex()
+			logIndex := input.LogIndex
+			advance.Metadata.TransactionHash = &transactionHash
+			advance.Metadata.LogIndex = &logIndex
+		}
 		err := resp.Data.FromAdvance(advance)
 		if err != nil {
 			panic("failed to convert advance")
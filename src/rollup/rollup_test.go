@@ -0,0 +1,183 @@
+package rollup
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/sequencer"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+const testTimeout = 5 * time.Second
+const testFinishTimeout = 500 * time.Millisecond
+
+// Decoder that fails while the failing flag is set.
+type flakyDecoder struct {
+	failing bool
+}
+
+func (d *flakyDecoder) HandleOutput(
+	ctx context.Context,
+	destination common.Address,
+	value *big.Int,
+	payload string,
+	inputIndex uint64,
+	outputIndex uint64,
+) error {
+	if d.failing {
+		return errors.New("database is locked")
+	}
+	return nil
+}
+
+func (d *flakyDecoder) HandleInput(ctx context.Context, input mdl.AdvanceInput) error {
+	return nil
+}
+
+func (d *flakyDecoder) RewindOutputs(ctx context.Context, inputIndex uint64) error {
+	return nil
+}
+
+type RollupSuite struct {
+	suite.Suite
+	decoder *flakyDecoder
+	model   *mdl.AppModel
+	server  *httptest.Server
+	client  *ClientWithResponses
+	tempDir string
+}
+
+func (s *RollupSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	tempDir, err := os.MkdirTemp("", "")
+	s.NoError(err)
+	s.tempDir = tempDir
+	db := sqlx.MustConnect("sqlite3", path.Join(tempDir, "rollup.sqlite3"))
+	s.decoder = &flakyDecoder{}
+	s.model = mdl.NewAppModel(s.decoder, db)
+	e := echo.New()
+	Register(e, s.model, sequencer.NewInputBoxSequencer(s.model), testFinishTimeout)
+	s.server = httptest.NewServer(e)
+	client, err := NewClientWithResponses(s.server.URL)
+	s.NoError(err)
+	s.client = client
+}
+
+func (s *RollupSuite) TearDownTest() {
+	s.server.Close()
+	os.RemoveAll(s.tempDir)
+}
+
+func TestRollupSuite(t *testing.T) {
+	suite.Run(t, new(RollupSuite))
+}
+
+func (s *RollupSuite) TestFinishTimesOutWithoutInputs() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	start := time.Now()
+	resp, err := s.client.FinishWithResponse(ctx, FinishJSONRequestBody{Status: Accept})
+	s.NoError(err)
+	s.Equal(http.StatusAccepted, resp.StatusCode())
+	s.GreaterOrEqual(time.Since(start), testFinishTimeout)
+}
+
+func (s *RollupSuite) TestFinishWakesUpOnNewInput() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	go func() {
+		time.Sleep(testFinishTimeout / 5)
+		err := s.model.AddAdvanceInput(common.Address{}, []byte{0xde, 0xad}, 1, time.Now(), 0)
+		s.NoError(err)
+	}()
+	start := time.Now()
+	resp, err := s.client.FinishWithResponse(ctx, FinishJSONRequestBody{Status: Accept})
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode())
+	s.Less(time.Since(start), testFinishTimeout)
+	s.Equal(AdvanceState, resp.JSON200.RequestType)
+	advance, err := resp.JSON200.Data.AsAdvance()
+	s.NoError(err)
+	s.Equal("0xdead", advance.Payload)
+	// the input was not read from the input box, so its location is unknown
+	s.Nil(advance.Metadata.TransactionHash)
+	s.Nil(advance.Metadata.LogIndex)
+}
+
+func (s *RollupSuite) TestFinishSendsAdvanceMetadata() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	err := s.model.AddEvmAdvanceInput(mdl.AdvanceInput{
+		Index:           0,
+		MsgSender:       common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		Payload:         []byte{0xde, 0xad},
+		BlockNumber:     10,
+		BlockTimestamp:  time.Unix(1700000000, 0),
+		PrevRandao:      new(big.Int).Lsh(big.NewInt(0x2a), 248),
+		ChainId:         31337,
+		AppContract:     common.HexToAddress("0x45290f5A64Ea31887C7bc25fB3269173bCd01093"),
+		TransactionHash: common.HexToHash("0xabcd"),
+		LogIndex:        2,
+	})
+	s.NoError(err)
+	resp, err := s.client.FinishWithResponse(ctx, FinishJSONRequestBody{Status: Accept})
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode())
+	advance, err := resp.JSON200.Data.AsAdvance()
+	s.NoError(err)
+	transactionHash := common.HexToHash("0xabcd").Hex()
+	logIndex := uint64(2)
+	s.Equal(Metadata{
+		ChainId:         31337,
+		AppContract:     "0x45290f5a64ea31887c7bc25fb3269173bcd01093",
+		MsgSender:       "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266",
+		InputIndex:      0,
+		BlockNumber:     10,
+		BlockTimestamp:  1700000000,
+		PrevRandao:      "0x2a00000000000000000000000000000000000000000000000000000000000000",
+		TransactionHash: &transactionHash,
+		LogIndex:        &logIndex,
+	}, advance.Metadata)
+}
+
+func (s *RollupSuite) TestFinishErrorKeepsInputRecoverable() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	err := s.model.AddAdvanceInput(common.Address{}, []byte{0xde, 0xad}, 1, time.Now(), 0)
+	s.NoError(err)
+	resp, err := s.client.FinishWithResponse(ctx, FinishJSONRequestBody{Status: Accept})
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode())
+	_, err = s.model.AddVoucher(common.Address{}, big.NewInt(0), []byte{0xbe, 0xef})
+	s.NoError(err)
+
+	s.decoder.failing = true
+	resp, err = s.client.FinishWithResponse(ctx, FinishJSONRequestBody{Status: Accept})
+	s.NoError(err)
+	s.Equal(http.StatusInternalServerError, resp.StatusCode())
+	input, err := s.model.InputRepository.FindByIndex(context.Background(), 0)
+	s.NoError(err)
+	s.Equal(mdl.CompletionStatusUnprocessed, input.Status)
+
+	s.decoder.failing = false
+	resp, err = s.client.FinishWithResponse(ctx, FinishJSONRequestBody{Status: Accept})
+	s.NoError(err)
+	s.Equal(http.StatusAccepted, resp.StatusCode())
+	input, err = s.model.InputRepository.FindByIndex(context.Background(), 0)
+	s.NoError(err)
+	s.Equal(mdl.CompletionStatusAccepted, input.Status)
+}
@@ -0,0 +1,217 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package inputter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/calindra/rollups-server/src/contracts"
+	"github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Name of the checkpoint of the execution worker in the model.
+const executionCheckpointName = "execution"
+
+type VoucherService interface {
+	MarkVoucherExecuted(
+		ctx context.Context,
+		inputIndex uint64,
+		outputIndex uint64,
+		txHash common.Hash,
+		blockNumber uint64,
+	) (bool, error)
+}
+
+// Model where the execution worker keeps the last block it read.
+type CheckpointModel interface {
+	GetCheckpoint(name string) (*model.Block, error)
+	SetCheckpoint(name string, block model.Block) error
+}
+
+// This worker reads the OutputExecuted events of the application and marks the
+// executed vouchers in the service.
+type ExecutionWorker struct {
+	Service            VoucherService
+	Model              CheckpointModel
+	Provider           string
+	ApplicationAddress common.Address
+	FromBlock          uint64
+	// Maximum number of blocks read in each eth_getLogs request; zero uses DefaultBlockRange.
+	BlockRange uint64
+	// Poll the node for new blocks instead of subscribing to them; the worker always
+	// polls HTTP providers.
+	Polling bool
+	// Interval between checks for new blocks when polling; zero uses DefaultPollInterval.
+	PollInterval time.Duration
+}
+
+func (w ExecutionWorker) String() string {
+	return "execution"
+}
+
+func (w ExecutionWorker) Start(ctx context.Context, ready chan<- struct{}) error {
+	conn := nodeConnection{
+		name:         "execution",
+		provider:     w.Provider,
+		polling:      w.Polling,
+		pollInterval: w.PollInterval,
+	}
+	return conn.run(ctx, ready, func(
+		ctx context.Context,
+		client *ethclient.Client,
+		heads <-chan *types.Header,
+		subErr <-chan error,
+	) error {
+		return w.readExecutions(ctx, client, heads, subErr)
+	})
+}
+
+// Read the executions from the checkpoint until the latest block each time a block arrives.
+func (w ExecutionWorker) readExecutions(
+	ctx context.Context,
+	client backend,
+	heads <-chan *types.Header,
+	subErr <-chan error,
+) error {
+	application, err := contracts.NewApplicationFilterer(w.ApplicationAddress, client)
+	if err != nil {
+		return fmt.Errorf("execution: bind application: %w", err)
+	}
+	for {
+		err := w.readNewExecutions(ctx, client, application)
+		if errors.Is(err, errStaleLog) {
+			slog.Warn("execution: the chain changed during the read; reading again on the next block",
+				"error", err)
+		} else if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-subErr:
+			return nodeErrorf("execution: %w", err)
+		case <-heads:
+			drainHeads(heads)
+		}
+	}
+}
+
+// Read the executions from the last checkpoint until the latest block, in chunks of
+// BlockRange blocks, saving the checkpoint after each chunk.
+// Like the inputter, the range starts at the checkpoint block; the events read twice mark
+// the same voucher again, which is harmless.
+// Return errStaleLog if the chain changed during the read.
+func (w ExecutionWorker) readNewExecutions(
+	ctx context.Context,
+	client backend,
+	application *contracts.ApplicationFilterer,
+) error {
+	from := w.FromBlock
+	checkpoint, err := w.Model.GetCheckpoint(executionCheckpointName)
+	if err != nil {
+		return fmt.Errorf("execution: %w", err)
+	}
+	if checkpoint != nil && checkpoint.Number > from {
+		from = checkpoint.Number
+	}
+	latest, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nodeErrorf("execution: get latest block: %w", err)
+	}
+	if from > latest {
+		return nil
+	}
+	blockRange := w.BlockRange
+	if blockRange == 0 {
+		blockRange = DefaultBlockRange
+	}
+	for {
+		to := min(from+blockRange-1, latest)
+		// the header is read before the logs, like in the inputter
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+		if err != nil {
+			return nodeErrorf("execution: get block %v: %w", to, err)
+		}
+		err = w.readExecutionsInRange(ctx, client, application, from, to)
+		if err != nil {
+			return err
+		}
+		err = w.Model.SetCheckpoint(
+			executionCheckpointName, model.Block{Number: to, Hash: header.Hash()})
+		if err != nil {
+			return fmt.Errorf("execution: %w", err)
+		}
+		if to == latest {
+			return nil
+		}
+		from = to + 1
+	}
+}
+
+// Read the executions between the given blocks, inclusive.
+func (w ExecutionWorker) readExecutionsInRange(
+	ctx context.Context,
+	client backend,
+	application *contracts.ApplicationFilterer,
+	from uint64,
+	to uint64,
+) error {
+	opts := bind.FilterOpts{
+		Context: ctx,
+		Start:   from,
+		End:     &to,
+	}
+	it, err := application.FilterOutputExecuted(&opts)
+	if err != nil {
+		return nodeErrorf("execution: filter output executed: %w", err)
+	}
+	defer it.Close()
+	for it.Next() {
+		if _, err := logHeader(ctx, client, it.Event.Raw); err != nil {
+			return fmt.Errorf("execution: %w", err)
+		}
+		if err := w.markExecuted(ctx, it.Event); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nodeErrorf("execution: filter output executed: %w", err)
+	}
+	return nil
+}
+
+// Mark the voucher of the event as executed.
+func (w ExecutionWorker) markExecuted(
+	ctx context.Context,
+	event *contracts.ApplicationOutputExecuted,
+) error {
+	found, err := w.Service.MarkVoucherExecuted(
+		ctx,
+		event.InputIndex,
+		event.OutputIndexWithinInput,
+		event.Raw.TxHash,
+		event.Raw.BlockNumber,
+	)
+	if err != nil {
+		return fmt.Errorf("execution: mark voucher executed: %w", err)
+	}
+	if !found {
+		slog.Warn("execution: executed voucher not found",
+			"input", event.InputIndex, "output", event.OutputIndexWithinInput)
+		return nil
+	}
+	slog.Info("execution: voucher executed",
+		"input", event.InputIndex, "output", event.OutputIndexWithinInput,
+		"tx", event.Raw.TxHash, "block", event.Raw.BlockNumber)
+	return nil
+}
@@ -0,0 +1,141 @@
+package inputter
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"testing"
+
+	"github.com/calindra/rollups-server/src/contracts"
+	"github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// Service and model that record the executed vouchers and the checkpoints in memory.
+type fakeExecutionModel struct {
+	executed    [][2]uint64
+	checkpoints map[string]model.Block
+}
+
+func (m *fakeExecutionModel) MarkVoucherExecuted(
+	ctx context.Context,
+	inputIndex uint64,
+	outputIndex uint64,
+	txHash common.Hash,
+	blockNumber uint64,
+) (bool, error) {
+	m.executed = append(m.executed, [2]uint64{inputIndex, outputIndex})
+	return true, nil
+}
+
+func (m *fakeExecutionModel) GetCheckpoint(name string) (*model.Block, error) {
+	block, ok := m.checkpoints[name]
+	if !ok {
+		return nil, nil
+	}
+	return &block, nil
+}
+
+func (m *fakeExecutionModel) SetCheckpoint(name string, block model.Block) error {
+	m.checkpoints[name] = block
+	return nil
+}
+
+// Add an OutputExecuted event of the application in the given block.
+func (b *fakeBackend) addExecution(inputIndex uint64, outputIndex uint64, blockNumber uint64) error {
+	applicationAbi, err := contracts.ApplicationMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	event := applicationAbi.Events["OutputExecuted"]
+	data, err := event.Inputs.Pack(inputIndex, outputIndex, []byte{0xaa})
+	if err != nil {
+		return err
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.logs = append(b.logs, types.Log{
+		Address:     testApplication,
+		Topics:      []common.Hash{event.ID},
+		Data:        data,
+		BlockNumber: blockNumber,
+		BlockHash:   b.header(blockNumber).Hash(),
+		TxHash:      common.BigToHash(new(big.Int).SetUint64(blockNumber)),
+	})
+	b.latest = max(b.latest, blockNumber)
+	return nil
+}
+
+type ExecutionSuite struct {
+	suite.Suite
+	backend *fakeBackend
+	model   *fakeExecutionModel
+	worker  ExecutionWorker
+}
+
+func (s *ExecutionSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	s.backend = &fakeBackend{}
+	s.model = &fakeExecutionModel{checkpoints: make(map[string]model.Block)}
+	s.worker = ExecutionWorker{
+		Service:            s.model,
+		Model:              s.model,
+		ApplicationAddress: testApplication,
+		FromBlock:          2,
+		BlockRange:         10,
+	}
+}
+
+func TestExecutionSuite(t *testing.T) {
+	suite.Run(t, new(ExecutionSuite))
+}
+
+func (s *ExecutionSuite) readNewExecutions() {
+	application, err := contracts.NewApplicationFilterer(testApplication, s.backend)
+	s.Require().NoError(err)
+	err = s.worker.readNewExecutions(context.Background(), s.backend, application)
+	s.Require().NoError(err)
+}
+
+func (s *ExecutionSuite) TestReadInChunks() {
+	s.Require().NoError(s.backend.addExecution(0, 0, 3))
+	s.Require().NoError(s.backend.addExecution(1, 2, 25))
+	s.backend.latest = 30
+	s.readNewExecutions()
+
+	s.Equal([][2]uint64{{2, 11}, {12, 21}, {22, 30}}, s.backend.ranges)
+	s.Equal([][2]uint64{{0, 0}, {1, 2}}, s.model.executed)
+	checkpoint := s.model.checkpoints[executionCheckpointName]
+	s.Equal(uint64(30), checkpoint.Number)
+	s.Equal(s.backend.header(30).Hash(), checkpoint.Hash)
+}
+
+func (s *ExecutionSuite) TestResumeFromCheckpoint() {
+	s.model.checkpoints[executionCheckpointName] = model.Block{Number: 20}
+	s.Require().NoError(s.backend.addExecution(0, 0, 3))
+	s.Require().NoError(s.backend.addExecution(1, 0, 22))
+	s.readNewExecutions()
+
+	// the history before the checkpoint is not read again
+	s.Equal([][2]uint64{{20, 22}}, s.backend.ranges)
+	s.Equal([][2]uint64{{1, 0}}, s.model.executed)
+	s.Equal(uint64(22), s.model.checkpoints[executionCheckpointName].Number)
+}
+
+func (s *ExecutionSuite) TestStaleLog() {
+	s.Require().NoError(s.backend.addExecution(0, 0, 3))
+	s.backend.latest = 10
+	// replace the block of the execution without removing its log
+	s.backend.fork++
+	delete(s.backend.headers, 3)
+
+	application, err := contracts.NewApplicationFilterer(testApplication, s.backend)
+	s.Require().NoError(err)
+	err = s.worker.readNewExecutions(context.Background(), s.backend, application)
+	s.ErrorIs(err, errStaleLog)
+	s.Empty(s.model.executed)
+	s.NotContains(s.model.checkpoints, executionCheckpointName)
+}
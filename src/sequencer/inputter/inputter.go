@@ -5,27 +5,56 @@ package inputter
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math/big"
 	"time"
 
 	"github.com/calindra/rollups-server/src/contracts"
 	"github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
+// Name of the checkpoint of the inputter in the model.
+const checkpointName = "inputter"
+
+// Default number of blocks read in each eth_getLogs request.
+const DefaultBlockRange = 1000
+
 type Model interface {
-	AddAdvanceInput(
-		sender common.Address,
-		payload []byte,
-		blockNumber uint64,
-		timestamp time.Time,
-		index int,
-	)
+	AddEvmAdvanceInput(input model.AdvanceInput) error
+	GetCheckpoint(name string) (*model.Block, error)
+	SetCheckpoint(name string, block model.Block) error
+	GetInputBlocks() ([]model.Block, error)
+	RewindInputs(blockNumber uint64) (int, error)
+}
+
+// Node calls used to read the inputs.
+type backend interface {
+	bind.ContractFilterer
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Error returned when a block that was read is no longer in the chain.
+type reorgError struct {
+	// Latest block with inputs that is still in the chain.
+	forkBlock uint64
 }
 
+func (e *reorgError) Error() string {
+	return fmt.Sprintf("chain reorganization after block %v", e.forkBlock)
+}
+
+// Error returned when the block of a log that was read is no longer in the chain, because the
+// chain changed during the read; the range is read again on the next block.
+var errStaleLog = errors.New("log is no longer in the chain")
+
 // This worker reads inputs from Ethereum and puts them in the model.
 type InputterWorker struct {
 	Model              Model
@@ -34,6 +63,18 @@ type InputterWorker struct {
 	InputBoxBlock      uint64
 	ApplicationAddress common.Address
 	Repository         model.InputRepository
+	// Maximum number of blocks read in each eth_getLogs request; zero uses DefaultBlockRange.
+	BlockRange uint64
+	// Number of blocks on top of a block before its inputs are read.
+	Confirmations uint64
+	// Rewind the model to the fork block when a reorganization is detected; otherwise, the
+	// inputter stops reading inputs until the model is rewound.
+	RewindOnReorg bool
+	// Poll the node for new blocks instead of subscribing to them; the inputter always
+	// polls HTTP providers.
+	Polling bool
+	// Interval between checks for new blocks when polling; zero uses DefaultPollInterval.
+	PollInterval time.Duration
 }
 
 func (w InputterWorker) String() string {
@@ -41,95 +82,272 @@ func (w InputterWorker) String() string {
 }
 
 func (w InputterWorker) Start(ctx context.Context, ready chan<- struct{}) error {
-	client, err := ethclient.DialContext(ctx, w.Provider)
-	if err != nil {
-		return fmt.Errorf("inputter: dial: %w", err)
+	conn := nodeConnection{
+		name:         "inputter",
+		provider:     w.Provider,
+		polling:      w.Polling,
+		pollInterval: w.PollInterval,
 	}
-	inputBox, err := contracts.NewInputBox(w.InputBoxAddress, client)
+	// Subscribe to the new blocks before reading the past ones; so, every block added after
+	// the first read triggers another read.
+	return conn.run(ctx, ready, func(
+		ctx context.Context,
+		client *ethclient.Client,
+		heads <-chan *types.Header,
+		subErr <-chan error,
+	) error {
+		return w.readInputs(ctx, client, heads, subErr)
+	})
+}
+
+// Read the inputs from the checkpoint until the latest block each time a block arrives.
+// This function continues to run forever until there is an error or the context is canceled.
+// When the connection to the node drops, it returns a nodeError.
+func (w InputterWorker) readInputs(
+	ctx context.Context,
+	client backend,
+	heads <-chan *types.Header,
+	subErr <-chan error,
+) error {
+	inputBox, err := contracts.NewInputBoxFilterer(w.InputBoxAddress, client)
 	if err != nil {
 		return fmt.Errorf("inputter: bind input box: %w", err)
 	}
-	ready <- struct{}{}
+	var reorg *reorgError
+	reorgReported := false
+	for {
+		err := w.readNewInputs(ctx, client, inputBox)
+		if errors.As(err, &reorg) {
+			// the alert is raised once; the inputter checks the chain again on each block
+			if !reorgReported {
+				slog.Error("inputter: chain reorganization detected; "+
+					"rewind the model to the fork block to read the new inputs",
+					"forkBlock", reorg.forkBlock)
+				reorgReported = true
+			}
+		} else if errors.Is(err, errStaleLog) {
+			slog.Warn("inputter: the chain changed during the read; reading again on the next block",
+				"error", err)
+		} else if err != nil {
+			return err
+		} else {
+			reorgReported = false
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-subErr:
+			return nodeErrorf("inputter: %w", err)
+		case <-heads:
+			// a single read covers all the blocks that arrived in the meantime
+			drainHeads(heads)
+		}
+	}
+}
 
-	// First, read the event logs to get the past inputs; then, watch the event logs to get the
-	// new ones. There is a race condition where we might lose inputs sent between the
-	// readPastInputs call and the watchNewInputs call. Given that nonodo is a development node,
-	// we accept this race condition.
-	err = w.readPastInputs(ctx, client, inputBox)
-	if err != nil {
-		return err
+// Discard the blocks waiting in the channel.
+func drainHeads(heads <-chan *types.Header) {
+	for {
+		select {
+		case <-heads:
+		default:
+			return
+		}
 	}
-	return w.watchNewInputs(ctx, client, inputBox)
 }
 
-// Read inputs starting from the input box deployment block until the latest block.
-func (w InputterWorker) readPastInputs(
+// Read the inputs from the last checkpoint until the latest confirmed block, in chunks of
+// BlockRange blocks, saving the checkpoint after each chunk.
+// Return errStaleLog if the chain changed during the read.
+// The range starts at the checkpoint block, which was already read, to avoid missing the
+// logs of a block that were not available when it was read; the inputs read twice are
+// skipped by the model because they have the same index.
+// Return a reorgError if the checkpoint block is no longer in the chain.
+func (w InputterWorker) readNewInputs(
 	ctx context.Context,
-	client *ethclient.Client,
-	inputBox *contracts.InputBox,
+	client backend,
+	inputBox *contracts.InputBoxFilterer,
 ) error {
-	opts := bind.FilterOpts{
-		Context: ctx,
-		Start:   w.InputBoxBlock,
+	from, err := w.checkpoint(ctx, client)
+	if err != nil {
+		return err
 	}
-	filter := []common.Address{w.ApplicationAddress}
-	it, err := inputBox.FilterInputAdded(&opts, filter, nil)
+	latest, err := client.BlockNumber(ctx)
 	if err != nil {
-		return fmt.Errorf("inputter: filter input added: %v", err)
+		return nodeErrorf("inputter: get latest block: %w", err)
 	}
-	defer it.Close()
-	for it.Next() {
-		if err := w.addInput(ctx, client, it.Event); err != nil {
+	if latest < w.Confirmations {
+		return nil
+	}
+	latest -= w.Confirmations
+	if from > latest {
+		slog.Debug("inputter: checkpoint is ahead of the latest block",
+			"checkpoint", from, "latest", latest)
+		return nil
+	}
+	blockRange := w.BlockRange
+	if blockRange == 0 {
+		blockRange = DefaultBlockRange
+	}
+	for {
+		to := min(from+blockRange-1, latest)
+		// The header is read before the logs, so a reorganization during the read either moves
+		// the blocks of the logs, which addInput detects, or replaces the checkpoint block,
+		// which the next read detects.
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+		if err != nil {
+			return nodeErrorf("inputter: get block %v: %w", to, err)
+		}
+		err = w.readInputsInRange(ctx, client, inputBox, from, to)
+		if err != nil {
 			return err
 		}
+		err = w.Model.SetCheckpoint(checkpointName, model.Block{Number: to, Hash: header.Hash()})
+		if err != nil {
+			return fmt.Errorf("inputter: %w", err)
+		}
+		if to == latest {
+			return nil
+		}
+		from = to + 1
 	}
-	return nil
 }
 
-// Watch new inputs added to the input box.
-// This function continues to run forever until there is an error or the context is canceled.
-func (w InputterWorker) watchNewInputs(
+// Get the block where the next read starts.
+// If the checkpoint block changed, rewind the model when RewindOnReorg is set or return a
+// reorgError otherwise.
+func (w InputterWorker) checkpoint(ctx context.Context, client backend) (uint64, error) {
+	checkpoint, err := w.Model.GetCheckpoint(checkpointName)
+	if err != nil {
+		return 0, fmt.Errorf("inputter: %w", err)
+	}
+	if checkpoint == nil || checkpoint.Number < w.InputBoxBlock {
+		return w.InputBoxBlock, nil
+	}
+	if checkpoint.Hash == (common.Hash{}) {
+		// the hash is unknown after the model is rewound
+		return checkpoint.Number, nil
+	}
+	canonical, err := isCanonical(ctx, client, *checkpoint)
+	if err != nil || canonical {
+		return checkpoint.Number, err
+	}
+	forkBlock, err := w.findForkBlock(ctx, client)
+	if err != nil {
+		return 0, err
+	}
+	if !w.RewindOnReorg {
+		return 0, &reorgError{forkBlock: forkBlock}
+	}
+	slog.Warn("inputter: chain reorganization detected; rewinding the model",
+		"forkBlock", forkBlock)
+	_, err = w.Model.RewindInputs(forkBlock)
+	if err != nil {
+		return 0, fmt.Errorf("inputter: %w", err)
+	}
+	return max(forkBlock, w.InputBoxBlock), nil
+}
+
+// Find the latest block with inputs that is still in the chain.
+// If there is none, return the block before the earliest block with inputs.
+func (w InputterWorker) findForkBlock(ctx context.Context, client backend) (uint64, error) {
+	blocks, err := w.Model.GetInputBlocks()
+	if err != nil {
+		return 0, fmt.Errorf("inputter: %w", err)
+	}
+	if len(blocks) == 0 {
+		return w.InputBoxBlock, nil
+	}
+	for _, block := range blocks {
+		canonical, err := isCanonical(ctx, client, block)
+		if err != nil {
+			return 0, err
+		}
+		if canonical {
+			return block.Number, nil
+		}
+	}
+	earliest := blocks[len(blocks)-1].Number
+	if earliest == 0 {
+		return 0, nil
+	}
+	return earliest - 1, nil
+}
+
+// Check whether the block is in the chain.
+func isCanonical(ctx context.Context, client backend, block model.Block) (bool, error) {
+	header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(block.Number))
+	if errors.Is(err, ethereum.NotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, nodeErrorf("inputter: get block %v: %w", block.Number, err)
+	}
+	return header.Hash() == block.Hash, nil
+}
+
+// Get the header of the block of the log, checking that the block is still in the chain.
+func logHeader(ctx context.Context, client backend, log types.Log) (*types.Header, error) {
+	header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, fmt.Errorf("block %v: %w", log.BlockNumber, errStaleLog)
+	}
+	if err != nil {
+		return nil, nodeErrorf("get block %v: %w", log.BlockNumber, err)
+	}
+	if header.Hash() != log.BlockHash {
+		return nil, fmt.Errorf("block %v: %w", log.BlockNumber, errStaleLog)
+	}
+	return header, nil
+}
+
+// Read the inputs added to the input box between the given blocks, inclusive.
+func (w InputterWorker) readInputsInRange(
 	ctx context.Context,
-	client *ethclient.Client,
-	inputBox *contracts.InputBox,
+	client backend,
+	inputBox *contracts.InputBoxFilterer,
+	from uint64,
+	to uint64,
 ) error {
-	logs := make(chan *contracts.InputBoxInputAdded)
-	opts := bind.WatchOpts{
+	opts := bind.FilterOpts{
 		Context: ctx,
+		Start:   from,
+		End:     &to,
 	}
 	filter := []common.Address{w.ApplicationAddress}
-	sub, err := inputBox.WatchInputAdded(&opts, logs, filter, nil)
+	it, err := inputBox.FilterInputAdded(&opts, filter, nil)
 	if err != nil {
-		return fmt.Errorf("inputter: watch input added: %w", err)
+		return nodeErrorf("inputter: filter input added: %w", err)
 	}
-	defer sub.Unsubscribe()
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case err := <-sub.Err():
+	defer it.Close()
+	for it.Next() {
+		if err := w.addInput(ctx, client, it.Event); err != nil {
 			return err
-		case event := <-logs:
-			if err := w.addInput(ctx, client, event); err != nil {
-				return err
-			}
 		}
 	}
+	if err := it.Error(); err != nil {
+		return nodeErrorf("inputter: filter input added: %w", err)
+	}
+	return nil
 }
 
 // Add the input to the model.
 func (w InputterWorker) addInput(
 	ctx context.Context,
-	client *ethclient.Client,
+	client backend,
 	event *contracts.InputBoxInputAdded,
 ) error {
-	header, err := client.HeaderByHash(ctx, event.Raw.BlockHash)
+	header, err := logHeader(ctx, client, event.Raw)
 	if err != nil {
-		return fmt.Errorf("inputter: failed to get tx header: %w", err)
+		return fmt.Errorf("inputter: %w", err)
 	}
 	timestamp := time.Unix(int64(header.Time), 0)
 
 	// use abi to decode the input
+	if len(event.Input) < 4 {
+		return fmt.Errorf("inputter: input %v is too short to be an EvmAdvance: %d bytes",
+			event.Index, len(event.Input))
+	}
 	eventInput := event.Input[4:]
 	abi, err := contracts.InputsMetaData.GetAbi()
 
@@ -145,7 +363,10 @@ func (w InputterWorker) addInput(
 		return err
 	}
 
+	chainId := values[0].(*big.Int)
+	appContract := values[1].(common.Address)
 	msgSender := values[2].(common.Address)
+	prevRandao := values[5].(*big.Int)
 	payload := values[7].([]uint8)
 	inputIndex := int(event.Index.Int64())
 
@@ -153,6 +374,7 @@ func (w InputterWorker) addInput(
 		"dapp", event.AppContract,
 		"input.index", event.Index,
 		"sender", msgSender,
+		"tx", event.Raw.TxHash,
 		"input", event.Input,
 		"payload", payload,
 		slog.Group("block",
@@ -161,12 +383,21 @@ func (w InputterWorker) addInput(
 		),
 	)
 
-	w.Model.AddAdvanceInput(
-		msgSender,
-		payload,
-		event.Raw.BlockNumber,
-		timestamp,
-		inputIndex,
-	)
+	err = w.Model.AddEvmAdvanceInput(model.AdvanceInput{
+		Index:           inputIndex,
+		MsgSender:       msgSender,
+		Payload:         payload,
+		BlockNumber:     event.Raw.BlockNumber,
+		BlockHash:       event.Raw.BlockHash,
+		BlockTimestamp:  timestamp,
+		PrevRandao:      prevRandao,
+		ChainId:         chainId.Uint64(),
+		AppContract:     appContract,
+		TransactionHash: event.Raw.TxHash,
+		LogIndex:        uint64(event.Raw.Index),
+	})
+	if err != nil {
+		return fmt.Errorf("inputter: add input: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,452 @@
+package inputter
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/calindra/rollups-server/src/contracts"
+	"github.com/calindra/rollups-server/src/model"
+	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/suite"
+)
+
+const testTimeout = 5 * time.Second
+
+var (
+	testInputBox    = common.HexToAddress("0x59b22D57D4f067708AB0c00552767405926dc768")
+	testApplication = common.HexToAddress("0x45290f5A64Ea31887C7bc25fB3269173bCd01093")
+)
+
+// Node with a fixed set of InputAdded logs.
+// The headers are created on demand; a reorganization replaces the headers after a block.
+type fakeBackend struct {
+	mutex   sync.Mutex
+	latest  uint64
+	logs    []types.Log
+	ranges  [][2]uint64
+	headers map[uint64]*types.Header
+	fork    byte
+	// Error returned by the header requests, to simulate a dropped connection.
+	err error
+	// Called before each log request, to change the chain during a read.
+	onFilter func()
+}
+
+func (b *fakeBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.latest, nil
+}
+
+func (b *fakeBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.err != nil {
+		return nil, b.err
+	}
+	if number == nil {
+		return b.header(b.latest), nil
+	}
+	if number.Uint64() > b.latest {
+		return nil, ethereum.NotFound
+	}
+	return b.header(number.Uint64()), nil
+}
+
+// Get the header of the block; the caller must hold the mutex.
+func (b *fakeBackend) header(number uint64) *types.Header {
+	if b.headers == nil {
+		b.headers = make(map[uint64]*types.Header)
+	}
+	header, ok := b.headers[number]
+	if !ok {
+		header = &types.Header{
+			Number: new(big.Int).SetUint64(number),
+			Time:   1700000000 + number,
+			Extra:  []byte{b.fork},
+		}
+		b.headers[number] = header
+	}
+	return header
+}
+
+// Replace the blocks from the given one on, dropping their logs.
+func (b *fakeBackend) reorg(number uint64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.fork++
+	for n := range b.headers {
+		if n >= number {
+			delete(b.headers, n)
+		}
+	}
+	var logs []types.Log
+	for _, log := range b.logs {
+		if log.BlockNumber < number {
+			logs = append(logs, log)
+		}
+	}
+	b.logs = logs
+}
+
+func (b *fakeBackend) FilterLogs(
+	ctx context.Context, query ethereum.FilterQuery,
+) ([]types.Log, error) {
+	if b.onFilter != nil {
+		b.onFilter()
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	from, to := query.FromBlock.Uint64(), query.ToBlock.Uint64()
+	b.ranges = append(b.ranges, [2]uint64{from, to})
+	var logs []types.Log
+	for _, log := range b.logs {
+		if log.BlockNumber >= from && log.BlockNumber <= to {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (b *fakeBackend) SubscribeFilterLogs(
+	ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	return nil, errors.New("not supported")
+}
+
+// Add an input to the input box in the given block.
+func (b *fakeBackend) addInput(index int64, blockNumber uint64, payload []byte) error {
+	inputsAbi, err := contracts.InputsMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	input, err := inputsAbi.Pack("EvmAdvance", big.NewInt(31337), testApplication,
+		common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		new(big.Int).SetUint64(blockNumber), big.NewInt(1700000000), big.NewInt(7),
+		big.NewInt(index), payload)
+	if err != nil {
+		return err
+	}
+	return b.addRawInput(index, blockNumber, input)
+}
+
+// Add an input with the given encoded EvmAdvance call to the input box.
+func (b *fakeBackend) addRawInput(index int64, blockNumber uint64, input []byte) error {
+	inputBoxAbi, err := contracts.InputBoxMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	event := inputBoxAbi.Events["InputAdded"]
+	data, err := event.Inputs.NonIndexed().Pack(input)
+	if err != nil {
+		return err
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.logs = append(b.logs, types.Log{
+		Address: testInputBox,
+		Topics: []common.Hash{
+			event.ID,
+			common.BytesToHash(testApplication.Bytes()),
+			common.BigToHash(big.NewInt(index)),
+		},
+		Data:        data,
+		BlockNumber: blockNumber,
+		BlockHash:   b.header(blockNumber).Hash(),
+		TxHash:      common.BigToHash(big.NewInt(index + 100)),
+		Index:       uint(index),
+	})
+	b.latest = max(b.latest, blockNumber)
+	return nil
+}
+
+type InputterSuite struct {
+	suite.Suite
+	backend *fakeBackend
+	model   *model.AppModel
+	worker  InputterWorker
+	tempDir string
+}
+
+func (s *InputterSuite) SetupTest() {
+	util.ConfigureLog(slog.LevelDebug)
+	tempDir, err := os.MkdirTemp("", "")
+	s.Require().NoError(err)
+	s.tempDir = tempDir
+	db := sqlx.MustConnect("sqlite3", path.Join(tempDir, "inputter.sqlite3"))
+	s.model = model.NewAppModel(nil, db)
+	s.backend = &fakeBackend{}
+	s.worker = InputterWorker{
+		Model:              s.model,
+		InputBoxAddress:    testInputBox,
+		InputBoxBlock:      2,
+		ApplicationAddress: testApplication,
+		BlockRange:         10,
+	}
+}
+
+func (s *InputterSuite) TearDownTest() {
+	os.RemoveAll(s.tempDir)
+}
+
+func TestInputterSuite(t *testing.T) {
+	suite.Run(t, new(InputterSuite))
+}
+
+func (s *InputterSuite) readNewInputs() {
+	s.Require().NoError(s.tryReadNewInputs())
+}
+
+func (s *InputterSuite) tryReadNewInputs() error {
+	inputBox, err := contracts.NewInputBoxFilterer(testInputBox, s.backend)
+	s.Require().NoError(err)
+	return s.worker.readNewInputs(context.Background(), s.backend, inputBox)
+}
+
+func (s *InputterSuite) requireInputs(count int) {
+	total, err := s.model.InputRepository.Count(context.Background(), nil)
+	s.Require().NoError(err)
+	s.Require().Equal(count, int(total))
+}
+
+func (s *InputterSuite) TestReadInChunks() {
+	s.Require().NoError(s.backend.addInput(0, 3, []byte{0xaa}))
+	s.Require().NoError(s.backend.addInput(1, 12, []byte{0xbb}))
+	s.Require().NoError(s.backend.addInput(2, 25, []byte{0xcc}))
+	s.backend.latest = 30
+	s.readNewInputs()
+
+	s.Equal([][2]uint64{{2, 11}, {12, 21}, {22, 30}}, s.backend.ranges)
+	s.requireInputs(3)
+	input, err := s.model.InputRepository.FindByIndex(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Equal([]byte{0xbb}, input.Payload)
+	s.Equal(uint64(12), input.BlockNumber)
+	s.Equal(uint64(31337), input.ChainId)
+	s.Equal(testApplication, input.AppContract)
+	s.Equal(big.NewInt(7), input.PrevRandao)
+	s.Equal(common.BigToHash(big.NewInt(101)), input.TransactionHash)
+	s.Equal(uint64(1), input.LogIndex)
+	checkpoint, err := s.model.GetCheckpoint(checkpointName)
+	s.NoError(err)
+	s.Require().NotNil(checkpoint)
+	s.Equal(uint64(30), checkpoint.Number)
+	s.Equal(s.backend.header(30).Hash(), checkpoint.Hash)
+}
+
+func (s *InputterSuite) TestResumeFromCheckpoint() {
+	s.Require().NoError(s.backend.addInput(0, 3, []byte{0xaa}))
+	s.Require().NoError(s.backend.addInput(1, 12, []byte{0xbb}))
+	s.readNewInputs()
+	s.requireInputs(2)
+
+	// the next read overlaps the checkpoint block and skips the inputs read before
+	s.backend.ranges = nil
+	s.Require().NoError(s.backend.addInput(2, 12, []byte{0xcc}))
+	s.Require().NoError(s.backend.addInput(3, 14, []byte{0xdd}))
+	s.readNewInputs()
+	s.Equal([][2]uint64{{12, 14}}, s.backend.ranges)
+	s.requireInputs(4)
+	input, err := s.model.InputRepository.FindByIndex(context.Background(), 2)
+	s.Require().NoError(err)
+	s.Equal([]byte{0xcc}, input.Payload)
+}
+
+func (s *InputterSuite) TestReadOnNewBlocks() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	s.Require().NoError(s.backend.addInput(0, 3, []byte{0xaa}))
+	heads := make(chan *types.Header)
+	result := make(chan error)
+	go func() {
+		result <- s.worker.readInputs(ctx, s.backend, heads, nil)
+	}()
+
+	s.Require().NoError(s.backend.addInput(1, 4, []byte{0xbb}))
+	heads <- &types.Header{Number: big.NewInt(4)}
+	s.Eventually(func() bool {
+		total, err := s.model.InputRepository.Count(context.Background(), nil)
+		return err == nil && total == 2
+	}, testTimeout, 10*time.Millisecond)
+
+	cancel()
+	s.ErrorIs(<-result, context.Canceled)
+}
+
+func (s *InputterSuite) TestWaitForConfirmations() {
+	s.worker.Confirmations = 2
+	s.Require().NoError(s.backend.addInput(0, 10, []byte{0xaa}))
+	s.backend.latest = 11
+	s.readNewInputs()
+	s.requireInputs(0)
+
+	s.backend.latest = 12
+	s.readNewInputs()
+	s.requireInputs(1)
+}
+
+// Add two inputs and replace the block of the second one with a block that has
+// another input.
+func (s *InputterSuite) addReorgedInputs() {
+	s.Require().NoError(s.backend.addInput(0, 3, []byte{0xaa}))
+	s.Require().NoError(s.backend.addInput(1, 8, []byte{0xbb}))
+	s.backend.latest = 10
+	s.readNewInputs()
+	s.requireInputs(2)
+
+	s.backend.reorg(6)
+	s.Require().NoError(s.backend.addInput(1, 9, []byte{0xcc}))
+}
+
+func (s *InputterSuite) TestDetectReorg() {
+	s.addReorgedInputs()
+	var reorg *reorgError
+	err := s.tryReadNewInputs()
+	s.Require().ErrorAs(err, &reorg)
+	s.Equal(uint64(3), reorg.forkBlock)
+	input, err := s.model.InputRepository.FindByIndex(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Equal([]byte{0xbb}, input.Payload)
+
+	removed, err := s.model.RewindInputs(reorg.forkBlock)
+	s.NoError(err)
+	s.Equal(1, removed)
+	s.readNewInputs()
+	input, err = s.model.InputRepository.FindByIndex(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Equal([]byte{0xcc}, input.Payload)
+	s.Equal(uint64(9), input.BlockNumber)
+	s.Equal(s.backend.header(9).Hash(), input.BlockHash)
+}
+
+func (s *InputterSuite) TestDetectReorgDuringRead() {
+	s.worker.BlockRange = 100
+	s.Require().NoError(s.backend.addInput(0, 3, []byte{0xaa}))
+	s.Require().NoError(s.backend.addInput(1, 25, []byte{0xbb}))
+	s.backend.latest = 30
+	s.backend.onFilter = func() {
+		s.backend.onFilter = nil
+		s.backend.reorg(20)
+	}
+	s.readNewInputs()
+	s.requireInputs(1)
+
+	// the checkpoint has the block read before the reorganization
+	var reorg *reorgError
+	err := s.tryReadNewInputs()
+	s.Require().ErrorAs(err, &reorg)
+	s.Equal(uint64(3), reorg.forkBlock)
+}
+
+func (s *InputterSuite) TestStaleLog() {
+	s.Require().NoError(s.backend.addInput(0, 3, []byte{0xaa}))
+	s.backend.latest = 10
+	// replace the block of the input without removing its log
+	s.backend.fork++
+	delete(s.backend.headers, 3)
+
+	err := s.tryReadNewInputs()
+	s.ErrorIs(err, errStaleLog)
+	s.requireInputs(0)
+	checkpoint, err := s.model.GetCheckpoint(checkpointName)
+	s.NoError(err)
+	s.Nil(checkpoint)
+}
+
+func (s *InputterSuite) TestShortInput() {
+	s.Require().NoError(s.backend.addRawInput(0, 3, []byte{0x41, 0x5b}))
+	s.backend.latest = 10
+
+	err := s.tryReadNewInputs()
+	s.ErrorContains(err, "too short")
+	s.requireInputs(0)
+}
+
+func (s *InputterSuite) TestRewindOnReorg() {
+	s.worker.RewindOnReorg = true
+	s.addReorgedInputs()
+	s.readNewInputs()
+	s.requireInputs(2)
+	input, err := s.model.InputRepository.FindByIndex(context.Background(), 1)
+	s.Require().NoError(err)
+	s.Equal([]byte{0xcc}, input.Payload)
+}
+
+func (s *InputterSuite) TestPollNewBlocks() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	s.Require().NoError(s.backend.addInput(0, 3, []byte{0xaa}))
+	conn := nodeConnection{name: "inputter", pollInterval: 10 * time.Millisecond}
+	heads := make(chan *types.Header)
+	sub := conn.pollNewHeads(ctx, s.backend, heads)
+	defer sub.Unsubscribe()
+	result := make(chan error)
+	go func() {
+		result <- s.worker.readInputs(ctx, s.backend, heads, sub.Err())
+	}()
+
+	s.Require().NoError(s.backend.addInput(1, 4, []byte{0xbb}))
+	s.Eventually(func() bool {
+		total, err := s.model.InputRepository.Count(context.Background(), nil)
+		return err == nil && total == 2
+	}, testTimeout, 10*time.Millisecond)
+
+	cancel()
+	s.ErrorIs(<-result, context.Canceled)
+}
+
+func (s *InputterSuite) TestPollFailure() {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	s.Require().NoError(s.backend.addInput(0, 3, []byte{0xaa}))
+	conn := nodeConnection{name: "inputter", pollInterval: 10 * time.Millisecond}
+	heads := make(chan *types.Header)
+	sub := conn.pollNewHeads(ctx, s.backend, heads)
+	defer sub.Unsubscribe()
+	result := make(chan error)
+	go func() {
+		result <- s.worker.readInputs(ctx, s.backend, heads, sub.Err())
+	}()
+
+	s.Eventually(func() bool {
+		total, err := s.model.InputRepository.Count(context.Background(), nil)
+		return err == nil && total == 1
+	}, testTimeout, 10*time.Millisecond)
+
+	// the worker reconnects on node errors instead of stopping
+	connErr := errors.New("connection refused")
+	s.backend.mutex.Lock()
+	s.backend.err = connErr
+	s.backend.mutex.Unlock()
+	var nodeErr *nodeError
+	err := <-result
+	s.ErrorAs(err, &nodeErr)
+	s.ErrorIs(err, connErr)
+	s.requireInputs(1)
+}
+
+func TestIsHttp(t *testing.T) {
+	for provider, expected := range map[string]bool{
+		"http://localhost:8545":        true,
+		"https://eth.example.com/v1/x": true,
+		"ws://localhost:8545":          false,
+		"wss://eth.example.com":        false,
+		"":                             false,
+	} {
+		if isHttp(provider) != expected {
+			t.Errorf("isHttp(%q) should be %v", provider, expected)
+		}
+	}
+}
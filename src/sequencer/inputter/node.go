@@ -0,0 +1,168 @@
+// (c) Cartesi and individual authors (see AUTHORS)
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+package inputter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Default interval between checks for new blocks when polling the node.
+const DefaultPollInterval = time.Second
+
+// Time to wait before connecting to the node again after the connection drops.
+const reconnectDelay = time.Second
+
+// Error returned when a call to the node fails.
+// The workers reconnect to the node instead of returning it.
+type nodeError struct {
+	err error
+}
+
+func (e *nodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *nodeError) Unwrap() error {
+	return e.err
+}
+
+func nodeErrorf(format string, args ...any) error {
+	return &nodeError{err: fmt.Errorf(format, args...)}
+}
+
+// Function that reads from the node each time a new block arrives, until there is an error.
+type readFunc func(
+	ctx context.Context,
+	client *ethclient.Client,
+	heads <-chan *types.Header,
+	subErr <-chan error,
+) error
+
+// Settings of the connection to the node shared by the workers.
+type nodeConnection struct {
+	name     string
+	provider string
+	// Poll the node for new blocks instead of subscribing to them.
+	polling      bool
+	pollInterval time.Duration
+}
+
+// Connect to the node and call read with the new blocks.
+// When read fails with a nodeError, connect again and call read once more; any other error
+// is returned. The ready channel is signaled after the first connection.
+func (c nodeConnection) run(ctx context.Context, ready chan<- struct{}, read readFunc) error {
+	client, sub, heads, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	ready <- struct{}{}
+	for {
+		err = read(ctx, client, heads, sub.Err())
+		sub.Unsubscribe()
+		client.Close()
+		var nodeErr *nodeError
+		if !errors.As(err, &nodeErr) || ctx.Err() != nil {
+			return err
+		}
+		slog.Warn(c.name+": lost connection to the node; reconnecting", "error", err)
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(reconnectDelay):
+			}
+			client, sub, heads, err = c.connect(ctx)
+			if err == nil {
+				break
+			}
+			slog.Warn(c.name+": failed to reconnect to the node", "error", err)
+		}
+		slog.Info(c.name + ": reconnected to the node")
+	}
+}
+
+// Dial the node and subscribe to the new blocks.
+// Poll the node when the polling is set, when the provider uses HTTP or when the node does
+// not support subscriptions.
+func (c nodeConnection) connect(ctx context.Context) (
+	*ethclient.Client, ethereum.Subscription, chan *types.Header, error,
+) {
+	client, err := ethclient.DialContext(ctx, c.provider)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%v: dial: %w", c.name, err)
+	}
+	heads := make(chan *types.Header)
+	if c.polling || isHttp(c.provider) {
+		return client, c.pollNewHeads(ctx, client, heads), heads, nil
+	}
+	sub, err := client.SubscribeNewHead(ctx, heads)
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		slog.Info(c.name + ": node does not support subscriptions; polling for new blocks")
+		return client, c.pollNewHeads(ctx, client, heads), heads, nil
+	}
+	if err != nil {
+		client.Close()
+		return nil, nil, nil, fmt.Errorf("%v: subscribe new head: %w", c.name, err)
+	}
+	return client, sub, heads, nil
+}
+
+// Send the latest header to the channel each time the latest block changes, checking it
+// at the poll interval. The subscription fails when the node cannot be reached.
+func (c nodeConnection) pollNewHeads(
+	ctx context.Context,
+	client backend,
+	heads chan<- *types.Header,
+) ethereum.Subscription {
+	interval := c.pollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var latest uint64
+		for {
+			select {
+			case <-quit:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+			header, err := client.HeaderByNumber(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("poll latest block: %w", err)
+			}
+			if header.Number.Uint64() <= latest {
+				continue
+			}
+			latest = header.Number.Uint64()
+			select {
+			case <-quit:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			case heads <- header:
+			}
+		}
+	})
+}
+
+// Check whether the provider URL uses HTTP.
+func isHttp(provider string) bool {
+	u, err := url.Parse(provider)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
@@ -1,6 +1,9 @@
 package sequencer
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/calindra/rollups-server/src/model"
 )
 
@@ -20,11 +23,13 @@ type EspressoSequencer struct {
 	model *model.AppModel
 }
 
-func (es *EspressoSequencer) FinishAndGetNext(accept bool) model.Input {
+func (es *EspressoSequencer) FinishAndGetNext(accept bool) (model.Input, error) {
 	return FinishAndGetNext(es.model, accept)
 }
 
-func FinishAndGetNext(m *model.AppModel, accept bool) model.Input {
+// Finish the current input and get the next one.
+// If finishing the current input fails, the state is kept so the finish can be retried.
+func FinishAndGetNext(m *model.AppModel, accept bool) (model.Input, error) {
 	m.Mutex.Lock()
 	defer m.Mutex.Unlock()
 
@@ -35,20 +40,27 @@ func FinishAndGetNext(m *model.AppModel, accept bool) model.Input {
 	} else {
 		status = model.CompletionStatusRejected
 	}
-	m.State.Finish(status)
+	err := m.State.Finish(status)
+	if err != nil {
+		return nil, fmt.Errorf("finish input: %w", err)
+	}
+	m.State = model.NewRollupsStateIdle()
+	m.NotifyCompletedInputs()
 
 	// try to get first unprocessed inspect
-	for _, input := range m.Inspects {
-		if input.Status == model.CompletionStatusUnprocessed {
-			m.State = model.NewRollupsStateInspect(input, m.GetProcessedInputCount)
-			return *input
-		}
+	inspect, err := m.InspectRepository.FindByStatus(model.CompletionStatusUnprocessed)
+	if err != nil {
+		return nil, fmt.Errorf("find next inspect: %w", err)
+	}
+	if inspect != nil {
+		m.State = model.NewRollupsStateInspect(inspect, m.InspectRepository, m.GetProcessedInputCount)
+		return *inspect, nil
 	}
 
 	// try to get first unprocessed advance
-	input, err := m.InputRepository.FindByStatus(model.CompletionStatusUnprocessed)
+	input, err := m.InputRepository.FindByStatus(context.Background(), model.CompletionStatusUnprocessed)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("find next advance: %w", err)
 	}
 	if input != nil {
 		m.State = model.NewRollupsStateAdvance(
@@ -56,19 +68,20 @@ func FinishAndGetNext(m *model.AppModel, accept bool) model.Input {
 			m.Decoder,
 			m.ReportRepository,
 			m.InputRepository,
+			m.ProofRepository,
+			m.EpochRepository,
 		)
-		return *input
+		return *input, nil
 	}
 
-	// if no input was found, set state to idle
-	m.State = model.NewRollupsStateIdle()
-	return nil
+	// if no input was found, the state stays idle
+	return nil, nil
 }
 
-func (ibs *InputBoxSequencer) FinishAndGetNext(accept bool) model.Input {
+func (ibs *InputBoxSequencer) FinishAndGetNext(accept bool) (model.Input, error) {
 	return FinishAndGetNext(ibs.model, accept)
 }
 
 type Sequencer interface {
-	FinishAndGetNext(accept bool) model.Input
+	FinishAndGetNext(accept bool) (model.Input, error)
 }
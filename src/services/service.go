@@ -2,23 +2,29 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/calindra/rollups-server/src/model"
 	"github.com/calindra/rollups-server/src/util"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type ConvenienceService struct {
 	voucherRepository *model.VoucherRepository
 	noticeRepository  *model.NoticeRepository
+	depositRepository *model.DepositRepository
 }
 
 func NewConvenienceService(
 	voucherRepository *model.VoucherRepository,
 	noticeRepository *model.NoticeRepository,
+	depositRepository *model.DepositRepository,
 ) *ConvenienceService {
 	return &ConvenienceService{
 		voucherRepository: voucherRepository,
 		noticeRepository:  noticeRepository,
+		depositRepository: depositRepository,
 	}
 }
 
@@ -80,6 +86,106 @@ func (c *ConvenienceService) UpdateExecuted(
 	)
 }
 
+// Mark the voucher as executed by the transaction in the block.
+// Return false when the voucher does not exist.
+func (c *ConvenienceService) MarkVoucherExecuted(
+	ctx context.Context,
+	inputIndex uint64,
+	outputIndex uint64,
+	txHash common.Hash,
+	blockNumber uint64,
+) (bool, error) {
+	return c.voucherRepository.MarkExecuted(
+		ctx,
+		inputIndex,
+		outputIndex,
+		txHash,
+		blockNumber,
+	)
+}
+
+// Delete the vouchers and notices of the inputs from the given index on.
+func (c *ConvenienceService) DeleteOutputsFrom(ctx context.Context, inputIndex uint64) error {
+	err := c.voucherRepository.DeleteFrom(ctx, inputIndex)
+	if err != nil {
+		return fmt.Errorf("delete vouchers: %w", err)
+	}
+	err = c.noticeRepository.DeleteFrom(ctx, inputIndex)
+	if err != nil {
+		return fmt.Errorf("delete notices: %w", err)
+	}
+	return nil
+}
+
+// Store the deposit decoded from an input; the deposits read twice are skipped.
+func (c *ConvenienceService) CreateDeposit(
+	ctx context.Context,
+	deposit *model.ConvenienceDeposit,
+) (*model.ConvenienceDeposit, error) {
+	depositInDb, err := c.depositRepository.FindByInputIndex(ctx, deposit.InputIndex)
+	if err != nil {
+		return nil, err
+	}
+	if depositInDb != nil {
+		return depositInDb, nil
+	}
+	return c.depositRepository.Create(ctx, deposit)
+}
+
+// Delete the deposits of the inputs from the given index on.
+func (c *ConvenienceService) DeleteDepositsFrom(ctx context.Context, inputIndex uint64) error {
+	err := c.depositRepository.DeleteFrom(ctx, inputIndex)
+	if err != nil {
+		return fmt.Errorf("delete deposits: %w", err)
+	}
+	return nil
+}
+
+// Store the call of the voucher decoded with the ABI registry.
+func (c *ConvenienceService) SetVoucherDecodedCall(
+	ctx context.Context,
+	inputIndex uint64,
+	outputIndex uint64,
+	function string,
+	args json.RawMessage,
+) error {
+	return c.voucherRepository.SetDecodedCall(ctx, inputIndex, outputIndex, function, args)
+}
+
+// Store the fields of the notice decoded with the ABI registry.
+func (c *ConvenienceService) SetNoticeDecodedFields(
+	ctx context.Context,
+	inputIndex uint64,
+	outputIndex uint64,
+	schema string,
+	fields json.RawMessage,
+) error {
+	return c.noticeRepository.SetDecodedFields(ctx, inputIndex, outputIndex, schema, fields)
+}
+
+// Find the vouchers that were not simulated yet in output order.
+func (c *ConvenienceService) FindVouchersToSimulate(
+	ctx context.Context,
+	limit int,
+) ([]model.ConvenienceVoucher, error) {
+	return c.voucherRepository.FindVouchersToSimulate(ctx, limit)
+}
+
+// Store the result of the voucher simulation.
+func (c *ConvenienceService) SetVoucherSimulation(
+	ctx context.Context,
+	inputIndex uint64,
+	outputIndex uint64,
+	simulation model.VoucherSimulation,
+) error {
+	return c.voucherRepository.SetSimulation(
+		ctx,
+		inputIndex,
+		outputIndex,
+		simulation,
+	)
+}
+
 func (c *ConvenienceService) FindAllVouchers(
 	ctx context.Context,
 	first *int,
@@ -87,6 +193,7 @@ func (c *ConvenienceService) FindAllVouchers(
 	after *string,
 	before *string,
 	filter []*model.ConvenienceFilter,
+	options ...util.PageOption,
 ) (*util.PageResult[model.ConvenienceVoucher], error) {
 	return c.voucherRepository.FindAllVouchers(
 		ctx,
@@ -95,6 +202,7 @@ func (c *ConvenienceService) FindAllVouchers(
 		after,
 		before,
 		filter,
+		options...,
 	)
 }
 
@@ -105,6 +213,7 @@ func (c *ConvenienceService) FindAllNotices(
 	after *string,
 	before *string,
 	filter []*model.ConvenienceFilter,
+	options ...util.PageOption,
 ) (*util.PageResult[model.ConvenienceNotice], error) {
 	return c.noticeRepository.FindAllNotices(
 		ctx,
@@ -113,9 +222,26 @@ func (c *ConvenienceService) FindAllNotices(
 		after,
 		before,
 		filter,
+		options...,
 	)
 }
 
+// Count the vouchers that match the filter.
+func (c *ConvenienceService) CountVouchers(
+	ctx context.Context,
+	filter []*model.ConvenienceFilter,
+) (uint64, error) {
+	return c.voucherRepository.Count(ctx, filter)
+}
+
+// Count the notices that match the filter.
+func (c *ConvenienceService) CountNotices(
+	ctx context.Context,
+	filter []*model.ConvenienceFilter,
+) (uint64, error) {
+	return c.noticeRepository.Count(ctx, filter)
+}
+
 func (c *ConvenienceService) FindVoucherByInputAndOutputIndex(
 	ctx context.Context, inputIndex uint64, outputIndex uint64,
 ) (*model.ConvenienceVoucher, error) {
@@ -131,3 +257,29 @@ func (c *ConvenienceService) FindNoticeByInputAndOutputIndex(
 		ctx, inputIndex, outputIndex,
 	)
 }
+
+func (c *ConvenienceService) FindAllDeposits(
+	ctx context.Context,
+	first *int,
+	last *int,
+	after *string,
+	before *string,
+	filter []*model.ConvenienceFilter,
+	options ...util.PageOption,
+) (*util.PageResult[model.ConvenienceDeposit], error) {
+	return c.depositRepository.FindAllDeposits(
+		ctx,
+		first,
+		last,
+		after,
+		before,
+		filter,
+		options...,
+	)
+}
+
+func (c *ConvenienceService) FindDepositByInputIndex(
+	ctx context.Context, inputIndex uint64,
+) (*model.ConvenienceDeposit, error) {
+	return c.depositRepository.FindByInputIndex(ctx, inputIndex)
+}
@@ -0,0 +1,156 @@
+// This package contains the worker that simulates the vouchers against the chain state.
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Default interval between checks for new vouchers.
+const DefaultPollInterval = time.Second
+
+// Maximum number of vouchers simulated in each check.
+const batchSize = 100
+
+type VoucherService interface {
+	FindVouchersToSimulate(ctx context.Context, limit int) ([]mdl.ConvenienceVoucher, error)
+	SetVoucherSimulation(
+		ctx context.Context,
+		inputIndex uint64,
+		outputIndex uint64,
+		simulation mdl.VoucherSimulation,
+	) error
+}
+
+// Node calls used to simulate the vouchers.
+type backend interface {
+	ethereum.ContractCaller
+	ethereum.GasEstimator
+}
+
+// This worker simulates each new voucher with an eth_call from the application to the
+// voucher destination on top of the latest block, and stores the result in the service.
+type SimulatorWorker struct {
+	Service            VoucherService
+	Provider           string
+	ApplicationAddress common.Address
+	PollInterval       time.Duration
+}
+
+func (w SimulatorWorker) String() string {
+	return "simulator"
+}
+
+func (w SimulatorWorker) Start(ctx context.Context, ready chan<- struct{}) error {
+	client, err := ethclient.DialContext(ctx, w.Provider)
+	if err != nil {
+		return fmt.Errorf("simulator: dial: %w", err)
+	}
+	defer client.Close()
+
+	interval := w.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	ready <- struct{}{}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			err := w.simulateVouchers(ctx, client)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Simulate the vouchers that were not simulated yet.
+// When the node cannot be reached, the vouchers are simulated again later.
+func (w SimulatorWorker) simulateVouchers(ctx context.Context, client backend) error {
+	vouchers, err := w.Service.FindVouchersToSimulate(ctx, batchSize)
+	if err != nil {
+		return fmt.Errorf("simulator: find vouchers: %w", err)
+	}
+	for _, voucher := range vouchers {
+		simulation, err := w.simulate(ctx, client, voucher)
+		if err != nil {
+			slog.Warn("simulator: failed to simulate voucher", "input", voucher.InputIndex,
+				"output", voucher.OutputIndex, "error", err)
+			return nil
+		}
+		err = w.Service.SetVoucherSimulation(ctx, voucher.InputIndex, voucher.OutputIndex, *simulation)
+		if err != nil {
+			return fmt.Errorf("simulator: set voucher simulation: %w", err)
+		}
+		if simulation.Success {
+			slog.Debug("simulator: voucher would succeed", "input", voucher.InputIndex,
+				"output", voucher.OutputIndex, "gas", simulation.GasEstimate)
+		} else {
+			slog.Warn("simulator: voucher would fail", "input", voucher.InputIndex,
+				"output", voucher.OutputIndex, "error", simulation.Error,
+				"revertData", hexutil.Encode(simulation.RevertData))
+		}
+	}
+	return nil
+}
+
+// Call the voucher destination from the application with the voucher value and payload.
+// Return an error only when the node cannot run the call.
+func (w SimulatorWorker) simulate(
+	ctx context.Context,
+	client backend,
+	voucher mdl.ConvenienceVoucher,
+) (*mdl.VoucherSimulation, error) {
+	msg := ethereum.CallMsg{
+		From:  w.ApplicationAddress,
+		To:    &voucher.Destination,
+		Value: voucher.Value,
+		Data:  common.FromHex(voucher.Payload),
+	}
+	_, err := client.CallContract(ctx, msg, nil)
+	if err != nil {
+		return failedSimulation(err)
+	}
+	gas, err := client.EstimateGas(ctx, msg)
+	if err != nil {
+		return failedSimulation(err)
+	}
+	return &mdl.VoucherSimulation{
+		Success:     true,
+		GasEstimate: gas,
+	}, nil
+}
+
+// Convert the error of the call to a failed simulation.
+// Only the errors returned by the node count as failures; the others are returned.
+func failedSimulation(err error) (*mdl.VoucherSimulation, error) {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return nil, err
+	}
+	simulation := &mdl.VoucherSimulation{
+		Success: false,
+		Error:   err.Error(),
+	}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if hexData, ok := dataErr.ErrorData().(string); ok {
+			simulation.RevertData = common.FromHex(hexData)
+		}
+	}
+	return simulation, nil
+}
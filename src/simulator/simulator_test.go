@@ -0,0 +1,121 @@
+package simulator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	mdl "github.com/calindra/rollups-server/src/model"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/suite"
+)
+
+// Error returned by the node when the call reverts.
+type revertError struct {
+	data string
+}
+
+func (e revertError) Error() string          { return "execution reverted" }
+func (e revertError) ErrorCode() int         { return 3 }
+func (e revertError) ErrorData() interface{} { return e.data }
+
+// Node that returns the same error for every call.
+type fakeBackend struct {
+	err error
+	gas uint64
+	msg ethereum.CallMsg
+}
+
+func (b *fakeBackend) CallContract(
+	ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int,
+) ([]byte, error) {
+	b.msg = msg
+	return nil, b.err
+}
+
+func (b *fakeBackend) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return b.gas, b.err
+}
+
+// Service that keeps the simulations in memory.
+type fakeService struct {
+	vouchers    []mdl.ConvenienceVoucher
+	simulations map[uint64]mdl.VoucherSimulation
+}
+
+func (s *fakeService) FindVouchersToSimulate(
+	ctx context.Context, limit int,
+) ([]mdl.ConvenienceVoucher, error) {
+	var vouchers []mdl.ConvenienceVoucher
+	for _, voucher := range s.vouchers {
+		if _, ok := s.simulations[voucher.OutputIndex]; !ok {
+			vouchers = append(vouchers, voucher)
+		}
+	}
+	return vouchers, nil
+}
+
+func (s *fakeService) SetVoucherSimulation(
+	ctx context.Context, inputIndex uint64, outputIndex uint64, simulation mdl.VoucherSimulation,
+) error {
+	s.simulations[outputIndex] = simulation
+	return nil
+}
+
+type SimulatorSuite struct {
+	suite.Suite
+	backend *fakeBackend
+	service *fakeService
+	worker  SimulatorWorker
+}
+
+func (s *SimulatorSuite) SetupTest() {
+	s.backend = &fakeBackend{}
+	s.service = &fakeService{
+		vouchers: []mdl.ConvenienceVoucher{{
+			Destination: common.HexToAddress("0xfafa"),
+			Value:       big.NewInt(7),
+			Payload:     "0xdeadbeef",
+			OutputIndex: 1,
+		}},
+		simulations: make(map[uint64]mdl.VoucherSimulation),
+	}
+	s.worker = SimulatorWorker{
+		Service:            s.service,
+		ApplicationAddress: common.HexToAddress("0xabab"),
+	}
+}
+
+func TestSimulatorSuite(t *testing.T) {
+	suite.Run(t, new(SimulatorSuite))
+}
+
+func (s *SimulatorSuite) TestSuccess() {
+	s.backend.gas = 21000
+	err := s.worker.simulateVouchers(context.Background(), s.backend)
+	s.Require().NoError(err)
+	s.Equal(mdl.VoucherSimulation{Success: true, GasEstimate: 21000}, s.service.simulations[1])
+	s.Equal(common.HexToAddress("0xabab"), s.backend.msg.From)
+	s.Equal(common.HexToAddress("0xfafa"), *s.backend.msg.To)
+	s.Equal(big.NewInt(7), s.backend.msg.Value)
+	s.Equal(common.FromHex("0xdeadbeef"), s.backend.msg.Data)
+}
+
+func (s *SimulatorSuite) TestRevert() {
+	s.backend.err = revertError{data: "0xe450d38c"}
+	err := s.worker.simulateVouchers(context.Background(), s.backend)
+	s.Require().NoError(err)
+	simulation := s.service.simulations[1]
+	s.False(simulation.Success)
+	s.Equal(common.FromHex("0xe450d38c"), simulation.RevertData)
+	s.Equal("execution reverted", simulation.Error)
+}
+
+func (s *SimulatorSuite) TestNodeUnavailable() {
+	s.backend.err = errors.New("connection refused")
+	err := s.worker.simulateVouchers(context.Background(), s.backend)
+	s.Require().NoError(err)
+	s.Empty(s.service.simulations)
+}
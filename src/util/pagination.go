@@ -7,7 +7,9 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 const DefaultPaginationLimit = 1000
@@ -17,96 +19,163 @@ var ErrMixedPagination = errors.New(
 var ErrInvalidCursor = errors.New("invalid pagination cursor")
 var ErrInvalidLimit = errors.New("limit cannot be negative")
 
+// Position of a row in the (input_index, output_index) ordering.
+// Tables without outputs, like the inputs, leave the OutputIndex as zero.
+type Cursor struct {
+	InputIndex  uint64
+	OutputIndex uint64
+}
+
+// Page to be fetched with a keyset query.
+type Page struct {
+	// Whether the rows are fetched in ascending order.
+	Forward bool
+	// Maximum number of rows in the page.
+	Limit int
+	// Rows must be strictly after (when forward) or before (when backward) this cursor.
+	Cursor *Cursor
+	// Whether the total number of rows that match the filter is counted.
+	WithTotal bool
+}
+
+// Option of a paginated query.
+type PageOption func(page *Page)
+
+// Count the rows that match the filter into the Total of the page result.
+// The count runs one more query, so it is only done when requested.
+func WithTotal() PageOption {
+	return func(page *Page) {
+		page.WithTotal = true
+	}
+}
+
 type PageResult[T any] struct {
-	Total  uint64
-	Offset uint64
-	Rows   []T
+	Rows            []T
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     *string
+	EndCursor       *string
+	// Number of rows that match the filter in all pages; only set with WithTotal.
+	Total *uint64
 }
 
-// Compute the pagination parameters given the GraphQL connection parameters.
+// Compute the page given the GraphQL connection parameters.
 func ComputePage(
-	first *int, last *int, after *string, before *string, total int,
-) (offset int, limit int, err error) {
+	first *int, last *int, after *string, before *string, options ...PageOption,
+) (*Page, error) {
 	forward := first != nil || after != nil
 	backward := last != nil || before != nil
 	if forward && backward {
-		return 0, 0, ErrMixedPagination
+		return nil, ErrMixedPagination
 	}
 	if !forward && !backward {
 		// If nothing was set, use forward pagination by default
 		forward = true
 	}
+	var page *Page
+	var err error
 	if forward {
-		return computeForwardPage(first, after, total)
+		page, err = computePage(true, first, after)
 	} else {
-		return computeBackwardPage(last, before, total)
+		page, err = computePage(false, last, before)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(page)
 	}
+	return page, nil
 }
 
-// Compute the pagination parameters when paginating forward
-func computeForwardPage(first *int, after *string, total int) (offset int, limit int, err error) {
-	if first != nil {
-		if *first < 0 {
-			return 0, 0, ErrInvalidLimit
+func computePage(forward bool, limit *int, cursor *string) (*Page, error) {
+	page := &Page{
+		Forward: forward,
+		Limit:   DefaultPaginationLimit,
+	}
+	if limit != nil {
+		if *limit < 0 {
+			return nil, ErrInvalidLimit
 		}
-		limit = *first
-	} else {
-		limit = DefaultPaginationLimit
+		page.Limit = *limit
 	}
-	if after != nil {
-		offset, err = DecodeCursor(*after, total)
+	if cursor != nil {
+		decoded, err := DecodeCursor(*cursor)
 		if err != nil {
-			return 0, 0, err
+			return nil, err
 		}
-		offset = offset + 1
-	} else {
-		offset = 0
+		page.Cursor = &decoded
 	}
-	limit = min(limit, total-offset)
-	return offset, limit, nil
+	return page, nil
 }
 
-// Compute the pagination parameters when paginating backward
-func computeBackwardPage(last *int, before *string, total int) (offset int, limit int, err error) {
-	if last != nil {
-		if *last < 0 {
-			return 0, 0, ErrInvalidLimit
-		}
-		limit = *last
-	} else {
-		limit = DefaultPaginationLimit
+// Build the page result from the rows fetched with the page.
+// The query should fetch one row beyond the limit to tell whether there are more rows,
+// and the backward pages should be fetched in descending order.
+func NewPageResult[T any](page *Page, rows []T, cursor func(row T) Cursor) *PageResult[T] {
+	hasMore := len(rows) > page.Limit
+	if hasMore {
+		rows = rows[:page.Limit]
 	}
-	var beforeOffset int
-	if before != nil {
-		beforeOffset, err = DecodeCursor(*before, total)
-		if err != nil {
-			return 0, 0, err
-		}
+	if !page.Forward {
+		slices.Reverse(rows)
+	}
+	result := &PageResult[T]{
+		Rows: rows,
+	}
+	if page.Forward {
+		result.HasNextPage = hasMore
+		result.HasPreviousPage = page.Cursor != nil
 	} else {
-		beforeOffset = total
+		result.HasNextPage = page.Cursor != nil
+		result.HasPreviousPage = hasMore
+	}
+	if len(rows) > 0 {
+		start := EncodeCursor(cursor(rows[0]))
+		end := EncodeCursor(cursor(rows[len(rows)-1]))
+		result.StartCursor = &start
+		result.EndCursor = &end
 	}
-	offset = max(0, beforeOffset-limit)
-	limit = min(limit, total-offset)
-	return offset, limit, nil
+	return result
 }
 
-// Encode the integer offset into a base64 string.
-func EncodeCursor(offset int) string {
-	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(offset)))
+// Set the total of the result when the page requests it.
+func (r *PageResult[T]) SetTotal(page *Page, count func() (uint64, error)) error {
+	if !page.WithTotal {
+		return nil
+	}
+	total, err := count()
+	if err != nil {
+		return err
+	}
+	r.Total = &total
+	return nil
 }
 
-// Decode the integer offset from a base64 string.
-func DecodeCursor(base64Cursor string, total int) (int, error) {
+// Encode the cursor into a base64 string.
+func EncodeCursor(cursor Cursor) string {
+	value := fmt.Sprintf("%d:%d", cursor.InputIndex, cursor.OutputIndex)
+	return base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+// Decode the cursor from a base64 string.
+func DecodeCursor(base64Cursor string) (Cursor, error) {
 	cursorBytes, err := base64.StdEncoding.DecodeString(base64Cursor)
 	if err != nil {
-		return 0, err
+		return Cursor{}, ErrInvalidCursor
+	}
+	inputIndex, outputIndex, found := strings.Cut(string(cursorBytes), ":")
+	if !found {
+		return Cursor{}, ErrInvalidCursor
 	}
-	offset, err := strconv.Atoi(string(cursorBytes))
+	var cursor Cursor
+	cursor.InputIndex, err = strconv.ParseUint(inputIndex, 10, 64)
 	if err != nil {
-		return 0, err
+		return Cursor{}, ErrInvalidCursor
 	}
-	if offset < 0 || offset >= total {
-		return 0, ErrInvalidCursor
+	cursor.OutputIndex, err = strconv.ParseUint(outputIndex, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
 	}
-	return offset, nil
+	return cursor, nil
 }